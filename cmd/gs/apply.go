@@ -0,0 +1,75 @@
+package gs
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/yggai/gs/pkg/generator"
+)
+
+// applyCmd 根据声明式资源定义批量生成代码
+var applyCmd = &cobra.Command{
+	Use:   "apply [gs.yaml]",
+	Short: "根据资源定义文件批量生成代码",
+	Long: `读取资源定义文件(默认gs.yaml)，对比当前项目计算生成计划并执行，可重复执行。
+
+文件中声明项目设置和资源列表:
+  project:
+    package: example.com/shop   # 默认从go.mod读取
+    auth: jwt                   # 生成认证模块，可选jwt、apikey
+    no_migration: false         # 生成模型时不生成建表迁移
+  resources:
+    - name: Order
+      path: /api/orders
+      fields:
+        - {name: title, type: string, required: true, size: 100}
+        - {name: email, type: string, format: email}
+        - {name: status, type: string, enum: [pending, paid]}
+        - {name: total, type: decimal, default: "0"}
+      relations:
+        - {type: belongs_to, model: User}
+      options: {soft_delete: true, pagination: true, versioned: true, auth: true, rbac: false}
+      components: [model, service, controller, route, test]   # 默认与gs create feature一致
+
+不存在的组件直接生成；资源定义变化时，重新生成上次由gs apply生成且未被修改过的文件，
+手动修改过的文件视为冲突并跳过，其余组件照常生成，有冲突时以非零状态退出。生成记录保存在gs.lock中，应与代码一起提交。
+生成模型时同时生成建表迁移，已有建表迁移时跳过，之后的字段变化用gs migrate diff生成迁移。
+
+例如:
+  gs apply
+  gs apply gs.yaml --dry-run
+  gs apply gs.yaml --force`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "gs.yaml"
+		if len(args) > 0 {
+			path = args[0]
+		}
+		
+		// 获取模板目录
+		templatesDir, err := getTemplatesDir()
+		if err != nil {
+			return err
+		}
+		
+		// 获取项目包名
+		packageName, _ := cmd.Flags().GetString("package")
+		if packageName == "" {
+			packageName = getDefaultPackage()
+		}
+		
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		
+		// 执行生成计划，有冲突时返回错误
+		g := generator.NewGenerator(templatesDir)
+		return g.Apply(path, packageName, dryRun, force)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	
+	applyCmd.Flags().String("package", "", "项目包名(默认从go.mod获取，资源定义中声明了project.package时以其为准)")
+	applyCmd.Flags().Bool("dry-run", false, "只输出生成计划，不修改文件")
+	applyCmd.Flags().Bool("force", false, "覆盖手动修改过的文件")
+}
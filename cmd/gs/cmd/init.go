@@ -32,8 +32,8 @@ func NewInitCmd() *cobra.Command {
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
 			
-			// 初始化项目
-			if err := g.InitProject(projectName, options.moduleName); err != nil {
+			// 初始化项目，不使用数据库和可选模块
+			if err := g.InitProject(projectName, options.moduleName, generator.ProjectOptions{}); err != nil {
 				return fmt.Errorf("项目初始化失败: %v", err)
 			}
 			
@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/yggai/gs/pkg/generator"
+	_ "github.com/yggai/gs/pkg/schema/sqlite"
 )
 
 // 获取默认项目包名
@@ -84,9 +85,17 @@ var createCmd = &cobra.Command{
   route       - 创建路由
   model       - 创建数据模型
   service     - 创建服务
+  repository  - 创建仓储
+  middleware  - 创建中间件
+  auth        - 创建认证模块
   example     - 创建示例代码
   test        - 创建测试代码
-  feature     - 创建完整功能集`,
+  feature     - 创建完整功能集
+  migration   - 创建数据库迁移
+  factory     - 创建测试数据工厂
+  docker      - 创建Dockerfile、docker-compose.yml和Makefile
+  deploy      - 创建Kubernetes清单或Helm chart
+  from-openapi - 根据OpenAPI文档创建功能`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 如果没有提供足够的参数，显示帮助信息
 		if len(args) < 2 {
@@ -106,6 +115,7 @@ var createCmd = &cobra.Command{
 		
 		// 创建生成器
 		g := generator.NewGenerator(templatesDir)
+		applyFeatureOptions(cmd, g)
 		
 		// 获取项目包名
 		packageName, _ := cmd.Flags().GetString("package")
@@ -130,6 +140,14 @@ var createCmd = &cobra.Command{
 			if err := g.GenerateService(componentName, packageName); err != nil {
 				fmt.Printf("错误: %v\n", err)
 			}
+		case "repository":
+			if err := g.GenerateRepository(componentName, packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		case "middleware":
+			if err := g.GenerateMiddleware(componentName, packageName, "blank"); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
 		case "example":
 			if err := g.GenerateExample(componentName, packageName); err != nil {
 				fmt.Printf("错误: %v\n", err)
@@ -142,6 +160,14 @@ var createCmd = &cobra.Command{
 			if err := g.GenerateFeature(componentName, packageName); err != nil {
 				fmt.Printf("错误: %v\n", err)
 			}
+		case "migration":
+			if err := g.GenerateMigration(componentName, packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		case "factory":
+			if err := g.GenerateFactory(componentName, packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
 		default:
 			fmt.Printf("错误：不支持的组件类型 '%s'\n", componentType)
 			cmd.Help()
@@ -154,15 +180,42 @@ func init() {
 	
 	// 为create命令添加选项
 	createCmd.PersistentFlags().String("package", "", "项目包名(默认从go.mod获取)")
+	createCmd.PersistentFlags().Bool("versioned", false, "启用乐观锁(Version列 + ETag/If-Match)")
+	createCmd.PersistentFlags().Bool("protected", false, "路由需要认证(需先执行gs create auth)")
+	createCmd.PersistentFlags().Bool("rbac", false, "按操作校验权限(生成权限常量、策略存储和RequirePermission中间件)，隐含--protected")
+	createCmd.PersistentFlags().Bool("soft-delete", false, "模型增加DeletedAt列，删除时只做标记")
+	createCmd.PersistentFlags().Bool("paginated", false, "列表接口按page和page_size分页并返回总数")
+	createCmd.PersistentFlags().Bool("no-migration", false, "生成模型时不生成建表迁移")
+	createCmd.PersistentFlags().String("dialect", "", "迁移的SQL方言: mysql、postgres或sqlite(默认读取项目配置的数据库驱动)")
 	
 	// 为create命令添加子命令
 	createCmd.AddCommand(createControllerCmd())
 	createCmd.AddCommand(createRouteCmd())
 	createCmd.AddCommand(createModelCmd())
 	createCmd.AddCommand(createServiceCmd())
+	createCmd.AddCommand(createRepositoryCmd())
+	createCmd.AddCommand(createMiddlewareCmd())
+	createCmd.AddCommand(createAuthCmd())
 	createCmd.AddCommand(createExampleCmd())
 	createCmd.AddCommand(createTestCmd())
 	createCmd.AddCommand(createFeatureCmd())
+	createCmd.AddCommand(createFromOpenAPICmd())
+	createCmd.AddCommand(createMigrationCmd())
+	createCmd.AddCommand(createFactoryCmd())
+	createCmd.AddCommand(createDockerCmd())
+	createCmd.AddCommand(createDeployCmd())
+}
+
+// applyFeatureOptions 将命令行选项应用到生成器
+func applyFeatureOptions(cmd *cobra.Command, g *generator.Generator) {
+	g.Options.Versioned, _ = cmd.Flags().GetBool("versioned")
+	g.Options.Protected, _ = cmd.Flags().GetBool("protected")
+	g.Options.RBAC, _ = cmd.Flags().GetBool("rbac")
+	g.Options.SoftDelete, _ = cmd.Flags().GetBool("soft-delete")
+	g.Options.Paginated, _ = cmd.Flags().GetBool("paginated")
+	noMigration, _ := cmd.Flags().GetBool("no-migration")
+	g.Options.Migration = !noMigration
+	g.Dialect, _ = cmd.Flags().GetString("dialect")
 }
 
 // 创建控制器命令
@@ -181,6 +234,7 @@ func createControllerCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -212,6 +266,7 @@ func createRouteCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -229,10 +284,32 @@ func createRouteCmd() *cobra.Command {
 
 // 创建模型命令
 func createModelCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "model [名称]",
 		Short: "创建数据模型",
-		Args:  cobra.ExactArgs(1),
+		Long: `创建GORM数据模型，同时在migrations目录生成建表迁移(--no-migration时不生成)。
+
+使用--from-sql时根据SQL文件中的CREATE TABLE语句(MySQL或PostgreSQL方言)生成模型，不需要名称：
+列类型、可空、默认值、主键、唯一键和索引转换为字段类型和gorm标签，外键生成关联字段，
+模型名为表名的单数形式，例如 order_items -> OrderItem。
+
+使用--from-json时根据JSON示例(例如第三方webhook的请求体)推断字段类型：整数、小数、字符串、
+RFC3339格式的时间，嵌套对象生成单独的结构体，数组生成切片，json标签保留原始键名。
+加上--dto只生成数据结构，不含gorm标签和表名。
+根据SQL文件生成的模型对应已有的表，不生成建表迁移。
+
+例如:
+  gs create model User
+  gs create model --from-sql migrations/schema.sql
+  gs create model --from-sql schema.sql --table users,orders
+  gs create model Webhook --from-json sample.json
+  gs create model StripeEvent --from-json event.json --dto`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromSQL, _ := cmd.Flags().GetString("from-sql"); fromSQL != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			// 获取模板目录
 			templatesDir, err := getTemplatesDir()
@@ -243,6 +320,7 @@ func createModelCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -250,12 +328,37 @@ func createModelCmd() *cobra.Command {
 				packageName = getDefaultPackage()
 			}
 			
+			// 根据SQL文件生成模型
+			if fromSQL, _ := cmd.Flags().GetString("from-sql"); fromSQL != "" {
+				tables, _ := cmd.Flags().GetStringSlice("table")
+				if err := g.GenerateModelsFromSQL(fromSQL, tables, packageName); err != nil {
+					fmt.Printf("错误: %v\n", err)
+				}
+				return
+			}
+			
+			// 根据JSON示例生成模型
+			if fromJSON, _ := cmd.Flags().GetString("from-json"); fromJSON != "" {
+				dto, _ := cmd.Flags().GetBool("dto")
+				if err := g.GenerateModelFromJSON(args[0], fromJSON, dto, packageName); err != nil {
+					fmt.Printf("错误: %v\n", err)
+				}
+				return
+			}
+			
 			// 生成模型
 			if err := g.GenerateModel(args[0], packageName); err != nil {
 				fmt.Printf("错误: %v\n", err)
 			}
 		},
 	}
+	
+	cmd.Flags().String("from-sql", "", "根据SQL文件中的CREATE TABLE语句生成模型")
+	cmd.Flags().StringSlice("table", nil, "只生成指定的表，多个表用逗号分隔")
+	cmd.Flags().String("from-json", "", "根据JSON示例推断字段类型生成模型")
+	cmd.Flags().Bool("dto", false, "与--from-json一起使用，只生成数据结构，不含gorm标签和表名")
+	
+	return cmd
 }
 
 // 创建服务命令
@@ -274,6 +377,7 @@ func createServiceCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -289,6 +393,146 @@ func createServiceCmd() *cobra.Command {
 	}
 }
 
+// 创建仓储命令
+func createRepositoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repository [名称]",
+		Short: "创建仓储",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 生成仓储
+			if err := g.GenerateRepository(args[0], packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+}
+
+// 创建中间件命令
+func createMiddlewareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "middleware [名称]",
+		Short: "创建中间件",
+		Long: fmt.Sprintf(`创建返回gin.HandlerFunc的中间件工厂函数、配置结构体及单元测试。
+可用的中间件类型: %s
+
+例如:
+  gs create middleware RequestID --kind=requestid --global
+  gs create middleware RateLimit --kind=ratelimit --group=User`, strings.Join(generator.MiddlewareKindNames(), ", ")),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 生成中间件
+			kind, _ := cmd.Flags().GetString("kind")
+			if err := g.GenerateMiddleware(args[0], packageName, kind); err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 注册中间件
+			global, _ := cmd.Flags().GetBool("global")
+			group, _ := cmd.Flags().GetString("group")
+			if global {
+				if err := g.RegisterMiddlewareGlobal(args[0], packageName, kind); err != nil {
+					fmt.Printf("错误: %v\n", err)
+				}
+			}
+			if group != "" {
+				if err := g.RegisterMiddlewareOnGroup(args[0], group, packageName); err != nil {
+					fmt.Printf("错误: %v\n", err)
+				}
+			}
+		},
+	}
+	
+	cmd.Flags().String("kind", "blank", "中间件类型")
+	cmd.Flags().Bool("global", false, "在main.go中全局注册")
+	cmd.Flags().String("group", "", "注册到指定资源的路由组，例如User")
+	
+	return cmd
+}
+
+// 创建认证模块命令
+func createAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "创建认证模块",
+		Long: fmt.Sprintf(`创建认证模块。
+jwt: 用户模型、注册/登录/刷新/登出接口、令牌管理及认证中间件
+apikey: API Key模型、签发/吊销管理接口及从请求头或查询参数读取Key的中间件
+生成后在config.Config和config.yaml中加入认证配置，并在routes.RegisterRoutes中注册认证路由，
+同时在migrations目录生成认证模块的建表迁移(--no-migration时不生成)
+可用的认证策略: %s
+
+例如:
+  gs create auth --strategy=jwt
+  gs create auth --strategy=apikey
+  gs create feature Order --protected`, strings.Join(generator.AuthStrategyNames(), ", ")),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 生成认证模块
+			strategy, _ := cmd.Flags().GetString("strategy")
+			if err := g.GenerateAuth(packageName, strategy); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+	
+	cmd.Flags().String("strategy", "jwt", "认证策略")
+	
+	return cmd
+}
+
 // 创建示例命令
 func createExampleCmd() *cobra.Command {
 	return &cobra.Command{
@@ -305,6 +549,7 @@ func createExampleCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -336,6 +581,7 @@ func createTestCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -353,11 +599,25 @@ func createTestCmd() *cobra.Command {
 
 // 创建完整功能命令
 func createFeatureCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "feature [名称]",
 		Short: "创建完整功能",
-		Long:  "创建完整功能集，包括模型、服务、控制器、路由、示例代码和测试代码",
-		Args:  cobra.ExactArgs(1),
+		Long: `创建完整功能集，包括模型、服务、控制器、路由、示例代码和测试代码。
+
+使用--from-db时读取数据库中的表结构，为每个表生成带关联的模型以及服务、控制器、路由和测试，
+不需要名称。目前支持 sqlite://<文件路径>；没有单列整数主键的表只生成模型，
+使用--versioned时表中需要有整数类型的version列。
+
+例如:
+  gs create feature Article
+  gs create feature --from-db sqlite://./app.db
+  gs create feature --from-db sqlite://./app.db --tables users,orders`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromDB, _ := cmd.Flags().GetString("from-db"); fromDB != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			// 获取模板目录
 			templatesDir, err := getTemplatesDir()
@@ -368,6 +628,7 @@ func createFeatureCmd() *cobra.Command {
 			
 			// 创建生成器
 			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
 			
 			// 获取项目包名
 			packageName, _ := cmd.Flags().GetString("package")
@@ -375,10 +636,252 @@ func createFeatureCmd() *cobra.Command {
 				packageName = getDefaultPackage()
 			}
 			
+			// 根据数据库表结构生成功能
+			if fromDB, _ := cmd.Flags().GetString("from-db"); fromDB != "" {
+				tables, _ := cmd.Flags().GetStringSlice("tables")
+				if err := g.GenerateFeaturesFromDB(fromDB, tables, packageName); err != nil {
+					fmt.Printf("错误: %v\n", err)
+				}
+				return
+			}
+			
 			// 生成完整功能
 			if err := g.GenerateFeature(args[0], packageName); err != nil {
 				fmt.Printf("错误: %v\n", err)
 			}
 		},
 	}
-} 
\ No newline at end of file
+	
+	cmd.Flags().String("from-db", "", "根据数据库表结构生成功能，例如 sqlite://./app.db")
+	cmd.Flags().StringSlice("tables", nil, "只生成指定的表，多个表用逗号分隔")
+	
+	return cmd
+}
+
+// 根据OpenAPI文档创建功能命令
+func createFromOpenAPICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "from-openapi [文档路径]",
+		Short: "根据OpenAPI文档创建功能",
+		Long: `读取YAML或JSON格式的OpenAPI 3文档，由components.schemas生成模型和数据结构，
+并按标签(没有标签时按路径前缀)为每个资源生成服务、控制器、路由和测试。
+集合路径上的GET/POST和 /{id} 路径上的GET/PUT/PATCH/DELETE沿用feature的命名(Get<复数>、Create<名称>等)，
+只生成文档中声明的这些接口，创建和更新绑定文档中声明的请求体(例如NewPet)，required字段映射为NOT NULL，
+其余接口生成返回501的自定义处理函数。
+
+例如:
+  gs create from-openapi api.yaml
+  gs create from-openapi api.json --versioned`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 根据文档生成功能
+			if err := g.GenerateFromOpenAPI(args[0], packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+}
+
+// 创建数据库迁移命令
+func createMigrationCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migration [名称]",
+		Short: "创建数据库迁移",
+		Long: `在migrations目录生成一对待填写的迁移文件 <版本>_<名称>.up.sql 和 <版本>_<名称>.down.sql，
+版本号为生成时的UTC时间。项目中还没有迁移执行器时同时生成migrations包和cmd/migrate命令：
+
+  go run ./cmd/migrate up              执行全部未执行的迁移
+  go run ./cmd/migrate down [n]        回滚最近执行的n个迁移
+  go run ./cmd/migrate status          查看迁移状态
+  go run ./cmd/migrate to <version>    迁移到指定版本
+
+已执行的版本记录在schema_migrations表中。SQL方言由--dialect指定，默认读取项目配置的数据库驱动。
+
+例如:
+  gs create migration add_index_to_users
+  gs create migration AddStatusToOrders --dialect postgres`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 生成迁移
+			if err := g.GenerateMigration(args[0], packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+}
+
+// 创建测试数据工厂命令
+func createFactoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "factory [名称]",
+		Short: "创建测试数据工厂",
+		Long: `根据models目录中的模型在factories目录生成测试数据工厂，按字段名和类型生成假数据
+(姓名、邮箱、手机号、网址、金额、时间等)，使用相同种子创建的工厂生成相同的数据：
+
+  factory := factories.NewUserFactory(db, factories.DefaultSeed)
+  user := factory.Build(func(u *models.User) { u.Name = "admin" })  // 不保存
+  users, err := factory.CreateList(10)                             // 保存到数据库
+
+主键、时间戳、有默认值的字段和外键保持零值。模型修改后删除工厂文件重新生成即可。
+项目中还没有cmd/seed命令时一并生成，用于将fixtures目录中的YAML或JSON夹具加载到数据库：
+
+  go run ./cmd/seed                      加载fixtures目录中的全部夹具
+  go run ./cmd/seed fixtures/users.yaml  加载指定的文件或目录
+
+夹具文件名为表名，内容为记录列表，按模型的belongs to关联排序，被引用的表先加载，
+主键已存在的记录会被更新。只能加载已生成工厂的模型。
+
+例如:
+  gs create factory User`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 创建生成器
+			g := generator.NewGenerator(templatesDir)
+			applyFeatureOptions(cmd, g)
+			
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+			
+			// 生成工厂
+			if err := g.GenerateFactory(args[0], packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+}
+
+// 创建Docker和Makefile命令
+func createDockerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker",
+		Short: "创建Dockerfile、docker-compose.yml和Makefile",
+		Long: `在项目根目录生成Docker和Makefile文件，内容按项目中实际存在的文件生成:
+
+  Dockerfile          多阶段构建，运行阶段使用distroless镜像并以非root用户运行；
+                      SQLite依赖cgo，使用distroless/base，数据库文件保存在/data卷中，
+                      其他情况静态编译并使用distroless/static；有cmd/migrate时一并编译迁移命令
+  docker-compose.yml  应用和配置的数据库(PostgreSQL或MySQL)，数据库就绪后启动应用；
+                      有cmd/migrate时先执行迁移，有tracing包时启动Jaeger接收链路追踪
+  .dockerignore       不发送到构建上下文的文件
+  Makefile            build、run、lint、tidy、clean和docker-*目标，有测试时生成test，
+                      有cmd/migrate时生成migrate、migrate-down和migrate-status，有cmd/seed时生成seed
+
+任一文件已存在时不生成任何文件，使用--force覆盖，例如增加迁移后重新生成。
+
+例如:
+  gs create docker
+  gs create docker --force`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 生成Docker和Makefile文件
+			force, _ := cmd.Flags().GetBool("force")
+			g := generator.NewGenerator(templatesDir)
+			if err := g.GenerateDocker(".", force); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+	
+	cmd.Flags().Bool("force", false, "覆盖已存在的文件")
+	
+	return cmd
+}
+
+// 创建Kubernetes部署文件命令
+func createDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "创建Kubernetes清单或Helm chart",
+		Long: `在deploy目录生成部署文件，--target选择生成的内容:
+
+  k8s   deploy/k8s: Deployment、Service、ConfigMap、HPA、Ingress和kustomization.yaml
+  helm  deploy/helm/<项目名称>: 包含相同资源的Helm chart，副本数、镜像、资源配额、
+        Ingress和自动伸缩等通过values.yaml配置
+
+资源名称和镜像取自项目名称，容器端口读取config/config.go中的默认端口(配置文件中的端口优先)，
+终止宽限期比server.shutdown_timeout多5秒；有health包时存活/就绪探针使用/livez和/readyz，
+否则使用/health；有metrics包时Pod增加Prometheus抓取注解。
+配置通过APP_前缀的环境变量注入，MySQL和PostgreSQL的密码从Secret <名称>-db读取。
+SQLite的数据库文件保存在PVC中，只能单副本运行，因此生成PVC而不生成HPA。
+
+任一文件已存在时不生成任何文件，使用--force覆盖。生成后可用gs deploy lint离线校验。
+
+例如:
+  gs create deploy
+  gs create deploy --target=helm
+  gs create deploy --target=k8s --force`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			// 生成部署文件
+			target, _ := cmd.Flags().GetString("target")
+			force, _ := cmd.Flags().GetBool("force")
+			g := generator.NewGenerator(templatesDir)
+			if err := g.GenerateDeploy(".", target, force); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+	
+	cmd.Flags().String("target", "k8s", "部署目标: k8s或helm")
+	cmd.Flags().Bool("force", false, "覆盖已存在的文件")
+	
+	return cmd
+}
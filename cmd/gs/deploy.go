@@ -0,0 +1,72 @@
+package gs
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yggai/gs/pkg/deploy"
+)
+
+// deployCmd 部署相关命令
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "部署相关命令",
+	Long: `部署相关命令。部署文件由gs create deploy生成到deploy目录。
+
+可用的子命令:
+  lint  - 离线校验Kubernetes清单和Helm chart`,
+}
+
+// 离线校验部署文件命令
+func deployLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [目录]",
+		Short: "离线校验Kubernetes清单和Helm chart",
+		Long: `校验目录(默认deploy)中的Kubernetes清单和Helm chart，不需要连接集群，也不需要安装kubectl或helm:
+
+  - YAML格式、apiVersion、kind和metadata.name，名称格式
+  - Deployment的选择器与Pod标签一致，容器端口、探针端口和路径，环境变量的值为字符串，
+    引用的ConfigMap、PVC和挂载的卷存在，资源配额格式正确且requests不大于limits
+  - Service的选择器匹配Deployment的Pod，targetPort是容器端口
+  - HPA的伸缩目标存在，副本数范围正确，按CPU伸缩时容器设置了CPU请求量
+  - Ingress的后端Service和端口存在，path和pathType正确
+  - ConfigMap的值为字符串，kustomization.yaml中列出的文件存在
+
+包含Chart.yaml的目录按Helm chart校验: 检查Chart.yaml，用values.yaml渲染templates中的模板后再校验渲染结果，
+只支持常用的Helm模板函数。发现问题时输出所有问题并以非零状态退出，可用于CI。
+
+例如:
+  gs deploy lint
+  gs deploy lint deploy/helm`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "deploy"
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			
+			issues, err := deploy.Lint(dir)
+			if err != nil {
+				return err
+			}
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("发现%d个问题", len(issues))
+			}
+			
+			fmt.Printf("校验通过: %s\n", dir)
+			return nil
+		},
+	}
+	
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(deployCmd)
+	
+	deployCmd.AddCommand(deployLintCmd())
+}
@@ -0,0 +1,82 @@
+package gs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yggai/gs/pkg/openapi"
+)
+
+// docsCmd 生成项目文档
+var docsCmd = &cobra.Command{
+	Use:   "docs [文档类型]",
+	Short: "生成项目文档",
+	Long: `根据项目源码生成文档。
+可用的文档类型:
+  openapi     - 生成OpenAPI 3接口文档`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	
+	// 为docs命令添加子命令
+	docsCmd.AddCommand(docsOpenAPICmd())
+}
+
+// 生成OpenAPI文档命令
+func docsOpenAPICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "生成OpenAPI 3接口文档",
+		Long: `扫描routes/*_routes.go和控制器，推断路由、请求体、参数和响应，生成openapi.yaml。
+处理函数的注释中可使用注解覆盖推断结果:
+  // @Summary 获取订单列表
+  // @Description 按创建时间倒序
+  // @Tags 订单
+  // @Response 200 订单列表
+  // @Deprecated
+
+存在@Response注解时，只输出注解中列出的状态码。`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, _ := cmd.Flags().GetString("dir")
+			output, _ := cmd.Flags().GetString("output")
+			title, _ := cmd.Flags().GetString("title")
+			apiVersion, _ := cmd.Flags().GetString("api-version")
+			
+			// 扫描项目生成文档
+			doc, err := openapi.Generate(dir, openapi.Options{
+				Title:   title,
+				Version: apiVersion,
+			})
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			content, err := openapi.Marshal(doc)
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			
+			if err := os.WriteFile(output, content, 0644); err != nil {
+				fmt.Printf("错误: 无法写入文档: %v\n", err)
+				return
+			}
+			
+			fmt.Printf("已生成OpenAPI文档: %s (%d个路径)\n", output, len(doc.Paths))
+		},
+	}
+	
+	cmd.Flags().String("dir", ".", "项目根目录")
+	cmd.Flags().StringP("output", "o", "openapi.yaml", "输出文件")
+	cmd.Flags().String("title", "", "文档标题(默认取模块名)")
+	cmd.Flags().String("api-version", "1.0.0", "接口版本")
+	
+	return cmd
+}
@@ -3,109 +3,196 @@ package gs
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yggai/gs/pkg/generator"
 )
 
 // initCmd 初始化新的Gin项目
 var initCmd = &cobra.Command{
 	Use:   "init [项目名称]",
 	Short: "创建一个新的Gin项目",
-	Long:  `创建一个新的Gin项目，包含标准的目录结构和基础配置文件。`,
-	Args:  cobra.ExactArgs(1),
+	Long: `创建一个新的Gin项目，包含配置、路由和启动代码。
+
+--db 选择数据库驱动(sqlite、mysql、postgres或none，默认sqlite，无需外部服务即可运行)，
+使用数据库时生成database包：按配置打开连接并设置连接池，启动时检查连接并重试，
+连接通过RegisterRoutes传给各资源的仓储，/readyz检查数据库连接，关闭服务时关闭连接。
+
+生成的server包按配置设置读写和空闲超时，收到SIGINT或SIGTERM时/readyz立即返回503，
+在shutdown_timeout内等待处理中的请求完成，再按注册的相反顺序执行关闭钩子。
+
+生成的health包管理命名的健康检查(数据库、磁盘空间、依赖的HTTP服务等)，/livez执行存活检查，
+/readyz执行所有检查，每个检查有超时时间并缓存结果，响应体列出每个检查的状态和耗时。
+
+生成的logger包基于log/slog，按log配置输出json或text格式的日志，请求日志中间件记录
+method、path、status、latency和请求ID，请求ID随上下文传给服务，用slog.InfoContext(ctx, ...)记录的日志都带有请求ID。
+
+生成的config包读取config.yaml(或.toml、.json)，APP_ENV选择profile(例如config.prod.yaml)，
+APP_前缀的环境变量覆盖任意配置项(例如APP_SERVER_PORT)，配置文件中可以用${VAR}引用环境变量，
+启动时校验配置并一次报告所有无效的配置项。
+
+--config-reload 生成config.Watcher：定期检查配置文件，变化时重新加载，校验通过后原子地替换配置，
+并调用通过OnChange订阅的回调，适合功能开关、限流阈值等无需重启即可生效的配置。
+
+--with 启用可选模块，多个用逗号分隔：
+  metrics  生成metrics包：/metrics以Prometheus文本格式输出指标，中间件按路由模板记录
+           请求数、耗时直方图和并发数，服务通过metrics.NewCounter等函数注册自定义指标
+  tracing  生成tracing包：按tracing配置使用OTLP/HTTP或stdout导出器，中间件按traceparent
+           请求头延续上游的追踪并创建服务端span；之后用gs create生成的服务和仓储方法
+           接收context.Context并创建子span
+  docker   生成多阶段构建的Dockerfile(distroless镜像)、连接配置的数据库的docker-compose.yml、
+           .dockerignore和Makefile，之后增加迁移等命令时用gs create docker --force重新生成
+
+--auth 生成认证模块(jwt或apikey)及其建表迁移，认证配置写入config.yaml并在启动时校验，jwt的签名密钥
+随机生成，只用于本地开发，生产环境通过AUTH_SECRET环境变量设置。--example 按gs create feature生成
+示例资源(模型、服务、控制器、路由、测试、建表迁移和examples/<名称>中的示例)，多个用逗号分隔。
+认证路由和示例资源的路由在routes.RegisterRoutes中注册，两者都需要数据库，启动前用go run ./cmd/migrate up
+创建表。--log-level和--log-format设置默认的日志级别和格式。
+
+交互式向导: 不指定项目名称或使用--interactive时，逐项询问项目名称、模块路径、可选的包、数据库、认证、
+日志、Docker和示例资源，每项都有默认值并在输入后校验，确认概要后生成，生成方式与使用标志时相同。
+向导的回答可以保存为项目规格文件，之后用--spec复用；--spec也可以直接使用，命令行中指定的标志优先于文件中的值，
+与--interactive一起使用时文件中的值作为向导的默认值。
+
+例如:
+  gs init
+  gs init myapp
+  gs init myapp --module github.com/username/myapp --db=postgres
+  gs init myapp --db=none
+  gs init myapp --with=metrics,tracing
+  gs init myapp --db=postgres --with=docker
+  gs init myapp --auth=jwt --example=product,order --log-format=json
+  gs init myapp --db=mysql --save-spec=gs-init.yaml
+  gs init other --spec=gs-init.yaml
+  gs init --spec=gs-init.yaml --interactive`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
+		// 读取项目规格文件
+		spec := generator.ProjectSpec{}
+		if path, _ := cmd.Flags().GetString("spec"); path != "" {
+			loaded, err := generator.LoadProjectSpec(path)
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			spec = *loaded
+		}
+		if len(args) > 0 {
+			spec.Module = renameModule(spec.Module, spec.Name, args[0])
+			spec.Name = args[0]
+		}
+		applyInitFlags(cmd, &spec)
+		
+		// 没有项目名称或使用--interactive时进入交互式向导
+		savePath, _ := cmd.Flags().GetString("save-spec")
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive || spec.Name == "" {
+			ok, path, err := runInitWizard(os.Stdin, os.Stdout, &spec, savePath)
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+			if !ok {
+				fmt.Println("已取消")
+				return
+			}
+			savePath = path
+		}
 		
 		// 验证项目名称
-		if !isValidProjectName(projectName) {
+		if !isValidProjectName(spec.Name) {
 			fmt.Println("错误：项目名称只能包含字母、数字、下划线和连字符")
 			return
 		}
 		
 		// 检查目录是否已存在
-		if _, err := os.Stat(projectName); !os.IsNotExist(err) {
-			fmt.Printf("错误：目录 '%s' 已存在\n", projectName)
+		if _, err := os.Stat(spec.Name); !os.IsNotExist(err) {
+			fmt.Printf("错误：目录 '%s' 已存在\n", spec.Name)
 			return
 		}
 		
-		// 创建项目目录
-		fmt.Printf("创建项目 '%s'\n", projectName)
-		if err := os.MkdirAll(projectName, 0755); err != nil {
-			fmt.Printf("错误：无法创建项目目录: %v\n", err)
+		if err := spec.Validate(); err != nil {
+			fmt.Printf("错误: %v\n", err)
 			return
 		}
 		
-		// 创建项目结构
-		directories := []string{
-			"config",
-			"controllers",
-			"middlewares",
-			"models",
-			"services",
-			"routes",
-			"utils",
-			"tests",
-			"examples",
-		}
-		
-		for _, dir := range directories {
-			path := filepath.Join(projectName, dir)
-			if err := os.MkdirAll(path, 0755); err != nil {
-				fmt.Printf("错误：创建目录失败 %s: %v\n", path, err)
+		// 保存项目规格
+		if savePath != "" {
+			if err := spec.Save(savePath); err != nil {
+				fmt.Printf("错误: %v\n", err)
 				return
 			}
+			fmt.Printf("已保存项目规格: %s\n", savePath)
 		}
 		
-		// 创建go.mod文件
-		goModPath := filepath.Join(projectName, "go.mod")
-		modContent := fmt.Sprintf("module %s\n\ngo 1.16\n", projectName)
-		if err := os.WriteFile(goModPath, []byte(modContent), 0644); err != nil {
-			fmt.Printf("错误：无法创建go.mod文件: %v\n", err)
+		// 获取模板目录
+		templatesDir, err := getTemplatesDir()
+		if err != nil {
+			fmt.Printf("错误: %v\n", err)
 			return
 		}
 		
-		// 创建main.go文件
-		mainPath := filepath.Join(projectName, "main.go")
-		mainContent := `package main
-
-import (
-	"fmt"
-	"log"
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-)
-
-func main() {
-	// 创建Gin引擎
-	r := gin.Default()
-
-	// 注册路由
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "ok",
-		})
-	})
-
-	// 启动服务器
-	fmt.Println("服务器启动在 :8080 端口...")
-	if err := r.Run(":8080"); err != nil {
-		log.Fatalf("服务器启动失败: %v", err)
-	}
-}
-`
-		if err := os.WriteFile(mainPath, []byte(mainContent), 0644); err != nil {
-			fmt.Printf("错误：无法创建main.go文件: %v\n", err)
+		// 创建项目
+		fmt.Printf("创建项目 '%s'\n", spec.Name)
+		g := generator.NewGenerator(templatesDir)
+		if err := g.InitFromSpec(spec); err != nil {
+			fmt.Printf("错误: %v\n", err)
 			return
 		}
 		
-		fmt.Println("项目创建成功！")
-		fmt.Println("安装依赖:")
-		fmt.Printf("cd %s && go mod tidy\n", projectName)
+		fmt.Println("安装依赖并启动:")
+		if spec.Auth == "" && len(spec.Examples) == 0 {
+			fmt.Printf("cd %s && go mod tidy && go run .\n", spec.Name)
+			return
+		}
+		
+		// 认证模块和示例资源的表由迁移创建
+		migrate := "go run ./cmd/migrate up"
+		if spec.Database != "sqlite" {
+			migrate = "DATABASE_DSN=<数据库连接> " + migrate
+		}
+		fmt.Printf("cd %s && go mod tidy && %s && go run .\n", spec.Name, migrate)
 	},
 }
 
+// renameModule 项目名称改变时，以原项目名称结尾的模块路径随之改变，
+// 例如复用规格文件时 github.com/username/shop 变为 github.com/username/newname
+func renameModule(module, oldName, newName string) string {
+	if oldName == "" || (module != oldName && !strings.HasSuffix(module, "/"+oldName)) {
+		return module
+	}
+	return strings.TrimSuffix(module, oldName) + newName
+}
+
+// applyInitFlags 将命令行中指定的标志写入项目规格，未指定的标志不覆盖规格文件中的值
+func applyInitFlags(cmd *cobra.Command, spec *generator.ProjectSpec) {
+	flags := cmd.Flags()
+	if flags.Changed("module") {
+		spec.Module, _ = flags.GetString("module")
+	}
+	if flags.Changed("db") || spec.Database == "" {
+		spec.Database, _ = flags.GetString("db")
+	}
+	if flags.Changed("config-reload") {
+		spec.ConfigReload, _ = flags.GetBool("config-reload")
+	}
+	if flags.Changed("with") {
+		spec.With, _ = flags.GetStringSlice("with")
+	}
+	if flags.Changed("auth") {
+		spec.Auth, _ = flags.GetString("auth")
+	}
+	if flags.Changed("log-level") {
+		spec.LogLevel, _ = flags.GetString("log-level")
+	}
+	if flags.Changed("log-format") {
+		spec.LogFormat, _ = flags.GetString("log-format")
+	}
+	if flags.Changed("example") {
+		spec.Examples, _ = flags.GetStringSlice("example")
+	}
+}
+
 // isValidProjectName 检查项目名称是否有效
 func isValidProjectName(name string) bool {
 	// 项目名称只允许字母、数字、下划线和连字符
@@ -122,4 +209,16 @@ func isValidProjectName(name string) bool {
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	
+	initCmd.Flags().StringP("module", "m", "", "Go模块名称(默认与项目名称相同)")
+	initCmd.Flags().String("db", "sqlite", "数据库驱动: sqlite、mysql、postgres或none")
+	initCmd.Flags().Bool("config-reload", false, "生成配置文件热加载(轮询配置文件，变化时重新加载并通知订阅者)")
+	initCmd.Flags().StringSlice("with", nil, fmt.Sprintf("启用的可选模块，多个用逗号分隔: %s", strings.Join(generator.ProjectFeatureNames(), ", ")))
+	initCmd.Flags().String("auth", "", fmt.Sprintf("生成认证模块: %s", strings.Join(generator.AuthStrategyNames(), "或")))
+	initCmd.Flags().String("log-level", "info", fmt.Sprintf("默认日志级别: %s", strings.Join(generator.LogLevels, "、")))
+	initCmd.Flags().String("log-format", "text", fmt.Sprintf("默认日志格式: %s", strings.Join(generator.LogFormats, "或")))
+	initCmd.Flags().StringSlice("example", nil, "生成的示例资源，多个用逗号分隔，例如 product,order")
+	initCmd.Flags().BoolP("interactive", "i", false, "使用交互式向导，不指定项目名称时自动使用")
+	initCmd.Flags().String("spec", "", "从项目规格文件读取设置")
+	initCmd.Flags().String("save-spec", "", "将项目设置保存为项目规格文件，供--spec复用")
 } 
\ No newline at end of file
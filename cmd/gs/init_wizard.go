@@ -0,0 +1,325 @@
+package gs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yggai/gs/pkg/generator"
+)
+
+// prompter 逐行读取交互式问题的回答
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask 提问并返回回答，直接回车时使用默认值def，validate返回错误时提示并重新提问
+func (p *prompter) ask(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			fmt.Fprintln(p.out)
+			return "", fmt.Errorf("输入已结束，向导未完成")
+		}
+	
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// choose 列出选项及说明后提问，可以输入选项或序号
+func (p *prompter) choose(question string, options []string, descriptions map[string]string, def string) (string, error) {
+	for i, option := range options {
+		if description := descriptions[option]; description != "" {
+			fmt.Fprintf(p.out, "  %d) %-8s %s\n", i+1, option, description)
+		} else {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+		}
+	}
+	answer, err := p.ask(question, def, func(answer string) error {
+		if choice(answer, options) == "" {
+			return fmt.Errorf("请输入%s之一或序号", strings.Join(options, "、"))
+		}
+		return nil
+	})
+	return choice(answer, options), err
+}
+
+// choice 返回回答对应的选项，回答可以是选项(忽略大小写)或从1开始的序号，无效时返回空
+func choice(answer string, options []string) string {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		return options[n-1]
+	}
+	for _, option := range options {
+		if strings.EqualFold(answer, option) {
+			return option
+		}
+	}
+	return ""
+}
+
+// confirm 提出是/否问题
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := p.ask(fmt.Sprintf("%s (%s)", question, hint), "", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("请输入y或n")
+	})
+	if err != nil || answer == "" {
+		return def, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// splitList 拆分逗号分隔的回答
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runInitWizard 逐项询问项目设置，spec中已有的值(来自--spec和命令行标志)作为默认值。
+// 确认概要后返回true，savePath为保存项目规格的文件，为空时不保存
+func runInitWizard(in io.Reader, out io.Writer, spec *generator.ProjectSpec, savePath string) (bool, string, error) {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+	fmt.Fprintln(out, "创建新的Gin项目，直接回车使用方括号中的默认值。")
+	
+	// 项目名称和模块路径
+	fmt.Fprintln(out, "\n[1/8] 项目名称")
+	name := spec.Name
+	if name == "" {
+		name = "myapp"
+	}
+	name, err := p.ask("项目名称(也是项目目录)", name, func(answer string) error {
+		if answer == "" || !isValidProjectName(answer) {
+			return fmt.Errorf("项目名称只能包含字母、数字、下划线和连字符")
+		}
+		if _, err := os.Stat(answer); !os.IsNotExist(err) {
+			return fmt.Errorf("目录 '%s' 已存在", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, "", err
+	}
+	module := renameModule(spec.Module, spec.Name, name)
+	if module == "" {
+		module = name
+	}
+	spec.Name = name
+	
+	fmt.Fprintln(out, "\n[2/8] Go模块路径")
+	if spec.Module, err = p.ask("模块路径(例如 github.com/username/"+name+")", module, generator.ValidateModulePath); err != nil {
+		return false, "", err
+	}
+	
+	// 项目结构: 分层目录固定生成，询问可选的包
+	fmt.Fprintln(out, "\n[3/8] 项目结构")
+	fmt.Fprintln(out, "  始终生成config、logger、server、health、routes包，gs create生成的代码位于controllers、services、repositories和models。")
+	fmt.Fprintln(out, "  可选的包:")
+	var packages []string
+	for _, feature := range generator.ProjectFeatureNames() {
+		if feature == "docker" {
+			continue
+		}
+		packages = append(packages, feature)
+		fmt.Fprintf(out, "    %-8s %s\n", feature, generator.ProjectFeatures[feature])
+	}
+	docker := (generator.ProjectOptions{With: spec.With}).Enabled("docker")
+	var enabled []string
+	for _, feature := range spec.With {
+		if feature != "docker" {
+			enabled = append(enabled, feature)
+		}
+	}
+	answer, err := p.ask("启用的包(逗号分隔，输入none不启用)", strings.Join(enabled, ","), func(answer string) error {
+		for _, feature := range splitList(answer) {
+			if feature != "none" && choice(feature, packages) == "" {
+				return fmt.Errorf("不支持的包 '%s'，可选: %s", feature, strings.Join(packages, "、"))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, "", err
+	}
+	spec.With = nil
+	for _, feature := range splitList(answer) {
+		if feature != "none" {
+			spec.With = append(spec.With, choice(feature, packages))
+		}
+	}
+	if spec.ConfigReload, err = p.confirm("生成配置文件热加载(config.Watcher)?", spec.ConfigReload); err != nil {
+		return false, "", err
+	}
+	
+	// 数据库
+	fmt.Fprintln(out, "\n[4/8] 数据库")
+	database := spec.Database
+	if database == "" {
+		database = "sqlite"
+	}
+	spec.Database, err = p.choose("数据库", []string{"sqlite", "mysql", "postgres", "none"}, map[string]string{
+		"sqlite":   "无需外部服务即可运行(cgo)",
+		"mysql":    "MySQL",
+		"postgres": "PostgreSQL",
+		"none":     "不使用数据库，不能生成认证模块和示例资源",
+	}, database)
+	if err != nil {
+		return false, "", err
+	}
+	
+	// 认证模块，仓储基于GORM，需要数据库
+	fmt.Fprintln(out, "\n[5/8] 认证")
+	if spec.Database == "none" {
+		fmt.Fprintln(out, "  不使用数据库，跳过")
+		spec.Auth = ""
+	} else {
+		auth := spec.Auth
+		if auth == "" {
+			auth = "none"
+		}
+		descriptions := map[string]string{"none": "不生成认证模块，之后可用gs create auth生成"}
+		for name, description := range generator.AuthStrategies {
+			descriptions[name] = description
+		}
+		if spec.Auth, err = p.choose("认证策略", append([]string{"none"}, generator.AuthStrategyNames()...), descriptions, auth); err != nil {
+			return false, "", err
+		}
+		if spec.Auth == "none" {
+			spec.Auth = ""
+		}
+	}
+	
+	// 日志
+	fmt.Fprintln(out, "\n[6/8] 日志")
+	level := spec.LogLevel
+	if level == "" {
+		level = "info"
+	}
+	if spec.LogLevel, err = p.choose("默认日志级别", generator.LogLevels, nil, level); err != nil {
+		return false, "", err
+	}
+	format := spec.LogFormat
+	if format == "" {
+		format = "text"
+	}
+	spec.LogFormat, err = p.choose("日志格式", generator.LogFormats, map[string]string{
+		"text": "便于本地阅读",
+		"json": "便于日志系统采集，建议生产环境使用",
+	}, format)
+	if err != nil {
+		return false, "", err
+	}
+	
+	// Docker
+	fmt.Fprintln(out, "\n[7/8] Docker")
+	if docker, err = p.confirm("生成Dockerfile、docker-compose.yml和Makefile?", docker); err != nil {
+		return false, "", err
+	}
+	if docker {
+		spec.With = append(spec.With, "docker")
+	}
+	
+	// 示例资源，按gs create feature生成
+	fmt.Fprintln(out, "\n[8/8] 示例资源")
+	if spec.Database == "none" {
+		fmt.Fprintln(out, "  不使用数据库，跳过")
+		spec.Examples = nil
+	} else {
+		fmt.Fprintln(out, "  每个资源生成模型、服务、控制器、路由、测试和建表迁移，与gs create feature相同")
+		answer, err := p.ask("资源名称(逗号分隔，例如 product,order，输入none不生成)", strings.Join(spec.Examples, ","), func(answer string) error {
+			if answer == "none" {
+				return nil
+			}
+			return (&generator.ProjectSpec{Name: name, Examples: splitList(answer)}).Validate()
+		})
+		if err != nil {
+			return false, "", err
+		}
+		spec.Examples = nil
+		if answer != "none" {
+			spec.Examples = splitList(answer)
+		}
+	}
+	
+	if err := spec.Validate(); err != nil {
+		return false, "", err
+	}
+	printProjectSpec(out, spec)
+	
+	ok, err := p.confirm("\n确认生成?", true)
+	if err != nil || !ok {
+		return false, "", err
+	}
+	savePath, err = p.ask("保存为项目规格文件，之后用gs init --spec复用(输入文件名，留空或输入none不保存)", savePath, nil)
+	if err != nil || savePath == "none" {
+		return true, "", err
+	}
+	return true, savePath, nil
+}
+
+// printProjectSpec 输出项目设置的概要
+func printProjectSpec(out io.Writer, spec *generator.ProjectSpec) {
+	orNone := func(items []string) string {
+		if len(items) == 0 {
+			return "无"
+		}
+		return strings.Join(items, "、")
+	}
+	var packages []string
+	for _, feature := range spec.With {
+		if feature != "docker" {
+			packages = append(packages, feature)
+		}
+	}
+	if spec.ConfigReload {
+		packages = append(packages, "配置热加载")
+	}
+	auth := spec.Auth
+	if auth == "" {
+		auth = "无"
+	}
+	docker := "否"
+	if (generator.ProjectOptions{With: spec.With}).Enabled("docker") {
+		docker = "是"
+	}
+	
+	fmt.Fprintln(out, "\n项目概要:")
+	fmt.Fprintf(out, "  项目名称  %s\n", spec.Name)
+	fmt.Fprintf(out, "  模块路径  %s\n", spec.Module)
+	fmt.Fprintf(out, "  可选的包  %s\n", orNone(packages))
+	fmt.Fprintf(out, "  数据库    %s\n", spec.Database)
+	fmt.Fprintf(out, "  认证      %s\n", auth)
+	fmt.Fprintf(out, "  日志      %s级别，%s格式\n", spec.LogLevel, spec.LogFormat)
+	fmt.Fprintf(out, "  Docker    %s\n", docker)
+	fmt.Fprintf(out, "  示例资源  %s\n", orNone(spec.Examples))
+}
@@ -0,0 +1,160 @@
+package gs
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/generator"
+)
+
+// wizard 在临时目录中用给定的回答运行向导，每个回答占一行，返回输出
+func wizard(t *testing.T, spec *generator.ProjectSpec, savePath string, answers ...string) (bool, string, string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	original, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	require.NoError(t, os.Chdir(dir), "无法切换到临时目录")
+	t.Cleanup(func() { os.Chdir(original) })
+	require.NoError(t, os.Mkdir("taken", 0755), "无法创建目录")
+	
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(answers, "\n") + "\n")
+	ok, path, err := runInitWizard(in, &out, spec, savePath)
+	return ok, path, out.String(), err
+}
+
+// 测试全部使用默认值
+func TestRunInitWizardDefaults(t *testing.T) {
+	spec := &generator.ProjectSpec{}
+	ok, path, out, err := wizard(t, spec, "",
+		"", // 项目名称
+		"", // 模块路径
+		"", // 可选的包
+		"", // 配置热加载
+		"", // 数据库
+		"", // 认证
+		"", // 日志级别
+		"", // 日志格式
+		"", // Docker
+		"", // 示例资源
+		"", // 确认
+		"", // 保存项目规格
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, path, "留空时不保存项目规格")
+	assert.Equal(t, &generator.ProjectSpec{
+		Name:      "myapp",
+		Module:    "myapp",
+		Database:  "sqlite",
+		LogLevel:  "info",
+		LogFormat: "text",
+	}, spec)
+	assert.Contains(t, out, "项目概要:")
+}
+
+// 测试逐项回答，无效的回答提示后重新询问
+func TestRunInitWizardAnswers(t *testing.T) {
+	spec := &generator.ProjectSpec{}
+	ok, path, out, err := wizard(t, spec, "",
+		"bad name", "taken", "shop",
+		"github.com/a b", "github.com/username/shop",
+		"cache", "metrics",
+		"y",
+		"oracle", "3",
+		"JWT",
+		"debug",
+		"2",
+		"maybe", "y",
+		"1st", "product,order",
+		"y",
+		"gs-init.yaml",
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "gs-init.yaml", path)
+	assert.Equal(t, &generator.ProjectSpec{
+		Name:         "shop",
+		Module:       "github.com/username/shop",
+		Database:     "postgres",
+		ConfigReload: true,
+		With:         []string{"metrics", "docker"},
+		Auth:         "jwt",
+		LogLevel:     "debug",
+		LogFormat:    "json",
+		Examples:     []string{"product", "order"},
+	}, spec)
+	
+	for _, message := range []string{
+		"项目名称只能包含字母、数字、下划线和连字符",
+		"目录 'taken' 已存在",
+		"模块路径 'github.com/a b' 无效",
+		"不支持的包 'cache'",
+		"请输入sqlite、mysql、postgres、none之一或序号",
+		"请输入y或n",
+		"示例资源名称 '1st'",
+	} {
+		assert.Contains(t, out, message, "无效的回答应提示原因")
+	}
+}
+
+// 测试已有的设置作为默认值，项目名称改变时模块路径随之改变
+func TestRunInitWizardSpecDefaults(t *testing.T) {
+	spec := &generator.ProjectSpec{
+		Name:      "shop",
+		Module:    "github.com/username/shop",
+		Database:  "mysql",
+		With:      []string{"docker", "tracing"},
+		Auth:      "apikey",
+		LogLevel:  "warn",
+		LogFormat: "json",
+		Examples:  []string{"product"},
+	}
+	ok, path, _, err := wizard(t, spec, "gs-init.yaml", "other", "", "", "", "", "", "", "", "", "", "", "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "gs-init.yaml", path, "--save-spec的文件作为默认值")
+	assert.Equal(t, &generator.ProjectSpec{
+		Name:      "other",
+		Module:    "github.com/username/other",
+		Database:  "mysql",
+		With:      []string{"tracing", "docker"},
+		Auth:      "apikey",
+		LogLevel:  "warn",
+		LogFormat: "json",
+		Examples:  []string{"product"},
+	}, spec)
+}
+
+// 测试不使用数据库时跳过认证和示例资源
+func TestRunInitWizardWithoutDatabase(t *testing.T) {
+	spec := &generator.ProjectSpec{Auth: "jwt", Examples: []string{"product"}}
+	ok, path, out, err := wizard(t, spec, "gs-init.yaml",
+		"", "", "", "",
+		"none",
+		"", "", "",
+		"", "none",
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, path, "输入none时不保存项目规格")
+	assert.Equal(t, "none", spec.Database)
+	assert.Empty(t, spec.Auth, "不使用数据库时不生成认证模块")
+	assert.Empty(t, spec.Examples, "不使用数据库时不生成示例资源")
+	assert.Equal(t, 2, strings.Count(out, "不使用数据库，跳过"))
+}
+
+// 测试取消和输入提前结束
+func TestRunInitWizardCancel(t *testing.T) {
+	ok, _, _, err := wizard(t, &generator.ProjectSpec{}, "", "", "", "", "", "", "", "", "", "", "", "n")
+	require.NoError(t, err)
+	assert.False(t, ok, "不确认时不生成项目")
+	
+	ok, _, _, err = wizard(t, &generator.ProjectSpec{}, "", "shop")
+	assert.EqualError(t, err, "输入已结束，向导未完成")
+	assert.False(t, ok)
+}
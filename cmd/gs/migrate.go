@@ -0,0 +1,81 @@
+package gs
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yggai/gs/pkg/generator"
+)
+
+// migrateCmd 数据库迁移相关命令
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "数据库迁移",
+	Long: `数据库迁移相关命令。迁移文件保存在migrations目录，由项目中生成的cmd/migrate命令执行。
+
+可用的子命令:
+  diff  - 根据模型的变化生成迁移`,
+}
+
+// 比较模型与表结构快照生成迁移命令
+func migrateDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [名称]",
+		Short: "根据模型的变化生成迁移",
+		Long: `用go/ast解析models目录中的模型(定义了TableName方法或嵌入gorm.Model的结构体)，
+按gorm标签得到表结构，与migrations/schema.json中记录的上次表结构快照比较，
+生成新建/删除表、增加/删除/修改列以及索引变化的迁移，并更新快照。
+
+删除表或列、修改列类型、改为非空等可能丢失数据的变更在迁移文件开头和命令输出中标出，执行前请检查。
+无法区分重命名和删除后新增，重命名的列会生成删除和新增两项变更。
+SQLite不支持修改列，修改列时通过重建表完成。
+
+快照由gs create model(生成建表迁移时)和gs migrate diff维护，应与迁移一起提交。
+
+例如:
+  gs migrate diff
+  gs migrate diff add_age_to_users
+  gs migrate diff --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+	
+			// 获取模板目录
+			templatesDir, err := getTemplatesDir()
+			if err != nil {
+				fmt.Printf("错误: %v\n", err)
+				return
+			}
+	
+			// 获取项目包名
+			packageName, _ := cmd.Flags().GetString("package")
+			if packageName == "" {
+				packageName = getDefaultPackage()
+			}
+	
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+	
+			// 生成迁移
+			g := generator.NewGenerator(templatesDir)
+			g.Dialect, _ = cmd.Flags().GetString("dialect")
+			if err := g.DiffMigration(name, dryRun, packageName); err != nil {
+				fmt.Printf("错误: %v\n", err)
+			}
+		},
+	}
+	
+	cmd.Flags().String("package", "", "项目包名(默认从go.mod获取)")
+	cmd.Flags().String("dialect", "", "迁移的SQL方言: mysql、postgres或sqlite(默认读取项目配置的数据库驱动)")
+	cmd.Flags().Bool("dry-run", false, "只输出变更和SQL，不生成迁移文件")
+	
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	
+	migrateCmd.AddCommand(migrateDiffCmd())
+}
@@ -0,0 +1,203 @@
+package deploy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// releaseName 渲染Helm chart时使用的release名称，与helm template的默认值相同
+const releaseName = "release-name"
+
+// versionPattern 匹配Chart.yaml中语义化版本格式的version
+var versionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// chartMetadata Chart.yaml中需要校验的字段
+type chartMetadata struct {
+	APIVersion string `yaml:"apiVersion"`
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	AppVersion string `yaml:"appVersion"`
+}
+
+// chart 校验Helm chart：检查Chart.yaml的必需字段，用values.yaml渲染templates目录中的模板，
+// 再按普通清单校验渲染结果。只支持常用的Helm模板函数，不需要安装helm
+func (l *linter) chart(dir string) {
+	chartFile := filepath.Join(dir, "Chart.yaml")
+	content, err := os.ReadFile(chartFile)
+	if err != nil {
+		l.report(chartFile, nil, "无法读取文件: %v", err)
+		return
+	}
+	var meta chartMetadata
+	if err := yaml.Unmarshal(content, &meta); err != nil {
+		l.report(chartFile, nil, "YAML格式错误: %v", err)
+		return
+	}
+	if meta.APIVersion != "v2" {
+		l.report(chartFile, nil, "apiVersion应为v2，当前为%q", meta.APIVersion)
+	}
+	if meta.Name == "" {
+		l.report(chartFile, nil, "缺少name")
+	} else if meta.Name != filepath.Base(dir) {
+		l.report(chartFile, nil, "name %s与目录名称%s不一致", meta.Name, filepath.Base(dir))
+	}
+	if !versionPattern.MatchString(meta.Version) {
+		l.report(chartFile, nil, "version必须是语义化版本，例如0.1.0，当前为%q", meta.Version)
+	}
+
+	values := map[string]interface{}{}
+	valuesFile := filepath.Join(dir, "values.yaml")
+	if content, err := os.ReadFile(valuesFile); err == nil {
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			l.report(valuesFile, nil, "YAML格式错误: %v", err)
+			return
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "templates", "*"))
+	sort.Strings(files)
+	tmpl := template.New(filepath.Base(dir)).Option("missingkey=zero")
+	tmpl.Funcs(helmFuncs(tmpl))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if _, err := tmpl.New(filepath.Base(file)).Parse(string(content)); err != nil {
+			l.report(file, nil, "模板解析失败: %v", err)
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"Values": values,
+		"Chart": map[string]interface{}{
+			"Name":       meta.Name,
+			"Version":    meta.Version,
+			"AppVersion": meta.AppVersion,
+		},
+		"Release": map[string]interface{}{
+			"Name":      releaseName,
+			"Namespace": "default",
+			"Service":   "Helm",
+		},
+	}
+	var objects []*object
+	for _, file := range files {
+		name := filepath.Base(file)
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			l.report(file, nil, "模板渲染失败: %v", err)
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+			rendered := strings.ReplaceAll(buf.String(), "<no value>", "")
+			objects = append(objects, l.parse(file, []byte(rendered))...)
+		}
+	}
+	l.check(objects)
+}
+
+// helmFuncs 返回渲染Helm模板需要的常用函数，参数顺序与Helm(sprig)一致
+func helmFuncs(tmpl *template.Template) template.FuncMap {
+	indent := func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	}
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name, data)
+			return buf.String(), err
+		},
+		"required": func(message string, v interface{}) (interface{}, error) {
+			if empty(v) {
+				return nil, fmt.Errorf("%s", message)
+			}
+			return v, nil
+		},
+		"default": func(def interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || empty(given[0]) {
+				return def
+			}
+			return given[0]
+		},
+		"empty": empty,
+		"toYaml": func(v interface{}) string {
+			var buf bytes.Buffer
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(v); err != nil {
+				return ""
+			}
+			return strings.TrimSuffix(buf.String(), "\n")
+		},
+		"indent": indent,
+		"nindent": func(spaces int, s string) string {
+			return "\n" + indent(spaces, s)
+		},
+		"quote": func(v ...interface{}) string {
+			quoted := make([]string, 0, len(v))
+			for _, s := range v {
+				if s != nil {
+					quoted = append(quoted, fmt.Sprintf("%q", fmt.Sprint(s)))
+				}
+			}
+			return strings.Join(quoted, " ")
+		},
+		"toString": func(v interface{}) string { return fmt.Sprint(v) },
+		"trunc": func(n int, s string) string {
+			if n >= 0 && len(s) > n {
+				return s[:n]
+			}
+			return s
+		},
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trim":       strings.TrimSpace,
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"sha256sum": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+	}
+}
+
+// empty 判断值是否为空，与Helm的empty函数一致
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return value.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,545 @@
+package deploy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// Issue 校验发现的问题
+type Issue struct {
+	File    string // 文件路径
+	Object  string // 对象，例如 Deployment/shop，文件级的问题为空
+	Message string // 问题描述
+}
+
+// String 返回 文件: 对象: 问题 形式的描述
+func (i Issue) String() string {
+	if i.Object == "" {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.File, i.Object, i.Message)
+}
+
+// apiVersions 支持校验的资源类型及其apiVersion
+var apiVersions = map[string]string{
+	"Deployment":              "apps/v1",
+	"Service":                 "v1",
+	"ConfigMap":               "v1",
+	"Secret":                  "v1",
+	"PersistentVolumeClaim":   "v1",
+	"HorizontalPodAutoscaler": "autoscaling/v2",
+	"Ingress":                 "networking.k8s.io/v1",
+}
+
+var (
+	// subdomainPattern 大多数资源名称需要满足的DNS-1123子域名格式
+	subdomainPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// labelPattern Service名称、容器名称和端口名称需要满足的DNS-1123标签格式
+	labelPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// pathTypes Ingress支持的pathType
+var pathTypes = map[string]bool{"Exact": true, "Prefix": true, "ImplementationSpecific": true}
+
+// object 清单中的一个Kubernetes对象
+type object struct {
+	file string
+	kind string
+	name string
+	doc  map[string]interface{}
+}
+
+// id 返回 类型/名称 形式的标识
+func (o *object) id() string {
+	return o.kind + "/" + o.name
+}
+
+// linter 收集校验过程中发现的问题
+type linter struct {
+	issues []Issue
+}
+
+// Lint 离线校验dir中的Kubernetes清单和Helm chart(包含Chart.yaml的目录)，不需要连接集群：
+// 检查YAML格式、必需字段和字段类型，以及选择器、端口名称、ConfigMap和PVC等对象之间的引用，
+// Helm chart先用values.yaml渲染再校验。返回按文件排序的问题，没有问题时返回空
+func Lint(dir string) ([]Issue, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取部署目录: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s 不是目录", dir)
+	}
+
+	l := &linter{}
+	var objects []*object
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if utils.FileExists(filepath.Join(path, "Chart.yaml")) {
+				l.chart(path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, l.parse(path, content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("无法读取部署目录: %v", err)
+	}
+	l.check(objects)
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].File < l.issues[j].File
+	})
+	return l.issues, nil
+}
+
+// report 记录问题
+func (l *linter) report(file string, o *object, format string, args ...interface{}) {
+	issue := Issue{File: file, Message: fmt.Sprintf(format, args...)}
+	if o != nil {
+		issue.Object = o.id()
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// parse 解析文件中的YAML文档，校验每个对象的apiVersion、kind和metadata.name，
+// kustomization.yaml只检查其中列出的文件是否存在
+func (l *linter) parse(file string, content []byte) []*object {
+	var objects []*object
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc map[string]interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			l.report(file, nil, "YAML格式错误: %v", err)
+			break
+		}
+		if doc == nil {
+			continue
+		}
+
+		kind, _ := doc["kind"].(string)
+		if kind == "Kustomization" {
+			l.kustomization(file, doc)
+			continue
+		}
+		if kind == "" {
+			l.report(file, nil, "缺少kind")
+			continue
+		}
+		o := &object{file: file, kind: kind, doc: doc}
+		o.name, _ = get(doc, "metadata", "name").(string)
+		if o.name == "" {
+			l.report(file, o, "缺少metadata.name")
+			continue
+		}
+		if apiVersion, _ := doc["apiVersion"].(string); apiVersion == "" {
+			l.report(file, o, "缺少apiVersion")
+		} else if want, ok := apiVersions[kind]; ok && apiVersion != want {
+			l.report(file, o, "apiVersion应为%s，当前为%s", want, apiVersion)
+		}
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+// kustomization 检查kustomization.yaml中列出的资源文件是否存在
+func (l *linter) kustomization(file string, doc map[string]interface{}) {
+	for _, resource := range list(doc["resources"]) {
+		path, _ := resource.(string)
+		if path == "" || strings.Contains(path, "://") {
+			continue
+		}
+		if !utils.FileExists(filepath.Join(filepath.Dir(file), path)) {
+			l.report(file, nil, "resources中的%s不存在", path)
+		}
+	}
+}
+
+// check 校验对象的字段以及对象之间的引用
+func (l *linter) check(objects []*object) {
+	byID := map[string]*object{}
+	for _, o := range objects {
+		if existing, ok := byID[o.id()]; ok {
+			l.report(o.file, o, "重复定义，另见%s", existing.file)
+			continue
+		}
+		byID[o.id()] = o
+	}
+
+	for _, o := range objects {
+		if o.kind == "Service" || o.kind == "Ingress" {
+			if len(o.name) > 63 || !labelPattern.MatchString(o.name) {
+				l.report(o.file, o, "名称只能包含小写字母、数字和连字符，不超过63个字符")
+			}
+		} else if len(o.name) > 253 || !subdomainPattern.MatchString(o.name) {
+			l.report(o.file, o, "名称只能包含小写字母、数字、连字符和点，不超过253个字符")
+		}
+		for key, value := range mapping(get(o.doc, "metadata", "labels")) {
+			if _, ok := value.(string); !ok {
+				l.report(o.file, o, "标签%s的值必须是字符串", key)
+			}
+		}
+
+		switch o.kind {
+		case "Deployment":
+			l.deployment(o, byID)
+		case "Service":
+			l.service(o, byID)
+		case "HorizontalPodAutoscaler":
+			l.hpa(o, byID)
+		case "Ingress":
+			l.ingress(o, byID)
+		case "ConfigMap":
+			for key, value := range mapping(o.doc["data"]) {
+				if _, ok := value.(string); !ok {
+					l.report(o.file, o, "data.%s的值必须是字符串，数字和布尔值需要加引号", key)
+				}
+			}
+		case "PersistentVolumeClaim":
+			if len(list(get(o.doc, "spec", "accessModes"))) == 0 {
+				l.report(o.file, o, "缺少spec.accessModes")
+			}
+			if _, ok := quantity(get(o.doc, "spec", "resources", "requests", "storage")); !ok {
+				l.report(o.file, o, "spec.resources.requests.storage不是有效的容量，例如1Gi")
+			}
+		}
+	}
+}
+
+// deployment 校验Deployment的选择器、副本数和Pod模板
+func (l *linter) deployment(o *object, byID map[string]*object) {
+	if replicas, ok := get(o.doc, "spec", "replicas").(int); ok && replicas < 0 {
+		l.report(o.file, o, "spec.replicas不能小于0")
+	} else if !ok && get(o.doc, "spec", "replicas") != nil {
+		l.report(o.file, o, "spec.replicas必须是整数")
+	}
+
+	matchLabels := mapping(get(o.doc, "spec", "selector", "matchLabels"))
+	if len(matchLabels) == 0 {
+		l.report(o.file, o, "缺少spec.selector.matchLabels")
+	}
+	labels := mapping(get(o.doc, "spec", "template", "metadata", "labels"))
+	for key, value := range matchLabels {
+		if labels[key] != value {
+			l.report(o.file, o, "spec.template.metadata.labels缺少选择器中的标签%s: %v", key, value)
+		}
+	}
+
+	spec := mapping(get(o.doc, "spec", "template", "spec"))
+	if spec == nil {
+		l.report(o.file, o, "缺少spec.template.spec")
+		return
+	}
+	volumes := map[string]bool{}
+	for _, volume := range list(spec["volumes"]) {
+		name, _ := get(volume, "name").(string)
+		volumes[name] = true
+		if claim, ok := get(volume, "persistentVolumeClaim", "claimName").(string); ok && byID["PersistentVolumeClaim/"+claim] == nil {
+			l.report(o.file, o, "卷%s引用的PersistentVolumeClaim %s不在清单中", name, claim)
+		}
+	}
+
+	containers := list(spec["containers"])
+	if len(containers) == 0 {
+		l.report(o.file, o, "缺少spec.template.spec.containers")
+	}
+	for i, c := range containers {
+		container := mapping(c)
+		name, _ := container["name"].(string)
+		if name == "" {
+			name = fmt.Sprintf("containers[%d]", i)
+			l.report(o.file, o, "%s缺少name", name)
+		} else if !labelPattern.MatchString(name) {
+			l.report(o.file, o, "容器名称%s只能包含小写字母、数字和连字符", name)
+		}
+		if image, _ := container["image"].(string); image == "" {
+			l.report(o.file, o, "容器%s缺少image", name)
+		}
+
+		ports := map[string]bool{}
+		for _, p := range list(container["ports"]) {
+			if port, ok := get(p, "containerPort").(int); !ok || port < 1 || port > 65535 {
+				l.report(o.file, o, "容器%s的containerPort必须是1到65535之间的整数", name)
+			} else {
+				ports[fmt.Sprint(port)] = true
+			}
+			if portName, ok := get(p, "name").(string); ok {
+				if len(portName) > 15 || !labelPattern.MatchString(portName) {
+					l.report(o.file, o, "容器%s的端口名称%s只能包含小写字母、数字和连字符，不超过15个字符", name, portName)
+				}
+				ports[portName] = true
+			}
+		}
+		for _, probe := range []string{"livenessProbe", "readinessProbe", "startupProbe"} {
+			httpGet := mapping(get(container, probe, "httpGet"))
+			if httpGet == nil {
+				continue
+			}
+			if path, _ := httpGet["path"].(string); !strings.HasPrefix(path, "/") {
+				l.report(o.file, o, "容器%s的%s.httpGet.path必须以/开头", name, probe)
+			}
+			if port := fmt.Sprint(httpGet["port"]); !ports[port] {
+				l.report(o.file, o, "容器%s的%s.httpGet.port %s不是容器的端口", name, probe, port)
+			}
+		}
+
+		for _, env := range list(container["env"]) {
+			envName, _ := get(env, "name").(string)
+			if envName == "" {
+				l.report(o.file, o, "容器%s的env缺少name", name)
+			}
+			if value, ok := get(env, "value").(string); !ok && get(env, "value") != nil {
+				l.report(o.file, o, "容器%s的环境变量%s的值必须是字符串，当前为%v", name, envName, value)
+			}
+		}
+		for _, envFrom := range list(container["envFrom"]) {
+			ref, ok := get(envFrom, "configMapRef", "name").(string)
+			if optional, _ := get(envFrom, "configMapRef", "optional").(bool); ok && !optional && byID["ConfigMap/"+ref] == nil {
+				l.report(o.file, o, "容器%s引用的ConfigMap %s不在清单中", name, ref)
+			}
+		}
+		for _, mount := range list(container["volumeMounts"]) {
+			if volume, _ := get(mount, "name").(string); !volumes[volume] {
+				l.report(o.file, o, "容器%s挂载的卷%s没有在volumes中定义", name, volume)
+			}
+		}
+		l.resources(o, name, mapping(container["resources"]))
+	}
+}
+
+// resources 校验容器的资源配额格式，requests不能大于limits
+func (l *linter) resources(o *object, container string, resources map[string]interface{}) {
+	requests, limits := mapping(resources["requests"]), mapping(resources["limits"])
+	for _, group := range []struct {
+		name   string
+		values map[string]interface{}
+	}{{"requests", requests}, {"limits", limits}} {
+		for resource, value := range group.values {
+			if _, ok := quantity(value); !ok {
+				l.report(o.file, o, "容器%s的resources.%s.%s不是有效的数量: %v", container, group.name, resource, value)
+			}
+		}
+	}
+	for resource, value := range requests {
+		request, ok1 := quantity(value)
+		limit, ok2 := quantity(limits[resource])
+		if ok1 && ok2 && request > limit {
+			l.report(o.file, o, "容器%s的resources.requests.%s大于limits", container, resource)
+		}
+	}
+}
+
+// service 校验Service的端口以及选择器是否匹配Deployment的Pod
+func (l *linter) service(o *object, byID map[string]*object) {
+	selector := mapping(get(o.doc, "spec", "selector"))
+	ports := list(get(o.doc, "spec", "ports"))
+	if len(ports) == 0 {
+		l.report(o.file, o, "缺少spec.ports")
+	}
+
+	// 选择器匹配的Deployment的容器端口
+	var matched []*object
+	for _, d := range byID {
+		if d.kind != "Deployment" || len(selector) == 0 {
+			continue
+		}
+		labels := mapping(get(d.doc, "spec", "template", "metadata", "labels"))
+		match := true
+		for key, value := range selector {
+			if labels[key] != value {
+				match = false
+			}
+		}
+		if match {
+			matched = append(matched, d)
+		}
+	}
+	if len(selector) == 0 {
+		l.report(o.file, o, "缺少spec.selector")
+	} else if len(matched) == 0 && hasKind(byID, "Deployment") {
+		l.report(o.file, o, "spec.selector没有匹配任何Deployment的Pod")
+	}
+	containerPorts := map[string]bool{}
+	for _, d := range matched {
+		for _, c := range list(get(d.doc, "spec", "template", "spec", "containers")) {
+			for _, p := range list(get(c, "ports")) {
+				containerPorts[fmt.Sprint(get(p, "containerPort"))] = true
+				if name, ok := get(p, "name").(string); ok {
+					containerPorts[name] = true
+				}
+			}
+		}
+	}
+
+	for _, p := range ports {
+		port, ok := get(p, "port").(int)
+		if !ok || port < 1 || port > 65535 {
+			l.report(o.file, o, "spec.ports的port必须是1到65535之间的整数")
+		}
+		if target := get(p, "targetPort"); target != nil && len(matched) > 0 && !containerPorts[fmt.Sprint(target)] {
+			l.report(o.file, o, "targetPort %v不是所选Pod的容器端口", target)
+		}
+	}
+}
+
+// hpa 校验HPA的伸缩目标和副本数范围
+func (l *linter) hpa(o *object, byID map[string]*object) {
+	kind, _ := get(o.doc, "spec", "scaleTargetRef", "kind").(string)
+	name, _ := get(o.doc, "spec", "scaleTargetRef", "name").(string)
+	target := byID[kind+"/"+name]
+	if target == nil {
+		l.report(o.file, o, "伸缩目标%s/%s不在清单中", kind, name)
+	}
+
+	maxReplicas, ok := get(o.doc, "spec", "maxReplicas").(int)
+	if !ok || maxReplicas < 1 {
+		l.report(o.file, o, "spec.maxReplicas必须是大于0的整数")
+	}
+	if minReplicas, ok := get(o.doc, "spec", "minReplicas").(int); ok && minReplicas > maxReplicas {
+		l.report(o.file, o, "spec.minReplicas大于spec.maxReplicas")
+	}
+
+	// 按CPU使用率伸缩时，目标的每个容器都需要设置CPU请求量
+	for _, metric := range list(get(o.doc, "spec", "metrics")) {
+		if get(metric, "resource", "name") != "cpu" || get(metric, "resource", "target", "type") != "Utilization" || target == nil {
+			continue
+		}
+		for _, c := range list(get(target.doc, "spec", "template", "spec", "containers")) {
+			if get(c, "resources", "requests", "cpu") == nil {
+				l.report(o.file, o, "按CPU使用率伸缩时%s的容器%v需要设置resources.requests.cpu", target.id(), get(c, "name"))
+			}
+		}
+	}
+}
+
+// ingress 校验Ingress的路径和后端Service
+func (l *linter) ingress(o *object, byID map[string]*object) {
+	rules := list(get(o.doc, "spec", "rules"))
+	if len(rules) == 0 && get(o.doc, "spec", "defaultBackend") == nil {
+		l.report(o.file, o, "缺少spec.rules")
+	}
+	for _, rule := range rules {
+		for _, p := range list(get(rule, "http", "paths")) {
+			if path, _ := get(p, "path").(string); !strings.HasPrefix(path, "/") {
+				l.report(o.file, o, "path必须以/开头")
+			}
+			if pathType, _ := get(p, "pathType").(string); !pathTypes[pathType] {
+				l.report(o.file, o, "pathType必须是Exact、Prefix或ImplementationSpecific")
+			}
+
+			name, _ := get(p, "backend", "service", "name").(string)
+			service := byID["Service/"+name]
+			if service == nil {
+				l.report(o.file, o, "后端Service %s不在清单中", name)
+				continue
+			}
+			port := get(p, "backend", "service", "port", "name")
+			if port == nil {
+				port = get(p, "backend", "service", "port", "number")
+			}
+			found := false
+			for _, servicePort := range list(get(service.doc, "spec", "ports")) {
+				if get(servicePort, "name") == port || get(servicePort, "port") == port {
+					found = true
+				}
+			}
+			if !found {
+				l.report(o.file, o, "后端端口%v不是%s的端口", port, service.id())
+			}
+		}
+	}
+}
+
+// hasKind 判断清单中是否有指定类型的对象
+func hasKind(byID map[string]*object, kind string) bool {
+	for _, o := range byID {
+		if o.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// get 按路径读取嵌套的映射，路径中任一层不存在时返回nil
+func get(v interface{}, path ...string) interface{} {
+	for _, key := range path {
+		m := mapping(v)
+		if m == nil {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// mapping 将YAML映射转换为map，不是映射时返回nil
+func mapping(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// list 将YAML序列转换为切片，不是序列时返回nil
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// quantitySuffixes Kubernetes资源数量支持的单位
+var quantitySuffixes = map[string]float64{
+	"m": 1e-3, "": 1, "k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// quantityPattern 匹配资源数量，例如 100m、0.5、256Mi
+var quantityPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)([a-zA-Z]*)$`)
+
+// quantity 解析资源数量，例如CPU的100m和内存的256Mi，返回以基本单位计的值
+func quantity(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		match := quantityPattern.FindStringSubmatch(v)
+		if match == nil {
+			return 0, false
+		}
+		scale, ok := quantitySuffixes[match[2]]
+		if !ok {
+			return 0, false
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		return value * scale, err == nil
+	}
+	return 0, false
+}
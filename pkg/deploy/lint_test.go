@@ -0,0 +1,234 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试清单
+var testManifests = map[string]string{
+	"k8s/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - app.yaml
+`,
+	"k8s/app.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shop
+data:
+  APP_SERVER_PORT: "8080"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: shop
+  template:
+    metadata:
+      labels:
+        app: shop
+    spec:
+      containers:
+        - name: shop
+          image: shop:latest
+          ports:
+            - name: http
+              containerPort: 8080
+          envFrom:
+            - configMapRef:
+                name: shop
+          readinessProbe:
+            httpGet:
+              path: /readyz
+              port: http
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: "1"
+              memory: 256Mi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: shop
+spec:
+  selector:
+    app: shop
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: shop
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: shop
+  minReplicas: 2
+  maxReplicas: 10
+  metrics:
+    - type: Resource
+      resource:
+        name: cpu
+        target:
+          type: Utilization
+          averageUtilization: 70
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: shop
+spec:
+  rules:
+    - host: shop.example.com
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: shop
+                port:
+                  name: http
+`,
+	"helm/shop/Chart.yaml": "apiVersion: v2\nname: shop\nversion: 0.1.0\nappVersion: \"1.0\"\n",
+	"helm/shop/values.yaml": `port: 8080
+labels:
+  tier: web
+config:
+  GIN_MODE: release
+  APP_SERVER_PORT: 8080
+`,
+	"helm/shop/templates/_helpers.tpl": `{{- define "shop.fullname" -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}`,
+	"helm/shop/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "shop.fullname" . }}
+data:
+  {{- range $key, $value := .Values.config }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+`,
+	"helm/shop/templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "shop.fullname" . }}
+spec:
+  selector:
+    matchLabels:
+      app: {{ .Chart.Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Chart.Name }}
+        {{- toYaml .Values.labels | nindent 8 }}
+    spec:
+      containers:
+        - name: {{ .Chart.Name }}
+          image: "shop:{{ .Values.tag | default .Chart.AppVersion }}"
+          ports:
+            - containerPort: {{ .Values.port }}
+          envFrom:
+            - configMapRef:
+                name: {{ include "shop.fullname" . }}
+`,
+	"helm/shop/templates/NOTES.txt": "{{ .Release.Name }} 已部署\n",
+}
+
+// writeManifests 在临时目录写入测试清单，edits按文件替换内容
+func writeManifests(t *testing.T, edits map[string][2]string) string {
+	dir := t.TempDir()
+	for name, content := range testManifests {
+		if edit, ok := edits[name]; ok {
+			require.Contains(t, content, edit[0])
+			content = strings.Replace(content, edit[0], edit[1], 1)
+		}
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+// messages 返回问题描述，便于断言
+func messages(issues []Issue) []string {
+	var result []string
+	for _, issue := range issues {
+		result = append(result, issue.Object+": "+issue.Message)
+	}
+	return result
+}
+
+func TestLintValid(t *testing.T) {
+	issues, err := Lint(writeManifests(t, nil))
+	require.NoError(t, err)
+	assert.Empty(t, messages(issues))
+}
+
+func TestLintManifests(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		edit  [2]string
+		issue string
+	}{
+		{"YAML格式错误", "k8s/app.yaml", [2]string{"  name: shop\ndata:", "  name: shop\n data:"}, "YAML格式错误"},
+		{"缺少名称", "k8s/app.yaml", [2]string{"  name: shop\ndata:", "  labels: {}\ndata:"}, "缺少metadata.name"},
+		{"apiVersion错误", "k8s/app.yaml", [2]string{"autoscaling/v2", "autoscaling/v1"}, "HorizontalPodAutoscaler/shop: apiVersion应为autoscaling/v2"},
+		{"名称格式错误", "k8s/app.yaml", [2]string{"kind: Ingress\nmetadata:\n  name: shop", "kind: Ingress\nmetadata:\n  name: Shop_Web"}, "Ingress/Shop_Web: 名称只能包含"},
+		{"ConfigMap的值不是字符串", "k8s/app.yaml", [2]string{`"8080"`, "8080"}, "ConfigMap/shop: data.APP_SERVER_PORT的值必须是字符串"},
+		{"选择器与Pod标签不一致", "k8s/app.yaml", [2]string{"      labels:\n        app: shop", "      labels:\n        app: web"}, "Deployment/shop: spec.template.metadata.labels缺少选择器中的标签app"},
+		{"探针端口不存在", "k8s/app.yaml", [2]string{"port: http", "port: web"}, "Deployment/shop: 容器shop的readinessProbe.httpGet.port web不是容器的端口"},
+		{"引用的ConfigMap不存在", "k8s/app.yaml", [2]string{"            - configMapRef:\n                name: shop", "            - configMapRef:\n                name: shop-env"}, "容器shop引用的ConfigMap shop-env不在清单中"},
+		{"requests大于limits", "k8s/app.yaml", [2]string{"memory: 64Mi", "memory: 1Gi"}, "容器shop的resources.requests.memory大于limits"},
+		{"资源数量格式错误", "k8s/app.yaml", [2]string{"cpu: 100m", "cpu: 100 millicores"}, "resources.requests.cpu不是有效的数量"},
+		{"Service选择器不匹配", "k8s/app.yaml", [2]string{"  selector:\n    app: shop\n  ports:", "  selector:\n    app: web\n  ports:"}, "Service/shop: spec.selector没有匹配任何Deployment的Pod"},
+		{"targetPort不存在", "k8s/app.yaml", [2]string{"targetPort: http", "targetPort: 9090"}, "Service/shop: targetPort 9090不是所选Pod的容器端口"},
+		{"HPA副本数范围错误", "k8s/app.yaml", [2]string{"minReplicas: 2", "minReplicas: 20"}, "spec.minReplicas大于spec.maxReplicas"},
+		{"HPA缺少CPU请求量", "k8s/app.yaml", [2]string{"            requests:\n              cpu: 100m\n", "            requests:\n"}, "需要设置resources.requests.cpu"},
+		{"Ingress后端端口不存在", "k8s/app.yaml", [2]string{"                  name: http", "                  name: grpc"}, "Ingress/shop: 后端端口grpc不是Service/shop的端口"},
+		{"kustomization中的文件不存在", "k8s/kustomization.yaml", [2]string{"app.yaml", "apps.yaml"}, "resources中的apps.yaml不存在"},
+		{"Chart版本错误", "helm/shop/Chart.yaml", [2]string{"0.1.0", "latest"}, "version必须是语义化版本"},
+		{"Chart名称与目录不一致", "helm/shop/Chart.yaml", [2]string{"name: shop", "name: store"}, "name store与目录名称shop不一致"},
+		{"模板渲染失败", "helm/shop/templates/deployment.yaml", [2]string{"{{ include \"shop.fullname\" . }}", "{{ include \"shop.name\" . }}"}, "模板渲染失败"},
+		{"渲染结果校验", "helm/shop/values.yaml", [2]string{"port: 8080", "port: 80800"}, "Deployment/release-name-shop: 容器shop的containerPort必须是1到65535之间的整数"},
+		{"渲染后ConfigMap的值不是字符串", "helm/shop/templates/configmap.yaml", [2]string{"$value | quote", "$value"}, "ConfigMap/release-name-shop: data.APP_SERVER_PORT的值必须是字符串"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Lint(writeManifests(t, map[string][2]string{tt.file: tt.edit}))
+			require.NoError(t, err)
+			assert.Contains(t, strings.Join(messages(issues), "\n"), tt.issue)
+		})
+	}
+}
+
+func TestLintNotDir(t *testing.T) {
+	_, err := Lint(filepath.Join(t.TempDir(), "deploy"))
+	assert.ErrorContains(t, err, "无法读取部署目录")
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{File: "deploy/k8s/service.yaml", Object: "Service/shop", Message: "缺少spec.ports"}
+	assert.Equal(t, "deploy/k8s/service.yaml: Service/shop: 缺少spec.ports", issue.String())
+	issue.Object = ""
+	assert.Equal(t, "deploy/k8s/service.yaml: 缺少spec.ports", issue.String())
+}
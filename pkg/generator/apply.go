@@ -0,0 +1,719 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyLockFile 记录gs apply生成的文件摘要，用于判断文件是否被手动修改
+const ApplyLockFile = "gs.lock"
+
+// ErrApplyConflict 有组件因冲突被跳过时Apply返回的错误，其余组件已正常生成
+var ErrApplyConflict = errors.New("存在冲突的组件")
+
+// ApplySpec gs.yaml中声明的项目设置和资源
+type ApplySpec struct {
+	Project   ApplyProject    `yaml:"project"`
+	Resources []ApplyResource `yaml:"resources"`
+}
+
+// ApplyProject 项目设置
+type ApplyProject struct {
+	Package     string `yaml:"package"`                // 项目包名，为空时从go.mod读取
+	Auth        string `yaml:"auth"`                   // 认证策略jwt或apikey，为空时不生成认证模块
+	NoMigration bool   `yaml:"no_migration,omitempty"` // 生成模型时不生成建表迁移，与--no-migration一致
+}
+
+// ApplyResource 资源定义
+type ApplyResource struct {
+	Name       string          `yaml:"name"`
+	Table      string          `yaml:"table,omitempty"`
+	Path       string          `yaml:"path,omitempty"`
+	Comment    string          `yaml:"comment,omitempty"`
+	Fields     []ApplyField    `yaml:"fields,omitempty"`
+	Relations  []ApplyRelation `yaml:"relations,omitempty"`
+	Options    ApplyOptions    `yaml:"options,omitempty"`
+	Components []string        `yaml:"components,omitempty"` // 为空时与gs create feature一致
+}
+
+// ApplyField 字段定义
+type ApplyField struct {
+	Name     string   `yaml:"name"`               // 字段名，snake_case时作为json键名
+	Type     string   `yaml:"type"`               // 字段类型，见applyFieldTypes
+	Required bool     `yaml:"required,omitempty"` // 必填，数据库列为not null
+	Nullable bool     `yaml:"nullable,omitempty"` // 可为空，使用指针类型
+	Unique   bool     `yaml:"unique,omitempty"`
+	Index    bool     `yaml:"index,omitempty"`
+	Size     int      `yaml:"size,omitempty"`    // 字符串最大长度
+	Default  string   `yaml:"default,omitempty"` // 数据库默认值
+	Format   string   `yaml:"format,omitempty"`  // email、url或uuid
+	Enum     []string `yaml:"enum,omitempty"`
+	Comment  string   `yaml:"comment,omitempty"`
+	Sample   string   `yaml:"sample,omitempty"` // 测试请求中使用的JSON值
+}
+
+// ApplyRelation 关联定义
+type ApplyRelation struct {
+	Type     string `yaml:"type"`               // belongs_to或has_many
+	Model    string `yaml:"model"`              // 关联的资源名
+	Optional bool   `yaml:"optional,omitempty"` // belongs_to的外键可为空
+}
+
+// ApplyOptions 资源的生成选项
+type ApplyOptions struct {
+	SoftDelete bool `yaml:"soft_delete,omitempty"`
+	Pagination bool `yaml:"pagination,omitempty"`
+	Versioned  bool `yaml:"versioned,omitempty"`
+	Auth       bool `yaml:"auth,omitempty"` // 路由需要JWT认证
+	RBAC       bool `yaml:"rbac,omitempty"` // 按操作校验权限，隐含auth
+}
+
+// applyFieldTypes 字段类型对应的Go类型和gorm列类型
+var applyFieldTypes = map[string]struct {
+	GoType string
+	Column string
+}{
+	"string":  {"string", ""},
+	"text":    {"string", "type:text"},
+	"int":     {"int", ""},
+	"int32":   {"int32", ""},
+	"int64":   {"int64", ""},
+	"uint":    {"uint", ""},
+	"uint64":  {"uint64", ""},
+	"float32": {"float32", ""},
+	"float64": {"float64", ""},
+	"decimal": {"float64", "type:decimal(10,2)"},
+	"bool":    {"bool", ""},
+	"time":    {"time.Time", ""},
+	"date":    {"time.Time", "type:date"},
+	"json":    {"map[string]interface{}", "serializer:json"},
+}
+
+// applyFormatSamples 字段格式对应的binding规则和测试值
+var applyFormatSamples = map[string]string{
+	"email": `"test@example.com"`,
+	"url":   `"https://example.com"`,
+	"uuid":  `"123e4567-e89b-12d3-a456-426614174000"`,
+}
+
+// applyComponents 可生成的组件及其输出文件，按生成顺序排列
+var applyComponents = []struct {
+	Name  string
+	Files []string // 文件路径，%s为小写资源名
+}{
+	{"model", []string{"models/%s.go"}},
+	{"repository", []string{"repositories/%s_repository.go"}},
+	{"service", []string{"services/%s_service.go"}},
+	{"controller", []string{"controllers/%s_controller.go"}},
+	{"route", []string{"routes/%s_routes.go"}},
+	{"test", []string{"tests/%s_test.go"}},
+	{"rbac", []string{"rbac/%s_permissions.go", "tests/%s_rbac_test.go"}},
+	{"example", []string{"examples/%s/main.go"}},
+}
+
+// applyAuthFiles 用于判断认证模块是否已生成的文件
+var applyAuthFiles = map[string]string{
+	"jwt":    filepath.Join("middlewares", "auth.go"),
+	"apikey": filepath.Join("middlewares", "api_key.go"),
+}
+
+// applyLock gs.lock的内容
+type applyLock struct {
+	Resources map[string]map[string]applyLockEntry `yaml:"resources"` // 资源名 -> 组件 -> 记录
+}
+
+// applyLockEntry 组件上次生成时的资源定义摘要和文件摘要
+type applyLockEntry struct {
+	Spec  string            `yaml:"spec"`
+	Files map[string]string `yaml:"files"`
+}
+
+// 计划中的操作
+const (
+	applyCreate    = "create"
+	applyUpdate    = "update"
+	applyUnchanged = "unchanged"
+	applyConflict  = "conflict"
+)
+
+// applyStep 计划中对一个组件的操作
+type applyStep struct {
+	Resource  string
+	Component string
+	Action    string
+	Files     []string
+	Reason    string // 冲突原因
+}
+
+// LoadApplySpec 读取并校验gs.yaml
+func LoadApplySpec(path string) (*ApplySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取资源定义文件: %v", err)
+	}
+	
+	// 拼错的键直接报错，避免静默忽略
+	var spec ApplySpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("无法解析资源定义文件 %s: %v", path, err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("资源定义文件 %s 有误: %v", path, err)
+	}
+	return &spec, nil
+}
+
+// validate 校验资源定义并统一资源名
+func (s *ApplySpec) validate() error {
+	if s.Project.Auth != "" {
+		s.Project.Auth = strings.ToLower(s.Project.Auth)
+		if _, ok := AuthStrategies[s.Project.Auth]; !ok {
+			return fmt.Errorf("不支持的认证策略 '%s'，可用策略: %s", s.Project.Auth, strings.Join(AuthStrategyNames(), ", "))
+		}
+	}
+	if len(s.Resources) == 0 {
+		return fmt.Errorf("没有声明任何资源")
+	}
+	
+	names := map[string]bool{}
+	for i := range s.Resources {
+		r := &s.Resources[i]
+		r.Name = formatName(goName(r.Name))
+		if r.Name == "" {
+			return fmt.Errorf("第%d个资源缺少name", i+1)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("资源 %s 重复声明", r.Name)
+		}
+		names[r.Name] = true
+	}
+	
+	for _, r := range s.Resources {
+		fields := map[string]bool{}
+		for _, f := range r.Fields {
+			name := goName(f.Name)
+			switch {
+			case name == "":
+				return fmt.Errorf("资源 %s 有字段缺少name", r.Name)
+			case ignoredFields[name] || name == "DeletedAt":
+				return fmt.Errorf("资源 %s 的字段 %s 由gs自动生成，无需声明", r.Name, f.Name)
+			case fields[name]:
+				return fmt.Errorf("资源 %s 的字段 %s 重复声明", r.Name, f.Name)
+			}
+			fields[name] = true
+			if _, ok := applyFieldTypes[f.Type]; !ok {
+				return fmt.Errorf("资源 %s 的字段 %s 类型 '%s' 不支持，可用类型: %s", r.Name, f.Name, f.Type, strings.Join(applyFieldTypeNames(), ", "))
+			}
+			if _, ok := applyFormatSamples[f.Format]; f.Format != "" && !ok {
+				return fmt.Errorf("资源 %s 的字段 %s 格式 '%s' 不支持，可用格式: email, url, uuid", r.Name, f.Name, f.Format)
+			}
+		}
+		for _, rel := range r.Relations {
+			if rel.Type != "belongs_to" && rel.Type != "has_many" {
+				return fmt.Errorf("资源 %s 的关联类型 '%s' 不支持，可用类型: belongs_to, has_many", r.Name, rel.Type)
+			}
+			model := formatName(goName(rel.Model))
+			if !names[model] {
+				return fmt.Errorf("资源 %s 关联的资源 '%s' 未声明", r.Name, rel.Model)
+			}
+			// has_many通过对方的外键关联
+			if rel.Type == "has_many" && !s.findResource(model).belongsTo(r.Name) {
+				return fmt.Errorf("资源 %s 与 %s 是一对多关联，%s 需要声明 belongs_to %s", r.Name, model, model, r.Name)
+			}
+		}
+		for _, component := range r.Components {
+			if applyComponentFiles(r.Name, component) == nil {
+				return fmt.Errorf("资源 %s 的组件 '%s' 不支持，可用组件: %s", r.Name, component, strings.Join(applyComponentNames(), ", "))
+			}
+		}
+		components := r.components()
+		if r.Options.Versioned && hasComponent(components, "service") && !hasComponent(components, "repository") {
+			return fmt.Errorf("资源 %s 启用了乐观锁，service依赖repository组件", r.Name)
+		}
+		if hasComponent(components, "rbac") && !hasComponent(components, "test") {
+			return fmt.Errorf("资源 %s 的权限测试使用test组件中的认证辅助函数，rbac依赖test组件", r.Name)
+		}
+	}
+	return nil
+}
+
+// applyFieldTypeNames 返回排序后的字段类型列表
+func applyFieldTypeNames() []string {
+	names := make([]string, 0, len(applyFieldTypes))
+	for name := range applyFieldTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyComponentNames 返回可生成的组件列表
+func applyComponentNames() []string {
+	var names []string
+	for _, component := range applyComponents {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+// applyComponentFiles 返回组件的输出文件，组件不存在时返回nil
+func applyComponentFiles(name, component string) []string {
+	for _, c := range applyComponents {
+		if c.Name == component {
+			files := make([]string, len(c.Files))
+			for i, file := range c.Files {
+				files[i] = filepath.FromSlash(fmt.Sprintf(file, strings.ToLower(name)))
+			}
+			return files
+		}
+	}
+	return nil
+}
+
+// hasComponent 判断组件列表中是否包含组件
+func hasComponent(components []string, component string) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// components 返回要生成的组件，按生成顺序排列。未声明时与gs create feature一致
+func (r ApplyResource) components() []string {
+	var components []string
+	for _, c := range applyComponents {
+		var wanted bool
+		if len(r.Components) > 0 {
+			wanted = hasComponent(r.Components, c.Name)
+		} else {
+			switch c.Name {
+			case "repository":
+				wanted = r.Options.Versioned
+			case "rbac":
+				wanted = r.Options.RBAC
+			default:
+				wanted = true
+			}
+		}
+		if wanted {
+			components = append(components, c.Name)
+		}
+	}
+	return components
+}
+
+// belongsTo 判断资源是否声明了对model的belongs_to关联
+func (r *ApplyResource) belongsTo(model string) bool {
+	for _, rel := range r.Relations {
+		if rel.Type == "belongs_to" && formatName(goName(rel.Model)) == model {
+			return true
+		}
+	}
+	return false
+}
+
+// featureOptions 将资源选项转换为组件生成选项
+func (r ApplyResource) featureOptions() FeatureOptions {
+	return FeatureOptions{
+		Versioned:  r.Options.Versioned,
+		Protected:  r.Options.Auth || r.Options.RBAC,
+		RBAC:       r.Options.RBAC,
+		SoftDelete: r.Options.SoftDelete,
+		Paginated:  r.Options.Pagination,
+	}
+}
+
+// resourceSpec 将资源定义转换为模型字段和路由
+func (r ApplyResource) resourceSpec() *ResourceSpec {
+	spec := &ResourceSpec{
+		BasePath: r.Path,
+		CRUD:     true,
+		Comment:  r.Comment,
+		Table:    r.Table,
+	}
+	
+	declared := map[string]bool{}
+	for _, f := range r.Fields {
+		field := f.field()
+		declared[field.Name] = true
+		spec.Fields = append(spec.Fields, field)
+	}
+	
+	for _, rel := range r.Relations {
+		model := formatName(goName(rel.Model))
+		if rel.Type == "belongs_to" {
+			key := Field{Name: model + "ID", Type: "uint", JSONName: toSnakeName(model) + "_id", Gorm: "not null;index", Binding: "required"}
+			if rel.Optional {
+				key = Field{Name: model + "ID", Type: "*uint", JSONName: toSnakeName(model) + "_id", Gorm: "index"}
+			}
+			if !declared[key.Name] {
+				spec.Fields = append(spec.Fields, key)
+			}
+			spec.Fields = append(spec.Fields, Field{
+				Name:     model,
+				Type:     "*" + model,
+				JSONName: toSnakeName(model) + ",omitempty",
+				Gorm:     "foreignKey:" + model + "ID",
+				ReadOnly: true,
+			})
+			continue
+		}
+		plural := PluralForm(model)
+		spec.Fields = append(spec.Fields, Field{
+			Name:     plural,
+			Type:     "[]" + model,
+			JSONName: toSnakeName(plural) + ",omitempty",
+			Gorm:     "foreignKey:" + r.Name + "ID",
+			ReadOnly: true,
+		})
+	}
+	return spec
+}
+
+// field 将字段定义转换为模型字段
+func (f ApplyField) field() Field {
+	fieldType := applyFieldTypes[f.Type]
+	field := Field{
+		Name:     goName(f.Name),
+		Type:     fieldType.GoType,
+		JSONName: toSnakeName(f.Name),
+		Comment:  f.Comment,
+	}
+	if f.Nullable && !strings.HasPrefix(field.Type, "map[") {
+		field.Type = "*" + field.Type
+	}
+	
+	// gorm标签
+	var gorm []string
+	if fieldType.Column != "" {
+		gorm = append(gorm, fieldType.Column)
+	}
+	if f.Size > 0 {
+		gorm = append(gorm, fmt.Sprintf("size:%d", f.Size))
+	}
+	if f.Required && !f.Nullable {
+		gorm = append(gorm, "not null")
+	}
+	if f.Unique {
+		gorm = append(gorm, "uniqueIndex")
+	} else if f.Index {
+		gorm = append(gorm, "index")
+	}
+	if f.Default != "" {
+		gorm = append(gorm, "default:"+f.Default)
+	}
+	field.Gorm = strings.Join(gorm, ";")
+	
+	// binding校验规则，bool的零值false无法通过required校验
+	var rules []string
+	if f.Format != "" {
+		rules = append(rules, f.Format)
+		field.Sample = applyFormatSamples[f.Format]
+	}
+	if len(f.Enum) > 0 {
+		rules = append(rules, "oneof="+strings.Join(f.Enum, " "))
+		field.Sample = strconv.Quote(f.Enum[0])
+	}
+	if f.Size > 0 && fieldType.GoType == "string" {
+		rules = append(rules, fmt.Sprintf("max=%d", f.Size))
+		if field.Sample == "" && f.Size < 32 {
+			field.Sample = strconv.Quote(strings.Repeat("a", min(f.Size, 4)))
+		}
+	}
+	if f.Required && fieldType.GoType != "bool" {
+		rules = append([]string{"required"}, rules...)
+	} else if len(rules) > 0 {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	field.Binding = strings.Join(rules, ",")
+	
+	if f.Sample != "" {
+		field.Sample = f.Sample
+	}
+	return field
+}
+
+// toSnakeName 将字段名转换为snake_case，已是snake_case时保持不变，例如 CustomerID -> customer_id
+func toSnakeName(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// hashSpec 返回资源定义的摘要，定义变化时重新生成组件
+func hashSpec(resource ApplyResource, packageName string) string {
+	data, _ := yaml.Marshal(resource)
+	sum := sha256.Sum256(append(data, packageName...))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFile 返回文件内容的摘要，文件不存在时返回空
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadApplyLock 读取gs.lock，文件不存在时返回空记录
+func loadApplyLock() (*applyLock, error) {
+	lock := &applyLock{}
+	data, err := os.ReadFile(ApplyLockFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("无法读取%s: %v", ApplyLockFile, err)
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("无法解析%s: %v", ApplyLockFile, err)
+	}
+	if lock.Resources == nil {
+		lock.Resources = map[string]map[string]applyLockEntry{}
+	}
+	return lock, nil
+}
+
+// save 写入gs.lock
+func (l *applyLock) save() error {
+	var buf bytes.Buffer
+	buf.WriteString("# 由gs apply生成，记录各组件生成时的资源定义和文件摘要，请勿手动修改\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("无法生成%s: %v", ApplyLockFile, err)
+	}
+	if err := os.WriteFile(ApplyLockFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("无法写入%s: %v", ApplyLockFile, err)
+	}
+	return nil
+}
+
+// planComponent 比较当前文件和上次生成的记录，决定组件的操作
+func planComponent(entry *applyLockEntry, files []string, specHash string, force bool) (string, string) {
+	var existing []string
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+	if len(existing) == 0 {
+		return applyCreate, ""
+	}
+	if entry != nil && entry.Spec == specHash && len(existing) == len(files) {
+		return applyUnchanged, ""
+	}
+	
+	// 只覆盖上次由gs apply生成且之后没有修改过的文件
+	if !force {
+		for _, file := range existing {
+			if entry == nil || entry.Files[filepath.ToSlash(file)] == "" {
+				return applyConflict, file + " 已存在且不是由gs apply生成"
+			}
+			if entry.Files[filepath.ToSlash(file)] != hashFile(file) {
+				return applyConflict, file + " 在上次生成后被修改"
+			}
+		}
+	}
+	return applyUpdate, ""
+}
+
+// Apply 根据gs.yaml计算生成计划并执行：不存在的组件直接生成，资源定义变化时重新生成
+// 上次由gs apply生成且未被修改的文件，被修改过的文件视为冲突并跳过，force为true时强制覆盖。
+// 有冲突时其余组件照常生成，最后返回包装ErrApplyConflict的错误。packageName为默认包名，资源定义中声明了project.package时以其为准。dryRun为true时只输出计划
+func (g *Generator) Apply(path string, packageName string, dryRun bool, force bool) error {
+	spec, err := LoadApplySpec(path)
+	if err != nil {
+		return err
+	}
+	if spec.Project.Package != "" {
+		packageName = spec.Project.Package
+	}
+	
+	lock, err := loadApplyLock()
+	if err != nil {
+		return err
+	}
+	
+	// 受保护的资源依赖JWT认证模块
+	authFile := applyAuthFiles["jwt"]
+	_, statErr := os.Stat(authFile)
+	hasJWT := statErr == nil || spec.Project.Auth == "jwt"
+	for _, r := range spec.Resources {
+		if (r.Options.Auth || r.Options.RBAC) && !hasJWT {
+			return fmt.Errorf("资源 %s 需要认证，请在project.auth中声明jwt或先执行 gs create auth --strategy=jwt", r.Name)
+		}
+	}
+	
+	// 计算计划
+	var steps []applyStep
+	if spec.Project.Auth != "" {
+		file := applyAuthFiles[spec.Project.Auth]
+		action := applyUnchanged
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			action = applyCreate
+		}
+		steps = append(steps, applyStep{Resource: spec.Project.Auth, Component: "auth", Action: action, Files: []string{file}})
+	}
+	for _, r := range spec.Resources {
+		specHash := hashSpec(r, packageName)
+		for _, component := range r.components() {
+			files := applyComponentFiles(r.Name, component)
+			var entry *applyLockEntry
+			if recorded, ok := lock.Resources[r.Name][component]; ok {
+				entry = &recorded
+			}
+			action, reason := planComponent(entry, files, specHash, force)
+			steps = append(steps, applyStep{Resource: r.Name, Component: component, Action: action, Files: files, Reason: reason})
+		}
+	}
+	
+	printApplyPlan(steps)
+	if dryRun {
+		return nil
+	}
+	
+	// 执行计划
+	if g.Specs == nil {
+		g.Specs = map[string]*ResourceSpec{}
+	}
+	for _, r := range spec.Resources {
+		g.Specs[r.Name] = r.resourceSpec()
+	}
+	
+	counts := map[string]int{}
+	for _, step := range steps {
+		counts[step.Action]++
+		if step.Action != applyCreate && step.Action != applyUpdate {
+			continue
+		}
+	
+		if step.Component == "auth" {
+			g.Options = FeatureOptions{Migration: !spec.Project.NoMigration}
+			if err := g.GenerateAuth(packageName, step.Resource); err != nil {
+				return err
+			}
+			continue
+		}
+	
+		resource := spec.findResource(step.Resource)
+		g.Options = resource.featureOptions()
+		g.Options.Migration = !spec.Project.NoMigration
+		if err := g.applyComponent(step, packageName); err != nil {
+			return err
+		}
+	
+		// 记录生成结果
+		entry := applyLockEntry{Spec: hashSpec(*resource, packageName), Files: map[string]string{}}
+		for _, file := range step.Files {
+			entry.Files[filepath.ToSlash(file)] = hashFile(file)
+		}
+		if lock.Resources[step.Resource] == nil {
+			lock.Resources[step.Resource] = map[string]applyLockEntry{}
+		}
+		lock.Resources[step.Resource][step.Component] = entry
+		if err := lock.save(); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已根据 %s 创建 %d 个、更新 %d 个组件，%d 个无变化，%d 个冲突\n",
+		path, counts[applyCreate], counts[applyUpdate], counts[applyUnchanged], counts[applyConflict])
+	if counts[applyConflict] > 0 {
+		return fmt.Errorf("%w: %d 个组件未被修改，确认后可使用--force覆盖", ErrApplyConflict, counts[applyConflict])
+	}
+	return nil
+}
+
+// findResource 按名称查找资源
+func (s *ApplySpec) findResource(name string) *ApplyResource {
+	for i := range s.Resources {
+		if s.Resources[i].Name == name {
+			return &s.Resources[i]
+		}
+	}
+	return nil
+}
+
+// applyComponent 生成单个组件，更新时先删除旧文件，失败时恢复
+func (g *Generator) applyComponent(step applyStep, packageName string) error {
+	backup := map[string][]byte{}
+	if step.Action == applyUpdate {
+		for _, file := range step.Files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			backup[file] = data
+			if err := os.Remove(file); err != nil {
+				return fmt.Errorf("无法删除旧文件: %v", err)
+			}
+		}
+	}
+	
+	var err error
+	switch step.Component {
+	case "model":
+		err = g.GenerateModel(step.Resource, packageName)
+	case "repository":
+		err = g.GenerateRepository(step.Resource, packageName)
+	case "service":
+		err = g.GenerateService(step.Resource, packageName)
+	case "controller":
+		err = g.GenerateController(step.Resource, packageName)
+	case "route":
+		err = g.GenerateRoute(step.Resource, packageName)
+	case "test":
+		err = g.GenerateTest(step.Resource, packageName)
+	case "rbac":
+		err = g.GenerateRBAC(step.Resource, packageName)
+	case "example":
+		err = g.GenerateExample(step.Resource, packageName)
+	}
+	if err != nil {
+		for file, data := range backup {
+			os.WriteFile(file, data, 0644)
+		}
+		return fmt.Errorf("生成 %s 的%s失败: %v", step.Resource, step.Component, err)
+	}
+	return nil
+}
+
+// printApplyPlan 输出生成计划
+func printApplyPlan(steps []applyStep) {
+	symbols := map[string]string{
+		applyCreate:    "+ 创建",
+		applyUpdate:    "~ 更新",
+		applyUnchanged: "= 无变化",
+		applyConflict:  "! 冲突",
+	}
+	fmt.Println("生成计划:")
+	for _, step := range steps {
+		line := fmt.Sprintf("  %s %s %s (%s)", symbols[step.Action], step.Resource, step.Component, strings.Join(step.Files, ", "))
+		if step.Reason != "" {
+			line += ": " + step.Reason
+		}
+		fmt.Println(line)
+	}
+}
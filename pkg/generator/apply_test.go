@@ -0,0 +1,282 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试用资源定义
+const testApplySpec = `
+project:
+  package: example.com/shop
+resources:
+  - name: customer
+    fields:
+      - {name: email, type: string, required: true, unique: true, format: email}
+      - {name: nickName, type: string, size: 8}
+    relations:
+      - {type: has_many, model: Order}
+    options: {soft_delete: true, pagination: true}
+  - name: Order
+    path: /api/v1/orders
+    fields:
+      - {name: status, type: string, required: true, enum: [pending, paid]}
+      - {name: paid, type: bool, required: true}
+    relations:
+      - {type: belongs_to, model: Customer}
+    components: [model, controller]
+`
+
+// 测试读取和校验资源定义
+func TestLoadApplySpec(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	spec, err := LoadApplySpec(createTempFile(t, tempDir, "gs.yaml", testApplySpec))
+	require.NoError(t, err, "读取资源定义失败")
+	require.Len(t, spec.Resources, 2)
+	assert.Equal(t, "Customer", spec.Resources[0].Name, "资源名应转换为Go类型名")
+	assert.Equal(t, []string{"model", "service", "controller", "route", "test", "example"}, spec.Resources[0].components(), "默认组件应与gs create feature一致")
+	assert.Equal(t, FeatureOptions{SoftDelete: true, Paginated: true}, spec.Resources[0].featureOptions())
+	
+	tests := map[string]string{
+		"未知的键":         "resources:\n  - name: a\n    feilds: []\n",
+		"没有资源":         "project: {auth: jwt}\n",
+		"不支持的认证策略":     "project: {auth: oauth}\nresources: [{name: a}]\n",
+		"不支持的字段类型":     "resources: [{name: a, fields: [{name: x, type: blob}]}]\n",
+		"自动生成的字段":      "resources: [{name: a, fields: [{name: created_at, type: time}]}]\n",
+		"未声明的关联资源":     "resources: [{name: a, relations: [{type: belongs_to, model: b}]}]\n",
+		"缺少外键的一对多":     "resources: [{name: a, relations: [{type: has_many, model: b}]}, {name: b}]\n",
+		"不支持的组件":       "resources: [{name: a, components: [model, view]}]\n",
+		"乐观锁缺少仓储":      "resources: [{name: a, options: {versioned: true}, components: [model, service]}]\n",
+		"权限测试缺少test组件": "resources: [{name: a, options: {rbac: true}, components: [model, controller, route, rbac]}]\n",
+	}
+	for name, content := range tests {
+		_, err := LoadApplySpec(createTempFile(t, tempDir, "bad.yaml", content))
+		assert.Error(t, err, "期望在%s时返回错误，但没有", name)
+	}
+}
+
+// 测试资源定义转换为模型字段
+func TestApplyResourceSpec(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	spec, err := LoadApplySpec(createTempFile(t, tempDir, "gs.yaml", testApplySpec))
+	require.NoError(t, err)
+	
+	customer := spec.Resources[0].resourceSpec()
+	assert.Equal(t, Fields{
+		{Name: "Email", Type: "string", JSONName: "email", Gorm: "not null;uniqueIndex", Binding: "required,email", Sample: `"test@example.com"`},
+		{Name: "NickName", Type: "string", JSONName: "nick_name", Gorm: "size:8", Binding: "omitempty,max=8", Sample: `"aaaa"`},
+		{Name: "Orders", Type: "[]Order", JSONName: "orders,omitempty", Gorm: "foreignKey:CustomerID", ReadOnly: true},
+	}, customer.Fields)
+	
+	order := spec.Resources[1].resourceSpec()
+	assert.Equal(t, "/api/v1/orders", order.BasePath)
+	assert.Equal(t, Fields{
+		{Name: "Status", Type: "string", JSONName: "status", Gorm: "not null", Binding: "required,oneof=pending paid", Sample: `"pending"`},
+		{Name: "Paid", Type: "bool", JSONName: "paid", Gorm: "not null"},
+		{Name: "CustomerID", Type: "uint", JSONName: "customer_id", Gorm: "not null;index", Binding: "required"},
+		{Name: "Customer", Type: "*Customer", JSONName: "customer,omitempty", Gorm: "foreignKey:CustomerID", ReadOnly: true},
+	}, order.Fields)
+}
+
+// 测试按资源定义生成代码，重复执行时只更新变化且未被修改的文件
+func TestApply(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":           "{{.Package}} {{.SoftDelete}}\n{{range .Fields}}{{.Name}} {{.Type}}\n{{end}}",
+		"service/service.go.tmpl":       "service {{.Name}} {{.Paginated}}\n",
+		"controller/controller.go.tmpl": "controller {{.Name}}{{range .Fields.Requests}} {{.Name}}{{end}}\n",
+		"route/route.go.tmpl":           "route {{.BasePath}}\n",
+		"test/test.go.tmpl":             "test {{.Name}}\n",
+		"example/example.go.tmpl":       "example {{.Name}}\n",
+		"migration/up.sql.tmpl":         "{{range .Up}}{{.}};\n{{end}}",
+		"migration/down.sql.tmpl":       "{{range .Down}}{{.}};\n{{end}}",
+		"migration/migrations.go.tmpl":  "package migrations",
+		"migration/main.go.tmpl":        "package main",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	specPath := createTempFile(t, tempDir, "gs.yaml", testApplySpec)
+	
+	// 测试只输出计划
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	require.NoError(t, g.Apply(specPath, "myapp", true, false), "输出生成计划失败")
+	_, err = os.Stat(filepath.Join(tempDir, "models", "customer.go"))
+	assert.True(t, os.IsNotExist(err), "只输出计划时不应生成文件")
+	
+	// 测试首次生成
+	require.NoError(t, g.Apply(specPath, "myapp", false, false), "按资源定义生成失败")
+	content, err := os.ReadFile(filepath.Join(tempDir, "models", "customer.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.True(t, strings.HasPrefix(string(content), "example.com/shop true\n"), "应使用资源定义中的包名和选项")
+	content, err = os.ReadFile(filepath.Join(tempDir, "services", "customer_service.go"))
+	require.NoError(t, err, "无法读取生成的服务文件")
+	assert.Equal(t, "service Customer true\n", string(content))
+	_, err = os.Stat(filepath.Join(tempDir, "services", "order_service.go"))
+	assert.True(t, os.IsNotExist(err), "不应生成未声明的组件")
+	_, err = os.Stat(filepath.Join(tempDir, ApplyLockFile))
+	assert.NoError(t, err, "应记录生成结果")
+	
+	// 生成模型时同时生成建表迁移
+	migrations, err := listMigrations()
+	require.NoError(t, err)
+	var names []string
+	for _, migration := range migrations {
+		names = append(names, migration.Name)
+	}
+	assert.ElementsMatch(t, []string{"create_customers", "create_orders"}, names, "应为每个模型生成建表迁移")
+	_, err = os.Stat(filepath.Join(tempDir, SchemaSnapshotFile))
+	assert.NoError(t, err, "应记录表结构快照")
+	
+	// 手动修改文件后，资源定义不变时不覆盖
+	controllerFile := filepath.Join(tempDir, "controllers", "order_controller.go")
+	require.NoError(t, os.WriteFile(controllerFile, []byte("edited\n"), 0644))
+	require.NoError(t, g.Apply(specPath, "myapp", false, false))
+	content, err = os.ReadFile(controllerFile)
+	require.NoError(t, err)
+	assert.Equal(t, "edited\n", string(content), "资源定义未变化时不应重新生成")
+	
+	// 资源定义变化时更新未修改的文件，修改过的文件视为冲突
+	changed := strings.Replace(testApplySpec, "{name: paid, type: bool, required: true}", "{name: paid, type: bool, required: true}\n      - {name: note, type: text}", 1)
+	require.NoError(t, os.WriteFile(specPath, []byte(changed), 0644))
+	err = g.Apply(specPath, "myapp", false, false)
+	assert.ErrorIs(t, err, ErrApplyConflict, "有冲突时应返回错误")
+	content, err = os.ReadFile(filepath.Join(tempDir, "models", "order.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Note string\n", "模型应按新的资源定义重新生成")
+	content, err = os.ReadFile(controllerFile)
+	require.NoError(t, err)
+	assert.Equal(t, "edited\n", string(content), "手动修改过的文件不应被覆盖")
+	
+	// 强制覆盖
+	require.NoError(t, g.Apply(specPath, "myapp", false, true))
+	content, err = os.ReadFile(controllerFile)
+	require.NoError(t, err)
+	assert.Equal(t, "controller Order Status Paid Note CustomerID\n", string(content))
+	
+	// 重新生成模型时不重复生成建表迁移
+	migrations, err = listMigrations()
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2, "已有建表迁移时应跳过")
+	
+	// 声明no_migration时不生成迁移
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, MigrationsDir)))
+	require.NoError(t, os.Remove(filepath.Join(tempDir, ApplyLockFile)))
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "models")))
+	noMigration := strings.Replace(testApplySpec, "package: example.com/shop", "package: example.com/shop\n  no_migration: true", 1)
+	require.NoError(t, os.WriteFile(specPath, []byte(noMigration), 0644))
+	err = g.Apply(specPath, "myapp", false, false)
+	assert.ErrorIs(t, err, ErrApplyConflict, "删除gs.lock后已有的其他文件视为冲突")
+	_, err = os.Stat(filepath.Join(tempDir, "models", "order.go"))
+	assert.NoError(t, err, "应重新生成模型")
+	_, err = os.Stat(filepath.Join(tempDir, MigrationsDir))
+	assert.True(t, os.IsNotExist(err), "声明no_migration时不应生成迁移")
+}
+
+// testUserModel 认证模块的用户模型，用于测试认证模块的建表迁移
+const testUserModel = `package models
+
+type User struct {
+	ID    uint   ` + "`gorm:\"primaryKey\"`" + `
+	Email string ` + "`gorm:\"size:255;not null;uniqueIndex\"`" + `
+}
+
+func (User) TableName() string {
+	return "users"
+}
+`
+
+// 测试按project.auth生成认证模块时同时生成建表迁移，声明no_migration时不生成
+func TestApplyAuth(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"auth/jwt/middlewares/auth.go.tmpl": "package middlewares",
+		"auth/jwt/models/user.go.tmpl":      testUserModel,
+		"repository/base.go.tmpl":           "package repositories",
+		"model/model.go.tmpl":               "{{.Name}}\n",
+		"migration/up.sql.tmpl":             "{{range .Up}}{{.}};\n{{end}}",
+		"migration/down.sql.tmpl":           "{{range .Down}}{{.}};\n{{end}}",
+		"migration/migrations.go.tmpl":      "package migrations",
+		"migration/main.go.tmpl":            "package main",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	spec := "project:\n  package: example.com/shop\n  auth: jwt\nresources:\n  - name: note\n    components: [model]\n"
+	specPath := createTempFile(t, tempDir, "gs.yaml", spec)
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	require.NoError(t, g.Apply(specPath, "myapp", false, false), "按资源定义生成失败")
+	
+	// 用户表的建表迁移
+	up, err := filepath.Glob(filepath.Join(MigrationsDir, "*_create_auth_tables.up.sql"))
+	require.NoError(t, err)
+	require.Len(t, up, 1, "应生成认证模块的建表迁移")
+	content, err := os.ReadFile(up[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "CREATE TABLE `users`", "迁移应创建users表")
+	
+	// 声明no_migration时不生成迁移
+	require.NoError(t, os.RemoveAll(MigrationsDir))
+	require.NoError(t, os.RemoveAll("middlewares"))
+	require.NoError(t, os.RemoveAll("models"))
+	require.NoError(t, os.Remove(ApplyLockFile))
+	noMigration := strings.Replace(spec, "auth: jwt", "auth: jwt\n  no_migration: true", 1)
+	require.NoError(t, os.WriteFile(specPath, []byte(noMigration), 0644))
+	require.NoError(t, g.Apply(specPath, "myapp", false, false))
+	_, err = os.Stat(filepath.Join("models", "user.go"))
+	assert.NoError(t, err, "应重新生成认证模块")
+	_, err = os.Stat(MigrationsDir)
+	assert.True(t, os.IsNotExist(err), "声明no_migration时不应生成迁移")
+}
+
+// 测试字段名转换为snake_case
+func TestToSnakeName(t *testing.T) {
+	tests := map[string]string{
+		"customer_email": "customer_email",
+		"CustomerEmail":  "customer_email",
+		"nickName":       "nick_name",
+		"CustomerID":     "customer_id",
+		"HTTPServer":     "http_server",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, toSnakeName(input), "toSnakeName(%q)", input)
+	}
+}
@@ -0,0 +1,436 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// AuthStrategies 支持的认证策略及说明
+var AuthStrategies = map[string]string{
+	"jwt":    "JWT访问令牌 + 刷新令牌",
+	"apikey": "API Key，适用于服务间调用",
+}
+
+// authConfigFields 各认证策略在项目中加入的配置字段和注册路由的函数
+var authConfigFields = map[string]struct {
+	Name     string // config.Config中的字段名
+	Field    string // 字段声明
+	Default  string // DefaultConfig中的默认值
+	Key      string // 配置文件中的顶层键
+	Validate string // Validate中校验配置的语句，生产环境不接受只用于本地开发的配置值
+	Register string // routes包中注册认证路由的函数
+}{
+	"jwt":    {"Auth", "Auth auth.Config `json:\"auth\"`", "Auth: auth.DefaultConfig(),", "auth", "errs = append(errs, c.Auth.Validate(Production())...)", "RegisterAuthRoutes"},
+	"apikey": {"APIKey", "APIKey auth.APIKeyConfig `json:\"api_key\"`", "APIKey: auth.DefaultAPIKeyConfig(),", "api_key", "errs = append(errs, c.APIKey.Validate()...)", "RegisterAPIKeyRoutes"},
+}
+
+// authEnvFile 保存本地开发签名密钥的环境变量文件，由项目的config.Load读取
+const authEnvFile = ".env"
+
+// authDevSecretPrefix 本地开发签名密钥的前缀，与认证模块的auth.DevSecretPrefix一致
+const authDevSecretPrefix = "dev-only-"
+
+// AuthData 认证模块模板数据
+type AuthData struct {
+	Strategy string // 认证策略
+	Package  string // 项目包名
+}
+
+// AuthStrategyNames 返回排序后的认证策略列表
+func AuthStrategyNames() []string {
+	names := make([]string, 0, len(AuthStrategies))
+	for name := range AuthStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateAuth 生成认证模块，包括模型、仓储、服务、控制器、中间件、路由和测试，
+// Options.Migration为true时同时生成认证模块的建表迁移
+func (g *Generator) GenerateAuth(packageName string, strategy string) error {
+	// 校验认证策略
+	if strategy == "" {
+		strategy = "jwt"
+	}
+	strategy = strings.ToLower(strategy)
+	if _, ok := AuthStrategies[strategy]; !ok {
+		return fmt.Errorf("不支持的认证策略 '%s'，可用策略: %s", strategy, strings.Join(AuthStrategyNames(), ", "))
+	}
+	
+	// 准备模板数据
+	data := AuthData{
+		Strategy: strategy,
+		Package:  packageName,
+	}
+	
+	// 生成认证模块文件
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "auth", strategy)
+	if err := g.generateTree(templatesDir, ".", data); err != nil {
+		return fmt.Errorf("生成认证模块失败: %v", err)
+	}
+	
+	// 生成仓储公共定义（仅首次）
+	baseTemplate := filepath.Join(g.TemplatesDir, "component", "repository", "base.go.tmpl")
+	if err := g.generateSharedFile(baseTemplate, filepath.Join("repositories", "repository.go"), data); err != nil {
+		return fmt.Errorf("生成仓储公共定义失败: %v", err)
+	}
+	
+	// 在项目配置中加入认证配置，并在路由中注册认证路由
+	if err := injectAuthConfig(packageName, strategy); err != nil {
+		return err
+	}
+	if err := injectAuthYAML(strategy); err != nil {
+		return err
+	}
+	if err := injectAuthEnv(strategy); err != nil {
+		return err
+	}
+	if err := injectAuthRoutes(packageName, strategy); err != nil {
+		return err
+	}
+	
+	// 按模型与表结构快照的差异生成用户、刷新令牌或API Key表的建表迁移，与生成模型时一致
+	if g.Options.Migration {
+		if err := g.DiffMigration("create_auth_tables", false, packageName); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已成功生成 %s 认证模块\n", strategy)
+	return nil
+}
+
+// injectAuthConfig 在config.Config中加入认证策略的配置字段、默认值和校验，项目没有配置文件时跳过
+func injectAuthConfig(packageName string, strategy string) error {
+	configFile := filepath.Join("config", "config.go")
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		return nil
+	}
+	
+	field := authConfigFields[strategy]
+	err := injectFile(configFile,
+		func(src []byte) ([]byte, error) {
+			return injectImport(src, packageName+"/auth")
+		},
+		func(src []byte) ([]byte, error) {
+			return injectStructField(src, "Config", field.Name, field.Field)
+		},
+		func(src []byte) ([]byte, error) {
+			return injectCompositeField(src, "DefaultConfig", field.Name, field.Default)
+		},
+		func(src []byte) ([]byte, error) {
+			// 在Validate返回校验错误之前加入认证配置的校验
+			return injectBeforeStmt(src, "Validate", func(stmt ast.Stmt) bool {
+				ifStmt, ok := stmt.(*ast.IfStmt)
+				if !ok {
+					return false
+				}
+				cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+				if !ok {
+					return false
+				}
+				call, ok := cond.X.(*ast.CallExpr)
+				if !ok {
+					return false
+				}
+				fun, ok := call.Fun.(*ast.Ident)
+				return ok && fun.Name == "len"
+			}, field.Validate)
+		},
+		formatSource,
+	)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("已在 %s 中加入认证配置\n", configFile)
+	return nil
+}
+
+// injectAuthYAML 在config.yaml中加入认证策略的配置，已有同名配置或项目没有config.yaml时跳过
+func injectAuthYAML(strategy string) error {
+	configFile := "config.yaml"
+	content, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("无法读取文件: %v", err)
+	}
+	
+	key := authConfigFields[strategy].Key
+	if regexp.MustCompile(`(?m)^` + key + `:`).Match(content) {
+		return nil
+	}
+	
+	var block string
+	switch strategy {
+	case "jwt":
+		block = `auth:
+  algorithm: HS256
+  # 签名密钥至少32个字符，通过AUTH_SECRET环境变量设置，本地开发使用.env中随机生成的密钥，
+  # APP_ENV=prod时不读取.env，也不接受该密钥
+  secret: "${AUTH_SECRET:-}"
+  access_token_ttl: 15m
+  refresh_token_ttl: 168h
+`
+	case "apikey":
+		block = `api_key:
+  header: X-API-Key
+  query_param: api_key
+  # 管理接口的引导Key，拥有全部权限，至少32个字符，为空时禁用，通过API_KEY_ADMIN_KEY环境变量设置
+  admin_key: "${API_KEY_ADMIN_KEY:-}"
+`
+	}
+	if err := appendLines(configFile, "\n"+block); err != nil {
+		return err
+	}
+	
+	fmt.Printf("已在 %s 中加入 %s 配置\n", configFile, key)
+	return nil
+}
+
+// injectAuthEnv 为JWT认证在.env中写入随机生成的本地开发签名密钥，并把.env加入.gitignore，
+// 密钥不进入版本库和镜像，生产环境通过AUTH_SECRET环境变量设置。项目没有config.yaml或.env中已有密钥时跳过
+func injectAuthEnv(strategy string) error {
+	if strategy != "jwt" || !utils.FileExists("config.yaml") {
+		return nil
+	}
+	
+	content, err := os.ReadFile(authEnvFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("无法读取文件: %v", err)
+	}
+	if !regexp.MustCompile(`(?m)^(export\s+)?AUTH_SECRET=`).Match(content) {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("无法生成签名密钥: %v", err)
+		}
+		line := fmt.Sprintf("# JWT签名密钥，只用于本地开发，不要提交到版本库\nAUTH_SECRET=%s%s\n", authDevSecretPrefix, hex.EncodeToString(secret))
+		if err := appendLines(authEnvFile, line); err != nil {
+			return err
+		}
+		fmt.Printf("已在 %s 中写入本地开发的签名密钥\n", authEnvFile)
+	}
+	
+	// .env不提交到版本库
+	ignore, err := os.ReadFile(".gitignore")
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("无法读取文件: %v", err)
+	}
+	if !regexp.MustCompile(`(?m)^/?\.env\s*$`).Match(ignore) {
+		return appendLines(".gitignore", authEnvFile+"\n")
+	}
+	return nil
+}
+
+// appendLines 在文件末尾追加内容，文件不存在时创建
+func appendLines(path string, lines string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("无法读取文件: %v", err)
+	}
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	if err := os.WriteFile(path, append(content, lines...), 0644); err != nil {
+		return fmt.Errorf("无法写入文件: %v", err)
+	}
+	return nil
+}
+
+// injectAuthRoutes 在routes.RegisterRoutes中注册认证路由，RegisterRoutes增加config.Config参数
+// 传入认证配置，main.go中的调用随之传入配置；JWT认证路由可能返回错误，RegisterRoutes改为返回error，
+// 由main.go处理。项目没有路由文件时跳过
+func injectAuthRoutes(packageName string, strategy string) error {
+	routesFile := filepath.Join("routes", "routes.go")
+	if _, err := os.Stat(routesFile); os.IsNotExist(err) {
+		return nil
+	}
+	
+	err := injectFile(routesFile,
+		func(src []byte) ([]byte, error) {
+			return injectRegisterAuthRoutes(src, strategy)
+		},
+		func(src []byte) ([]byte, error) {
+			return injectImport(src, packageName+"/config")
+		},
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已在 %s 中注册认证路由\n", routesFile)
+	
+	mainFile := "main.go"
+	if _, err := os.Stat(mainFile); os.IsNotExist(err) {
+		return nil
+	}
+	return injectFile(mainFile, func(src []byte) ([]byte, error) {
+		return injectRegisterRoutesCall(src, strategy)
+	})
+}
+
+// injectRegisterAuthRoutes 在RegisterRoutes的开头调用认证策略的路由注册函数，已调用时原样返回
+func injectRegisterAuthRoutes(src []byte, strategy string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	fn := findFunc(file, "RegisterRoutes")
+	if fn == nil {
+		return nil, fmt.Errorf("未找到函数 RegisterRoutes")
+	}
+	register := authConfigFields[strategy].Register
+	registered := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == register {
+				registered = true
+			}
+		}
+		return !registered
+	})
+	if registered {
+		return src, nil
+	}
+	
+	// 按类型查找参数名
+	router := paramName(fn, "gin", "Engine")
+	db := paramName(fn, "gorm", "DB")
+	cfg := paramName(fn, "config", "Config")
+	if router == "" || db == "" {
+		return nil, fmt.Errorf("RegisterRoutes 没有 *gin.Engine 和 *gorm.DB 参数，无法注册认证路由")
+	}
+	
+	// JWT认证路由返回的错误由RegisterRoutes返回
+	returnsError := false
+	if results := fn.Type.Results; results != nil {
+		if ident, ok := results.List[0].Type.(*ast.Ident); len(results.List) == 1 && ok && ident.Name == "error" {
+			returnsError = true
+		} else {
+			return nil, fmt.Errorf("RegisterRoutes 的返回值不是error，无法注册认证路由")
+		}
+	}
+	if cfg == "" {
+		cfg = "cfg"
+	}
+	
+	var code string
+	switch strategy {
+	case "jwt":
+		code = fmt.Sprintf(`	// 认证路由，返回的认证中间件传给受保护资源的路由，例如 RegisterOrderRoutes(router, requireAuth)
+	if _, err := %s(%s, %s, %s.Auth); err != nil {
+		return err
+	}`, register, router, db, cfg)
+	case "apikey":
+		code = fmt.Sprintf(`	// API Key管理路由，返回的中间件传给服务间调用的路由，例如 RegisterOrderRoutes(router, requireAPIKey)
+	%s(%s, %s, %s.APIKey)`, register, router, db, cfg)
+	}
+	
+	// 从后往前修改，前面的偏移量不受影响
+	if strategy == "jwt" && !returnsError {
+		offset := fset.Position(fn.Body.Rbrace).Offset
+		lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+		src = splice(src, lineStart, lineStart, "\treturn nil\n")
+	}
+	offset := fset.Position(fn.Body.Lbrace).Offset + 1
+	src = splice(src, offset, offset, "\n"+code+"\n")
+	closing := fset.Position(fn.Type.Params.Closing).Offset
+	if strategy == "jwt" && !returnsError {
+		src = splice(src, closing+1, closing+1, " error")
+	}
+	if paramName(fn, "config", "Config") == "" {
+		src = splice(src, closing, closing, ", "+cfg+" config.Config")
+	}
+	return src, nil
+}
+
+// injectRegisterRoutesCall 在main.go调用routes.RegisterRoutes时传入配置，RegisterRoutes返回error时
+// 注册失败则退出，已传入时原样返回
+func injectRegisterRoutesCall(src []byte, strategy string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	// 配置变量为config.Load()的返回值
+	var cfg string
+	var stmt *ast.ExprStmt
+	var call *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if c, ok := n.Rhs[0].(*ast.CallExpr); ok && isSelectorCall(c, "config", "Load") {
+				if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+					cfg = ident.Name
+				}
+			}
+		case *ast.ExprStmt:
+			if c, ok := n.X.(*ast.CallExpr); ok && isSelectorCall(c, "routes", "RegisterRoutes") {
+				stmt = n
+			}
+		case *ast.CallExpr:
+			if isSelectorCall(n, "routes", "RegisterRoutes") {
+				call = n
+			}
+		}
+		return true
+	})
+	if call == nil || cfg == "" {
+		return nil, fmt.Errorf("main.go 中未找到 config.Load 和 routes.RegisterRoutes 的调用")
+	}
+	
+	// 调用只有引擎和数据库连接两个参数时传入配置
+	if len(call.Args) == 2 {
+		offset := fset.Position(call.Rparen).Offset
+		src = splice(src, offset, offset, ", "+cfg)
+	}
+	if strategy != "jwt" || stmt == nil {
+		return src, nil
+	}
+	
+	// 调用改写为检查返回的错误
+	start := fset.Position(stmt.Pos()).Offset
+	end := fset.Position(stmt.End()).Offset
+	if len(call.Args) == 2 {
+		end += len(", " + cfg)
+	}
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	indent := string(src[lineStart:start])
+	code := fmt.Sprintf("if err := %s; err != nil {\n%s\tfatal(\"无法注册路由\", \"error\", err)\n%s}", src[start:end], indent, indent)
+	return splice(src, start, end, code), nil
+}
+
+// paramName 返回函数中类型为pkg.typeName或*pkg.typeName的参数名，没有时返回空字符串
+func paramName(fn *ast.FuncDecl, pkg string, typeName string) string {
+	for _, field := range fn.Type.Params.List {
+		expr := field.Type
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != typeName || len(field.Names) == 0 {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == pkg {
+			return field.Names[0].Name
+		}
+	}
+	return ""
+}
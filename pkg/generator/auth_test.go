@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfigSource 项目config包的最小结构，用于测试认证配置的注入
+const testConfigSource = `package config
+
+import "fmt"
+
+type Config struct {
+	Port int ` + "`json:\"port\"`" + `
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Port: 8080,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []string
+	if c.Port < 1 {
+		errs = append(errs, fmt.Sprintf("port %d", c.Port))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+`
+
+// testRoutesSource 项目路由文件的最小结构，用于测试认证路由的注册
+const testRoutesSource = `package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterRoutes(router *gin.Engine, db *gorm.DB) {
+	RegisterProductRoutes(router)
+}
+`
+
+// testMainSource 项目main.go的最小结构，用于测试RegisterRoutes调用的改写
+const testMainSource = `package main
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("无法加载配置", "error", err)
+	}
+
+	// 注册路由
+	routes.RegisterRoutes(r, db)
+}
+`
+
+// 测试生成认证模块
+func TestGenerateAuth(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建templates目录结构
+	authDir := filepath.Join(tempDir, "templates", "component", "auth", "jwt")
+	require.NoError(t, os.MkdirAll(filepath.Join(authDir, "middlewares"), 0755), "无法创建模板目录")
+	repositoryDir := filepath.Join(tempDir, "templates", "component", "repository")
+	require.NoError(t, os.MkdirAll(repositoryDir, 0755), "无法创建模板目录")
+	
+	// 创建测试模板
+	err = os.WriteFile(filepath.Join(authDir, "middlewares", "auth.go.tmpl"), []byte("import \"{{.Package}}/auth\" // {{.Strategy}}\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = os.WriteFile(filepath.Join(repositoryDir, "base.go.tmpl"), []byte("package repositories\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	
+	// 创建项目配置文件
+	require.NoError(t, os.MkdirAll("config", 0755), "无法创建config目录")
+	err = os.WriteFile(filepath.Join("config", "config.go"), []byte(testConfigSource), 0644)
+	require.NoError(t, err, "无法创建配置文件")
+	require.NoError(t, os.WriteFile("config.yaml", []byte("server:\n  port: 8080\n"), 0644), "无法创建配置文件")
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试生成认证模块
+	err = g.GenerateAuth("myapp", "JWT")
+	require.NoError(t, err, "生成认证模块失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "middlewares", "auth.go"))
+	require.NoError(t, err, "无法读取生成的认证中间件")
+	assert.Equal(t, "import \"myapp/auth\" // jwt\n", string(content), "生成的认证中间件内容不符合预期")
+	
+	_, err = os.Stat(filepath.Join(tempDir, "repositories", "repository.go"))
+	assert.NoError(t, err, "仓储公共定义未生成")
+	
+	// 验证配置注入
+	content, err = os.ReadFile(filepath.Join("config", "config.go"))
+	require.NoError(t, err, "无法读取配置文件")
+	assert.Contains(t, string(content), "\"myapp/auth\"", "配置文件未导入auth包")
+	assert.Regexp(t, `Auth\s+auth\.Config`, string(content), "配置文件未加入Auth字段")
+	content, err = os.ReadFile("config.yaml")
+	require.NoError(t, err, "无法读取配置文件")
+	assert.Regexp(t, `\nauth:\n  algorithm: HS256\n(  #.*\n)*  secret: "\$\{AUTH_SECRET:-\}"\n`, string(content), "config.yaml未加入认证配置，且不应包含默认密钥")
+	
+	// 本地开发的签名密钥只写入不提交的.env
+	content, err = os.ReadFile(".env")
+	require.NoError(t, err, "无法读取.env")
+	assert.Regexp(t, `(?m)^AUTH_SECRET=dev-only-[0-9a-f]{64}$`, string(content), ".env未写入本地开发的签名密钥")
+	content, err = os.ReadFile(".gitignore")
+	require.NoError(t, err, "无法读取.gitignore")
+	assert.Equal(t, ".env\n", string(content), ".env应加入.gitignore")
+	
+	// 已有密钥时不重新生成
+	env, err := os.ReadFile(".env")
+	require.NoError(t, err, "无法读取.env")
+	require.NoError(t, injectAuthEnv("jwt"), "写入.env失败")
+	again, err := os.ReadFile(".env")
+	require.NoError(t, err, "无法读取.env")
+	assert.Equal(t, string(env), string(again), "已有密钥时不应修改.env")
+	
+	// 测试文件已存在的情况
+	err = g.GenerateAuth("myapp", "jwt")
+	assert.Error(t, err, "期望在认证模块已存在时返回错误，但没有")
+	
+	// 测试不支持的策略
+	err = g.GenerateAuth("myapp", "unknown")
+	assert.Error(t, err, "期望对不支持的认证策略返回错误，但没有")
+}
+
+// 测试不同认证策略加入的配置字段
+func TestInjectAuthConfig(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 没有配置文件时跳过
+	assert.NoError(t, injectAuthConfig("myapp", "jwt"), "没有配置文件时不应返回错误")
+	
+	// 创建项目配置文件
+	require.NoError(t, os.MkdirAll("config", 0755), "无法创建config目录")
+	err = os.WriteFile(filepath.Join("config", "config.go"), []byte(testConfigSource), 0644)
+	require.NoError(t, err, "无法创建配置文件")
+	
+	require.NoError(t, injectAuthConfig("myapp", "jwt"), "加入JWT配置失败")
+	require.NoError(t, injectAuthConfig("myapp", "apikey"), "加入API Key配置失败")
+	
+	content, err := os.ReadFile(filepath.Join("config", "config.go"))
+	require.NoError(t, err, "无法读取配置文件")
+	assert.Contains(t, string(content), "\tAuth   auth.Config       `json:\"auth\"`\n", "配置文件未加入Auth字段")
+	assert.Contains(t, string(content), "\tAPIKey auth.APIKeyConfig `json:\"api_key\"`\n", "配置文件未加入APIKey字段")
+	assert.Contains(t, string(content), "\t\tAuth:   auth.DefaultConfig(),\n", "默认配置未加入Auth字段")
+	assert.Contains(t, string(content), "\t\tAPIKey: auth.DefaultAPIKeyConfig(),\n", "默认配置未加入APIKey字段")
+	assert.Contains(t, string(content), "\terrs = append(errs, c.Auth.Validate(Production())...)\n\terrs = append(errs, c.APIKey.Validate()...)\n\tif len(errs) > 0 {", "Validate未校验认证配置")
+	
+	// 重复注入时不修改
+	require.NoError(t, injectAuthConfig("myapp", "jwt"), "重复加入JWT配置失败")
+	again, err := os.ReadFile(filepath.Join("config", "config.go"))
+	require.NoError(t, err, "无法读取配置文件")
+	assert.Equal(t, string(content), string(again), "重复注入不应修改配置文件")
+}
+
+// 测试在路由中注册认证路由，并在main.go中传入配置
+func TestInjectAuthRoutes(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 没有路由文件时跳过
+	assert.NoError(t, injectAuthRoutes("myapp", "jwt"), "没有路由文件时不应返回错误")
+	
+	// 创建项目文件
+	require.NoError(t, os.MkdirAll("routes", 0755), "无法创建routes目录")
+	require.NoError(t, os.WriteFile(filepath.Join("routes", "routes.go"), []byte(testRoutesSource), 0644), "无法创建路由文件")
+	require.NoError(t, os.WriteFile("main.go", []byte(testMainSource), 0644), "无法创建main.go")
+	
+	// API Key路由不返回错误
+	require.NoError(t, injectAuthRoutes("myapp", "apikey"), "注册API Key路由失败")
+	content, err := os.ReadFile(filepath.Join("routes", "routes.go"))
+	require.NoError(t, err, "无法读取路由文件")
+	assert.Contains(t, string(content), "\t\"myapp/config\"\n", "路由文件未导入config包")
+	assert.Contains(t, string(content), "func RegisterRoutes(router *gin.Engine, db *gorm.DB, cfg config.Config) {\n")
+	assert.Contains(t, string(content), "\tRegisterAPIKeyRoutes(router, db, cfg.APIKey)\n\n\tRegisterProductRoutes(router)\n}")
+	content, err = os.ReadFile("main.go")
+	require.NoError(t, err, "无法读取main.go")
+	assert.Contains(t, string(content), "\troutes.RegisterRoutes(r, db, cfg)\n")
+	
+	// JWT路由返回错误，main.go在注册失败时退出
+	require.NoError(t, injectAuthRoutes("myapp", "jwt"), "注册JWT路由失败")
+	content, err = os.ReadFile(filepath.Join("routes", "routes.go"))
+	require.NoError(t, err, "无法读取路由文件")
+	assert.Contains(t, string(content), "func RegisterRoutes(router *gin.Engine, db *gorm.DB, cfg config.Config) error {\n")
+	assert.Contains(t, string(content), "\tif _, err := RegisterAuthRoutes(router, db, cfg.Auth); err != nil {\n\t\treturn err\n\t}\n")
+	assert.Contains(t, string(content), "\tRegisterProductRoutes(router)\n\treturn nil\n}")
+	formatted, err := format.Source(content)
+	require.NoError(t, err, "注入后的路由文件无法解析")
+	assert.Equal(t, string(formatted), string(content), "注入后的路由文件应符合gofmt格式")
+	content, err = os.ReadFile("main.go")
+	require.NoError(t, err, "无法读取main.go")
+	assert.Contains(t, string(content), "\tif err := routes.RegisterRoutes(r, db, cfg); err != nil {\n\t\tfatal(\"无法注册路由\", \"error\", err)\n\t}\n")
+	
+	// 重复注册时不修改
+	require.NoError(t, injectAuthRoutes("myapp", "jwt"), "重复注册JWT路由失败")
+	again, err := os.ReadFile("main.go")
+	require.NoError(t, err, "无法读取main.go")
+	assert.Equal(t, string(content), string(again), "重复注册不应修改main.go")
+	
+	// RegisterRoutes没有数据库连接时返回错误
+	require.NoError(t, os.WriteFile(filepath.Join("routes", "routes.go"), []byte("package routes\n\nfunc RegisterRoutes(router *gin.Engine) {\n}\n"), 0644))
+	assert.Error(t, injectAuthRoutes("myapp", "jwt"), "期望在RegisterRoutes没有数据库连接时返回错误，但没有")
+}
+
+// 测试受保护的资源按项目中已生成的认证中间件选择认证策略
+func TestFeatureAuthStrategy(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Protected = true
+	
+	// 没有认证中间件
+	err = g.GenerateFeature("Order", "myapp")
+	require.Error(t, err, "期望在缺少认证中间件时返回错误，但没有")
+	assert.Contains(t, err.Error(), "gs create auth")
+	
+	// 只有API Key中间件
+	require.NoError(t, os.MkdirAll("middlewares", 0755), "无法创建middlewares目录")
+	require.NoError(t, os.WriteFile(filepath.Join("middlewares", "api_key.go"), []byte("package middlewares\n"), 0644))
+	assert.Equal(t, "apikey", g.featureOptions().Auth, "应使用API Key认证")
+	
+	// 权限校验需要登录用户
+	g.Options.RBAC = true
+	err = g.GenerateFeature("Order", "myapp")
+	require.Error(t, err, "期望在只有API Key认证时拒绝--rbac，但没有")
+	assert.Contains(t, err.Error(), "--strategy=jwt")
+	
+	// 两者都存在时使用JWT
+	require.NoError(t, os.WriteFile(filepath.Join("middlewares", "auth.go"), []byte("package middlewares\n"), 0644))
+	assert.Equal(t, "jwt", g.featureOptions().Auth, "应使用JWT认证")
+}
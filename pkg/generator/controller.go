@@ -11,11 +11,48 @@ import (
 
 // ControllerData 控制器模板数据
 type ControllerData struct {
-	Name         string // 控制器名称，首字母大写
-	PluralName   string // 复数名称，用于列表方法
-	ResourceName string // 资源名称，用于URL路径
-	VarName      string // 变量名称，首字母小写
-	Package      string // 项目包名
+	Name         string      // 控制器名称，首字母大写
+	PluralName   string      // 复数名称，用于列表方法
+	ResourceName string      // 资源名称，用于URL路径
+	VarName      string      // 变量名称，首字母小写
+	Package      string      // 项目包名
+	Fields       Fields      // 模型字段，为空时使用默认的Name字段
+	Handlers     Handlers    // 自定义接口
+	CRUD         bool        // 是否生成增删改查接口
+	Ops          Operations  // 生成的增删改查接口
+	CreateBody   RequestBody // 创建接口绑定的请求结构
+	UpdateBody   RequestBody // 更新接口绑定的请求结构
+	FeatureOptions
+}
+
+// ToModel 返回将请求结构request转换为模型的复合字面量
+func (d ControllerData) ToModel(body RequestBody) string {
+	if len(d.Fields) == 0 && body.Type == "" {
+		return fmt.Sprintf("models.%s{Name: request.Name}", d.Name)
+	}
+	fields := body.ModelFields(d.Fields)
+	if len(fields) == 0 {
+		return fmt.Sprintf("models.%s{}", d.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "models.%s{\n", d.Name)
+	for _, field := range fields {
+		fmt.Fprintf(&b, "\t\t%-*s request.%s,\n", fields.KeyWidth(), field.Name+":", field.Name)
+	}
+	b.WriteString("\t}")
+	return b.String()
+}
+
+// PatchFields 返回PATCH可以更新的模型字段名列表，用于bindPatch的参数
+func (d ControllerData) PatchFields(body RequestBody) string {
+	if len(d.Fields) == 0 && body.Type == "" {
+		return `"Name"`
+	}
+	var names []string
+	for _, field := range body.ModelFields(d.Fields) {
+		names = append(names, fmt.Sprintf("%q", field.Name))
+	}
+	return strings.Join(names, ", ")
 }
 
 // GenerateController 生成控制器代码
@@ -24,12 +61,24 @@ func (g *Generator) GenerateController(name string, packageName string) error {
 	name = formatName(name)
 	
 	// 准备模板数据
+	spec := g.resourceSpec(name)
 	data := ControllerData{
-		Name:         name,
-		PluralName:   PluralForm(name),
-		ResourceName: strings.ToLower(name) + "s",
-		VarName:      strings.ToLower(name[:1]) + name[1:],
-		Package:      packageName,
+		Name:           name,
+		PluralName:     PluralForm(name),
+		ResourceName:   strings.ToLower(name) + "s",
+		VarName:        strings.ToLower(name[:1]) + name[1:],
+		Package:        packageName,
+		Fields:         spec.Fields,
+		Handlers:       spec.Handlers,
+		CRUD:           spec.CRUD,
+		Ops:            g.operations(name),
+		FeatureOptions: g.featureOptions(),
+	}
+	if data.Ops.Create {
+		data.CreateBody = g.requestBody(name, spec.CreateRequest)
+	}
+	if data.Ops.Update() {
+		data.UpdateBody = g.requestBody(name, spec.UpdateRequest)
 	}
 	
 	// 确保目录存在
@@ -46,6 +95,15 @@ func (g *Generator) GenerateController(name string, packageName string) error {
 		return fmt.Errorf("控制器文件已存在: %s", outputFile)
 	}
 	
+	// 乐观锁模式下生成ETag/If-Match等公共辅助函数（仅首次）
+	if g.Options.Versioned {
+		helpersFile := filepath.Join(outputDir, "helpers.go")
+		helpersTemplate := filepath.Join(g.TemplatesDir, "component", "controller", "helpers.go.tmpl")
+		if err := g.generateSharedFile(helpersTemplate, helpersFile, data); err != nil {
+			return fmt.Errorf("生成控制器辅助函数失败: %v", err)
+		}
+	}
+	
 	// 生成控制器文件
 	templatePath := filepath.Join(g.TemplatesDir, "component", "controller", "controller.go.tmpl")
 	if err := g.GenerateFromTemplate(templatePath, outputFile, data); err != nil {
@@ -67,4 +125,4 @@ func formatName(name string) string {
 	}
 	
 	return name
-} 
\ No newline at end of file
+}
@@ -26,6 +26,24 @@ func TestControllerData(t *testing.T) {
 	assert.Equal(t, "myapp", data.Package, "Package字段设置不正确")
 }
 
+// 测试PATCH可以更新的字段，只读字段和请求结构中类型不同的字段不可更新
+func TestControllerDataPatchFields(t *testing.T) {
+	assert.Equal(t, `"Name"`, ControllerData{Name: "User"}.PatchFields(RequestBody{}), "未定义字段时使用默认的Name字段")
+	
+	data := ControllerData{Name: "Order", Fields: Fields{
+		{Name: "Title", Type: "string", JSONName: "title"},
+		{Name: "Total", Type: "float64", JSONName: "total"},
+		{Name: "Code", Type: "string", JSONName: "code", ReadOnly: true},
+	}}
+	assert.Equal(t, `"Title", "Total"`, data.PatchFields(RequestBody{}))
+	
+	body := RequestBody{Type: "models.UpdateOrder", Fields: Fields{
+		{Name: "Title", Type: "string", JSONName: "title"},
+		{Name: "Total", Type: "string", JSONName: "total"},
+	}}
+	assert.Equal(t, `"Title"`, data.PatchFields(body))
+}
+
 // 测试生成控制器
 func TestGenerateController(t *testing.T) {
 	// 创建测试环境
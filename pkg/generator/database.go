@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// GenerateFeaturesFromDB 读取数据库中的表结构，为每个表生成带关联的模型以及服务、控制器、路由和测试，
+// tables为空时生成全部表。数据库类型需先通过schema.Register注册，例如导入 pkg/schema/sqlite
+func (g *Generator) GenerateFeaturesFromDB(url string, tables []string, packageName string) error {
+	inspected, err := schema.Inspect(url)
+	if err != nil {
+		return err
+	}
+	
+	selected, err := selectTables(inspected, tables)
+	if err != nil {
+		return err
+	}
+	
+	specs, err := tableSpecs(inspected, selected, true)
+	if err != nil {
+		return err
+	}
+	
+	// 乐观锁需要表中已有整数类型的version列
+	if g.Options.Versioned {
+		for _, table := range selected {
+			if integerKey(table) != nil && !hasVersionField(specs[tableModelName(table.Name)]) {
+				return fmt.Errorf("表 %s 没有整数类型的version列，无法启用乐观锁", table.Name)
+			}
+		}
+	}
+	
+	if g.Specs == nil {
+		g.Specs = map[string]*ResourceSpec{}
+	}
+	for name, spec := range specs {
+		g.Specs[name] = spec
+	}
+	
+	// 生成功能，没有单列整数主键的表无法按ID增删改查，只生成模型
+	for _, table := range selected {
+		name := tableModelName(table.Name)
+		if integerKey(table) == nil {
+			if err := g.GenerateModel(name, packageName); err != nil {
+				return err
+			}
+			fmt.Printf("表 %s 没有单列整数主键，只生成模型\n", table.Name)
+			continue
+		}
+		if err := g.GenerateFeature(name, packageName); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已根据 %s 生成 %d 个表的代码\n", url, len(selected))
+	return nil
+}
+
+// hasVersionField 判断字段中是否有乐观锁使用的Version列，并将其类型统一为int
+func hasVersionField(spec *ResourceSpec) bool {
+	for i, field := range spec.Fields {
+		if field.Name == "Version" && isIntegerType(field.Type) {
+			spec.Fields[i].Type = "int"
+			spec.Fields[i].ReadOnly = true
+			spec.Fields[i].Binding = ""
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// fakeIntrospector 返回固定表结构的测试数据库
+type fakeIntrospector struct {
+	tables []*schema.Table
+}
+
+func (f *fakeIntrospector) Tables() ([]*schema.Table, error) {
+	return f.tables, nil
+}
+
+func (f *fakeIntrospector) Close() error {
+	return nil
+}
+
+func init() {
+	schema.Register("fake", func(dsn string) (schema.Introspector, error) {
+		return &fakeIntrospector{tables: []*schema.Table{
+			{
+				Name:       "users",
+				PrimaryKey: []string{"id"},
+				Columns: []*schema.Column{
+					{Name: "id", Type: "INTEGER", NotNull: true, AutoIncrement: true},
+					{Name: "email", Type: "TEXT", NotNull: true},
+				},
+			},
+			{
+				Name:       "orders",
+				PrimaryKey: []string{"order_no"},
+				Columns: []*schema.Column{
+					{Name: "order_no", Type: "INTEGER", NotNull: true, AutoIncrement: true},
+					{Name: "user_id", Type: "INTEGER", NotNull: true},
+					{Name: "version", Type: "INTEGER", NotNull: true, Default: "0"},
+				},
+				ForeignKeys: []schema.ForeignKey{{Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}}},
+			},
+			{
+				Name:       "order_tags",
+				PrimaryKey: []string{"order_no", "tag"},
+				Columns: []*schema.Column{
+					{Name: "order_no", Type: "INTEGER", NotNull: true},
+					{Name: "tag", Type: "TEXT", NotNull: true},
+				},
+			},
+		}}, nil
+	})
+}
+
+// 测试根据数据库表结构生成功能
+func TestGenerateFeaturesFromDB(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":           "{{.Name}} {{.TableName}}\n{{range .Fields}}{{.Name}} {{.Type}} {{.Gorm}}\n{{end}}",
+		"repository/base.go.tmpl":       "base\n",
+		"repository/repository.go.tmpl": "repository {{.Name}}\n",
+		"service/service.go.tmpl":       "service {{.Name}}\n",
+		"controller/controller.go.tmpl": "controller {{.Name}}\n",
+		"controller/helpers.go.tmpl":    "helpers\n",
+		"route/route.go.tmpl":           "route {{.Name}}\n",
+		"test/test.go.tmpl":             "test {{.Name}}\n",
+		"example/example.go.tmpl":       "example {{.Name}}\n",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	// 测试乐观锁需要version列
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Versioned = true
+	err = g.GenerateFeaturesFromDB("fake://app", []string{"users"}, "myapp")
+	assert.ErrorContains(t, err, "version", "期望在缺少version列时返回错误，但没有")
+	
+	// 测试生成
+	err = g.GenerateFeaturesFromDB("fake://app", []string{"orders", "order_tags"}, "myapp")
+	require.NoError(t, err, "根据数据库生成功能失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "models", "order.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.Contains(t, string(content), "Order orders\n", "模型名或表名不正确")
+	assert.Contains(t, string(content), "ID uint column:order_no;primaryKey;autoIncrement\n", "整数主键应统一为ID字段")
+	assert.Contains(t, string(content), "Version int ", "乐观锁字段类型应为int")
+	assert.NotContains(t, string(content), "User *User", "users表未生成时不应有关联字段")
+	
+	for _, path := range []string{"repositories/order_repository.go", "services/order_service.go", "controllers/order_controller.go", "routes/order_routes.go", "tests/order_test.go"} {
+		_, err = os.Stat(filepath.Join(tempDir, path))
+		assert.NoError(t, err, "缺少生成的文件 %s", path)
+	}
+	
+	// 没有单列整数主键的表只生成模型
+	_, err = os.Stat(filepath.Join(tempDir, "models", "ordertag.go"))
+	assert.NoError(t, err, "缺少生成的模型文件")
+	_, err = os.Stat(filepath.Join(tempDir, "controllers", "ordertag_controller.go"))
+	assert.True(t, os.IsNotExist(err), "复合主键的表不应生成控制器")
+	
+	// 测试不支持的数据库类型
+	err = NewGenerator(filepath.Join(tempDir, "templates")).GenerateFeaturesFromDB("oracle://localhost", nil, "myapp")
+	assert.ErrorContains(t, err, "fake", "错误信息应列出支持的数据库类型")
+}
@@ -0,0 +1,197 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// DeployDir 部署文件所在目录
+const DeployDir = "deploy"
+
+// DeployTargets gs create deploy支持的部署目标及说明
+var DeployTargets = map[string]string{
+	"k8s":  "Kubernetes清单: Deployment、Service、ConfigMap、HPA、Ingress和kustomization.yaml",
+	"helm": "Helm chart: 与k8s相同的资源，副本数、资源配额等通过values.yaml配置",
+}
+
+// DeployData 部署模板数据
+type DeployData struct {
+	DockerData
+	App           string         // Kubernetes资源和Helm chart的名称，镜像名称中的下划线替换为连字符
+	LivenessPath  string         // 存活探针路径
+	ReadinessPath string         // 就绪探针路径
+	GracePeriod   int            // 终止宽限期(秒)，大于server.shutdown_timeout
+	Metrics       bool           // 项目包含metrics包，Pod增加Prometheus抓取注解
+	Secrets       []DeploySecret // 从Secret读取的环境变量
+}
+
+// DeploySecret 从Kubernetes Secret读取的环境变量
+type DeploySecret struct {
+	Env      string // 环境变量名
+	Name     string // Secret名称
+	Key      string // Secret中的键
+	Optional bool   // Secret不存在时仍然启动，用于为空时禁用的配置项
+	Comment  string // 说明及创建Secret的命令
+}
+
+// GenerateDeploy 在deploy目录生成target对应的部署文件，k8s生成到deploy/k8s，helm生成到deploy/helm/<项目名称>，
+// 镜像名称、端口、探针路径和终止宽限期等按项目中实际存在的文件生成，force为false时任一文件已存在则不生成任何文件
+func (g *Generator) GenerateDeploy(projectDir string, target string, force bool) error {
+	if _, ok := DeployTargets[target]; !ok {
+		return fmt.Errorf("不支持的部署目标 '%s'(可选 k8s、helm)", target)
+	}
+	
+	docker, err := dockerData(projectDir)
+	if err != nil {
+		return err
+	}
+	data := DeployData{
+		DockerData:    docker,
+		App:           strings.ReplaceAll(docker.Image, "_", "-"),
+		LivenessPath:  "/health",
+		ReadinessPath: "/health",
+		GracePeriod:   15,
+		Metrics:       utils.FileExists(filepath.Join(projectDir, "metrics", "metrics.go")),
+	}
+	data.Secrets = deploySecrets(projectDir, data.App, docker.Database)
+	if utils.FileExists(filepath.Join(projectDir, "health", "health.go")) {
+		data.LivenessPath, data.ReadinessPath = "/livez", "/readyz"
+	}
+	config := projectConfig(projectDir, docker.ConfigFiles)
+	if timeout, err := strconv.Atoi(config.Server.ShutdownTimeout); err == nil {
+		data.GracePeriod = timeout + 5
+	}
+	
+	outputDir := filepath.Join(projectDir, DeployDir, target)
+	if target == "helm" {
+		outputDir = filepath.Join(outputDir, data.App)
+	}
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "deploy", target)
+	if err := g.generateDeployFiles(templatesDir, outputDir, data, force); err != nil {
+		return err
+	}
+	if notice := data.notice(target); notice != "" {
+		fmt.Println(notice)
+	}
+	return nil
+}
+
+// deploySecrets 返回部署时从Secret读取的环境变量：MySQL和PostgreSQL的数据库密码，以及项目中
+// 认证模块的JWT签名密钥和API Key引导Key，环境变量名与config.yaml中引用的一致，密钥不进入ConfigMap和镜像
+func deploySecrets(projectDir string, app string, database string) []DeploySecret {
+	var secrets []DeploySecret
+	if database != "" && database != "sqlite" {
+		secrets = append(secrets, DeploySecret{
+			Env:     "APP_DATABASE_PASSWORD",
+			Name:    app + "-db",
+			Key:     "password",
+			Comment: fmt.Sprintf("数据库密码: kubectl create secret generic %s-db --from-literal=password=...", app),
+		})
+	}
+	if utils.FileExists(filepath.Join(projectDir, "auth", "config.go")) {
+		secrets = append(secrets, DeploySecret{
+			Env:     "AUTH_SECRET",
+			Name:    app + "-auth",
+			Key:     "secret",
+			Comment: fmt.Sprintf("JWT签名密钥，至少32个字符: kubectl create secret generic %s-auth --from-literal=secret=$(openssl rand -hex 32)", app),
+		})
+	}
+	if utils.FileExists(filepath.Join(projectDir, "auth", "api_key.go")) {
+		secrets = append(secrets, DeploySecret{
+			Env:      "API_KEY_ADMIN_KEY",
+			Name:     app + "-api-key",
+			Key:      "admin-key",
+			Optional: true,
+			Comment:  fmt.Sprintf("API Key管理接口的引导Key，Secret不存在时禁用: kubectl create secret generic %s-api-key --from-literal=admin-key=$(openssl rand -hex 32)", app),
+		})
+	}
+	return secrets
+}
+
+// includes 判断部署模板是否需要生成，rel为相对部署目标模板目录的路径，
+// SQLite的数据库文件保存在PVC中，只能单副本运行，因此生成PVC而不生成HPA
+func (d DeployData) includes(rel string) bool {
+	switch filepath.ToSlash(rel) {
+	case "hpa.yaml.tmpl":
+		return d.Database != "sqlite"
+	case "pvc.yaml.tmpl":
+		return d.Database == "sqlite"
+	}
+	return true
+}
+
+// notice 返回生成后需要提示的与默认资源不同之处，没有时返回空
+func (d DeployData) notice(target string) string {
+	if d.Database != "sqlite" {
+		return ""
+	}
+	if target == "helm" {
+		return "注意: SQLite的数据库文件保存在PVC中，只能单副本运行，values.yaml中的autoscaling已关闭，persistence已开启"
+	}
+	return "注意: SQLite的数据库文件保存在PVC中，只能单副本运行，已生成pvc.yaml，未生成hpa.yaml"
+}
+
+// generateDeployFiles 按模板目录的结构生成部署文件：.tmpl文件用模板渲染并去掉后缀，
+// 其他文件(例如Helm的templates目录)原样复制
+func (g *Generator) generateDeployFiles(templatesDir, outputDir string, data DeployData, force bool) error {
+	var files []string
+	err := filepath.WalkDir(templatesDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(templatesDir, path)
+		if err != nil {
+			return err
+		}
+		if data.includes(rel) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("无法读取模板目录: %v", err)
+	}
+	
+	// 计算输出路径并检查文件是否已存在
+	outputs := make([]string, len(files))
+	for i, path := range files {
+		rel, err := filepath.Rel(templatesDir, path)
+		if err != nil {
+			return err
+		}
+		outputs[i] = filepath.Join(outputDir, strings.TrimSuffix(rel, ".tmpl"))
+		if _, err := os.Stat(outputs[i]); !force && !os.IsNotExist(err) {
+			return fmt.Errorf("文件已存在: %s(使用--force覆盖)", outputs[i])
+		}
+	}
+	
+	for i, path := range files {
+		if filepath.Ext(path) == ".tmpl" {
+			if err := g.GenerateFromTemplate(path, outputs[i], data); err != nil {
+				return fmt.Errorf("生成文件失败 %s: %v", outputs[i], err)
+			}
+		} else {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("无法读取文件: %v", err)
+			}
+			if err := utils.EnsureDir(filepath.Dir(outputs[i])); err != nil {
+				return fmt.Errorf("无法创建目录: %v", err)
+			}
+			if err := os.WriteFile(outputs[i], content, 0644); err != nil {
+				return fmt.Errorf("无法写入文件: %v", err)
+			}
+		}
+		fmt.Printf("已生成文件: %s\n", outputs[i])
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试读取config.go中的默认server配置
+func TestProjectConfig(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "config"), 0755), "无法创建配置目录")
+	createTempFile(t, filepath.Join(tempDir, "config"), "config.go", `package config
+
+func defaults() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            9090,
+			ShutdownTimeout: 20,
+		},
+	}
+}
+`)
+	config := projectConfig(tempDir, nil)
+	assert.Equal(t, "9090", config.Server.Port)
+	assert.Equal(t, "20", config.Server.ShutdownTimeout)
+	
+	// 配置文件中的值优先
+	createTempFile(t, tempDir, "config.yaml", "server:\n  port: ${PORT:-7070}\n")
+	config = projectConfig(tempDir, []string{"config.yaml"})
+	assert.Equal(t, "7070", config.Server.Port)
+	assert.Equal(t, "20", config.Server.ShutdownTimeout)
+}
+
+// 测试生成部署文件
+func TestGenerateDeploy(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 创建测试模板，templates目录中的文件原样复制
+	templates := map[string]string{
+		"k8s/deployment.yaml.tmpl":    "{{.App}} {{.Port}} {{.LivenessPath}} {{.ReadinessPath}} {{.GracePeriod}}",
+		"k8s/hpa.yaml.tmpl":           "hpa",
+		"k8s/pvc.yaml.tmpl":           "pvc",
+		"helm/Chart.yaml.tmpl":        "name: {{.App}}",
+		"helm/templates/service.yaml": "name: {{ .Chart.Name }}",
+		"helm/templates/_helpers.tpl": "{{- define \"app.name\" -}}{{- end -}}",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", "deploy", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	projectDir := filepath.Join(tempDir, "shop_api")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "health"), 0755), "无法创建项目目录")
+	createTempFile(t, projectDir, "go.mod", "module shop\n\ngo 1.22\n")
+	createTempFile(t, projectDir, "config.yaml", "server:\n  port: 9000\n  shutdown_timeout: 30\ndatabase:\n  driver: sqlite\n")
+	createTempFile(t, filepath.Join(projectDir, "health"), "health.go", "package health")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	require.NoError(t, g.GenerateDeploy(projectDir, "k8s", false))
+	content, err := os.ReadFile(filepath.Join(projectDir, "deploy", "k8s", "deployment.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop-api 9000 /livez /readyz 35", string(content))
+	assert.FileExists(t, filepath.Join(projectDir, "deploy", "k8s", "pvc.yaml"))
+	assert.NoFileExists(t, filepath.Join(projectDir, "deploy", "k8s", "hpa.yaml"), "SQLite只能单副本运行，不应生成HPA")
+	
+	// 文件已存在时不覆盖
+	err = g.GenerateDeploy(projectDir, "k8s", false)
+	assert.ErrorContains(t, err, "文件已存在")
+	assert.NoError(t, g.GenerateDeploy(projectDir, "k8s", true))
+	
+	// Helm chart生成到以项目名称命名的目录
+	require.NoError(t, g.GenerateDeploy(projectDir, "helm", false))
+	chartDir := filepath.Join(projectDir, "deploy", "helm", "shop-api")
+	content, err = os.ReadFile(filepath.Join(chartDir, "Chart.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: shop-api", string(content))
+	content, err = os.ReadFile(filepath.Join(chartDir, "templates", "service.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: {{ .Chart.Name }}", string(content), "Helm模板应原样复制")
+	assert.FileExists(t, filepath.Join(chartDir, "templates", "_helpers.tpl"))
+	
+	// 不支持的部署目标
+	err = g.GenerateDeploy(projectDir, "nomad", false)
+	assert.ErrorContains(t, err, "不支持的部署目标")
+	
+	// SQLite不生成HPA时提示原因
+	sqlite := DeployData{DockerData: DockerData{Database: "sqlite"}}
+	assert.Contains(t, sqlite.notice("k8s"), "未生成hpa.yaml")
+	assert.Contains(t, sqlite.notice("helm"), "autoscaling已关闭")
+	assert.Empty(t, DeployData{DockerData: DockerData{Database: "postgres"}}.notice("k8s"), "其他数据库生成HPA，无需提示")
+}
+
+// 测试按数据库和认证模块生成从Secret读取的环境变量
+func TestDeploySecrets(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	assert.Empty(t, deploySecrets(tempDir, "shop", "sqlite"), "SQLite没有密码，没有认证模块时不需要Secret")
+	
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "auth"), 0755), "无法创建auth目录")
+	createTempFile(t, filepath.Join(tempDir, "auth"), "config.go", "package auth")
+	createTempFile(t, filepath.Join(tempDir, "auth"), "api_key.go", "package auth")
+	secrets := deploySecrets(tempDir, "shop", "postgres")
+	require.Len(t, secrets, 3)
+	assert.Equal(t, []string{"APP_DATABASE_PASSWORD", "AUTH_SECRET", "API_KEY_ADMIN_KEY"}, []string{secrets[0].Env, secrets[1].Env, secrets[2].Env})
+	assert.Equal(t, "shop-auth", secrets[1].Name)
+	assert.Equal(t, "secret", secrets[1].Key)
+	assert.False(t, secrets[1].Optional, "JWT签名密钥是必需的")
+	assert.True(t, secrets[2].Optional, "引导Key为空时禁用，Secret不存在时仍应启动")
+}
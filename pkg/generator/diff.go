@@ -0,0 +1,317 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yggai/gs/pkg/schema"
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// SchemaSnapshotFile 表结构快照文件，记录最近一次生成迁移后的表结构，应与迁移一起提交
+var SchemaSnapshotFile = filepath.Join(MigrationsDir, "schema.json")
+
+// schemaSnapshot 表结构快照
+type schemaSnapshot struct {
+	Dialect string          `json:"dialect"` // 生成迁移时使用的SQL方言，列类型与方言相关
+	Tables  []*schema.Table `json:"tables"`  // 按表名排序
+}
+
+// loadSchemaSnapshot 读取表结构快照，文件不存在时返回空快照
+func loadSchemaSnapshot(dialect schema.Dialect) (*schemaSnapshot, error) {
+	snapshot := &schemaSnapshot{Dialect: string(dialect)}
+	content, err := os.ReadFile(SchemaSnapshotFile)
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法读取表结构快照: %v", err)
+	}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, fmt.Errorf("无法解析表结构快照 %s: %v", SchemaSnapshotFile, err)
+	}
+	if snapshot.Dialect != string(dialect) {
+		return nil, fmt.Errorf("表结构快照使用的方言为%s，与当前的%s不一致", snapshot.Dialect, dialect)
+	}
+	return snapshot, nil
+}
+
+// setTable 加入或替换快照中的表
+func (s *schemaSnapshot) setTable(table *schema.Table) {
+	for i, existing := range s.Tables {
+		if existing.Name == table.Name {
+			s.Tables[i] = table
+			return
+		}
+	}
+	s.Tables = append(s.Tables, table)
+	sort.Slice(s.Tables, func(i, j int) bool {
+		return s.Tables[i].Name < s.Tables[j].Name
+	})
+}
+
+// save 保存快照
+func (s *schemaSnapshot) save() error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(MigrationsDir); err != nil {
+		return fmt.Errorf("无法创建迁移目录: %v", err)
+	}
+	if err := os.WriteFile(SchemaSnapshotFile, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("无法保存表结构快照: %v", err)
+	}
+	return nil
+}
+
+// DiffMigration 比较models目录中的模型与表结构快照，生成增删表、增删改列和索引的迁移，
+// 破坏性变更在迁移文件和输出中标出；dryRun为true时只输出变更和SQL，不生成文件
+func (g *Generator) DiffMigration(name string, dryRun bool, packageName string) error {
+	if name == "" {
+		name = "update_schema"
+	}
+	name = migrationName(name)
+	if name == "" {
+		return fmt.Errorf("迁移名称不能为空")
+	}
+	
+	dialect, err := g.migrationDialect()
+	if err != nil {
+		return err
+	}
+	snapshot, err := loadSchemaSnapshot(dialect)
+	if err != nil {
+		return err
+	}
+	tables, err := parseModelTables("models", dialect)
+	if err != nil {
+		return err
+	}
+	
+	changes := dialect.Diff(snapshot.Tables, tables)
+	if len(changes) == 0 {
+		fmt.Println("模型与表结构快照一致，不需要生成迁移")
+		return nil
+	}
+	
+	// 升级语句按变更顺序，回滚语句按相反顺序，每项变更前加上说明
+	data := MigrationData{Name: name, Dialect: string(dialect), Package: packageName}
+	fmt.Println("表结构变更:")
+	for _, change := range changes {
+		comment := "-- " + change.Description
+		if change.Destructive {
+			comment = "-- 警告: " + change.Description
+			data.Warnings = append(data.Warnings, change.Description)
+			fmt.Printf("  ! %s (破坏性变更，执行前请确认)\n", change.Description)
+		} else {
+			fmt.Printf("  + %s\n", change.Description)
+		}
+		data.Up = append(data.Up, commentStatements(comment, change.Up)...)
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		data.Down = append(data.Down, commentStatements("-- 撤销: "+changes[i].Description, changes[i].Down)...)
+	}
+	
+	if dryRun {
+		fmt.Println()
+		for _, statement := range data.Up {
+			fmt.Println(statement + ";")
+		}
+		return nil
+	}
+	
+	if err := g.generateMigration(data); err != nil {
+		return err
+	}
+	snapshot.Tables = tables
+	if err := snapshot.save(); err != nil {
+		return err
+	}
+	if len(data.Warnings) > 0 {
+		fmt.Printf("注意: 迁移包含 %d 项破坏性变更，执行前请检查升级文件\n", len(data.Warnings))
+	}
+	return nil
+}
+
+// commentStatements 在第一条语句前加上注释
+func commentStatements(comment string, statements []string) []string {
+	if len(statements) == 0 {
+		return nil
+	}
+	return append([]string{comment + "\n" + statements[0]}, statements[1:]...)
+}
+
+// modelStruct 模型文件中声明的结构体
+type modelStruct struct {
+	Name  string
+	Type  *ast.StructType
+	Table string // TableName方法返回的表名，为空表示不是模型
+}
+
+// parseModelTables 用go/ast解析目录中的模型，转换为表结构，按表名排序
+func parseModelTables(dir string, dialect schema.Dialect) ([]*schema.Table, error) {
+	models, structs, err := parseModelStructs(dir)
+	if err != nil {
+		return nil, err
+	}
+	
+	var tables []*schema.Table
+	for _, model := range models {
+		if model.Table != "" {
+			tables = append(tables, modelTable(model.Table, structFields(structs, model.Type, ""), dialect))
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
+
+// parseModelStructs 用go/ast解析目录中声明的结构体，按声明顺序返回，同时返回按名称索引的结构体。
+// 定义了TableName方法或嵌入gorm.Model的结构体视为模型，其余结构体只在被嵌入时展开
+func parseModelStructs(dir string) ([]*modelStruct, map[string]*modelStruct, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	fset := token.NewFileSet()
+	var models []*modelStruct
+	structs := map[string]*modelStruct{}
+	tableNames := map[string]string{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, fmt.Errorf("无法解析模型文件: %v", err)
+		}
+	
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+						model := &modelStruct{Name: typeSpec.Name.Name, Type: structType}
+						structs[model.Name] = model
+						models = append(models, model)
+					}
+				}
+			case *ast.FuncDecl:
+				if receiver, table := tableNameMethod(decl); receiver != "" {
+					tableNames[receiver] = table
+				}
+			}
+		}
+	}
+	
+	for _, model := range models {
+		model.Table = tableNames[model.Name]
+		if model.Table == "" && embedsGormModel(model.Type) {
+			model.Table = toSnakeName(PluralForm(model.Name))
+		}
+	}
+	return models, structs, nil
+}
+
+// tableNameMethod 判断是否为返回字符串常量的TableName方法，返回接收者类型和表名
+func tableNameMethod(decl *ast.FuncDecl) (string, string) {
+	if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 || decl.Body == nil || len(decl.Body.List) != 1 {
+		return "", ""
+	}
+	receiver := decl.Recv.List[0].Type
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		receiver = star.X
+	}
+	ident, ok := receiver.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", ""
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", ""
+	}
+	table, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", ""
+	}
+	return ident.Name, table
+}
+
+// embedsGormModel 判断结构体是否嵌入了gorm.Model
+func embedsGormModel(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 && types.ExprString(field.Type) == "gorm.Model" {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields 返回结构体的导出字段，嵌入的gorm.Model和同一包中的结构体按GORM的规则展开，
+// 带embedded标签的字段展开时列名加上embeddedPrefix
+func structFields(structs map[string]*modelStruct, structType *ast.StructType, prefix string) Fields {
+	var fields Fields
+	for _, field := range structType.Fields.List {
+		typ := types.ExprString(field.Type)
+		tag := ""
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value).Get("gorm")
+			}
+		}
+		settings := parseGormTag(tag)
+	
+		// 嵌入的结构体
+		embedded := structs[strings.TrimPrefix(typ, "*")]
+		switch {
+		case len(field.Names) == 0 && typ == "gorm.Model":
+			fields = append(fields,
+				Field{Name: "ID", Type: "uint", Gorm: "primaryKey"},
+				Field{Name: "CreatedAt", Type: "time.Time"},
+				Field{Name: "UpdatedAt", Type: "time.Time"},
+				Field{Name: "DeletedAt", Type: "gorm.DeletedAt", Gorm: "index"},
+			)
+			continue
+		case embedded != nil && (len(field.Names) == 0 || settings.has("EMBEDDED")):
+			fields = append(fields, structFields(structs, embedded.Type, prefix+settings["EMBEDDEDPREFIX"])...)
+			continue
+		}
+	
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			f := Field{Name: name.Name, Type: typ, Gorm: tag}
+			if prefix != "" {
+				column := settings["COLUMN"]
+				if column == "" {
+					column = toSnakeName(name.Name)
+				}
+				f.Gorm = strings.TrimPrefix(tag+";column:"+prefix+column, ";")
+			}
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// 测试用go/ast解析模型文件
+func TestParseModelTables(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	createTempFile(t, tempDir, "user.go", `package models
+
+import "gorm.io/gorm"
+
+type Base struct {
+	ID      uint   `+"`gorm:\"primaryKey\"`"+`
+	Creator string
+}
+
+type Address struct {
+	City string
+}
+
+// User 定义了TableName方法
+type User struct {
+	Base
+	Name    string  `+"`gorm:\"size:64;not null\"`"+`
+	Home    Address `+"`gorm:\"embedded;embeddedPrefix:home_\"`"+`
+	Profile *Profile
+	secret  string
+}
+
+func (User) TableName() string {
+	return "app_users"
+}
+
+// Post 嵌入gorm.Model
+type Post struct {
+	gorm.Model
+	Title string `+"`gorm:\"uniqueIndex\"`"+`
+}
+
+// UserRequest 不是模型
+type UserRequest struct {
+	Name string
+}
+`)
+	createTempFile(t, tempDir, "user_test.go", "package models\n\ntype Fixture struct {\n\tgorm.Model\n}\n")
+	
+	tables, err := parseModelTables(tempDir, schema.MySQL)
+	require.NoError(t, err, "解析模型失败")
+	require.Len(t, tables, 2, "只有定义了TableName或嵌入gorm.Model的结构体是模型")
+	
+	users := tables[0]
+	assert.Equal(t, "app_users", users.Name)
+	var names []string
+	for _, column := range users.Columns {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"id", "creator", "name", "home_city"}, names, "嵌入的结构体应展开，关联和未导出字段应忽略")
+	assert.Equal(t, []string{"id"}, users.PrimaryKey)
+	assert.Equal(t, "varchar(64)", users.Column("name").Type)
+	
+	posts := tables[1]
+	assert.Equal(t, "posts", posts.Name)
+	assert.Equal(t, []string{"id"}, posts.PrimaryKey)
+	assert.NotNil(t, posts.Column("deleted_at"), "gorm.Model应展开为ID、时间戳和软删除列")
+	assert.NotNil(t, posts.Column("title"))
+	
+	createTempFile(t, tempDir, "broken.go", "package models\n\ntype {")
+	_, err = parseModelTables(tempDir, schema.MySQL)
+	assert.Error(t, err, "语法错误的模型文件应返回错误")
+}
+
+// 测试根据模型变化生成迁移
+func TestDiffMigration(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":          "package models\n\ntype {{.Name}} struct {\n\tID uint `gorm:\"primaryKey\"`\n\tName string\n\tCreatedAt time.Time\n\tUpdatedAt time.Time\n}\n\nfunc ({{.Name}}) TableName() string {\n\treturn \"{{.TableName}}\"\n}\n",
+		"migration/up.sql.tmpl":        "{{range .Warnings}}-- 警告: {{.}}\n{{end}}{{range .Up}}{{.}};\n{{end}}",
+		"migration/down.sql.tmpl":      "{{range .Down}}{{.}};\n{{end}}",
+		"migration/migrations.go.tmpl": "package migrations",
+		"migration/main.go.tmpl":       "package main",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Migration = true
+	g.Dialect = "mysql"
+	
+	// 生成模型时记录表结构快照
+	require.NoError(t, g.GenerateModel("User", "myapp"), "生成模型失败")
+	snapshot, err := loadSchemaSnapshot(schema.MySQL)
+	require.NoError(t, err, "读取快照失败")
+	require.Len(t, snapshot.Tables, 1, "生成模型后应记录表结构快照")
+	assert.Equal(t, "users", snapshot.Tables[0].Name)
+	
+	// 模型没有变化时不生成迁移
+	require.NoError(t, g.DiffMigration("", false, "myapp"))
+	migrations, _ := listMigrations()
+	assert.Len(t, migrations, 1, "模型没有变化时不应生成迁移")
+	
+	// 修改模型：删除Name，增加非空的Age
+	createTempFile(t, "models", "user.go", "package models\n\ntype User struct {\n\tID uint `gorm:\"primaryKey\"`\n\tAge int `gorm:\"not null;index\"`\n\tCreatedAt time.Time\n\tUpdatedAt time.Time\n}\n\nfunc (User) TableName() string {\n\treturn \"users\"\n}\n")
+	
+	// 只预览时不生成文件
+	require.NoError(t, g.DiffMigration("", true, "myapp"), "预览迁移失败")
+	migrations, _ = listMigrations()
+	assert.Len(t, migrations, 1, "预览时不应生成迁移")
+	
+	require.NoError(t, g.DiffMigration("", false, "myapp"), "生成迁移失败")
+	migrations, _ = listMigrations()
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "update_schema", migrations[1].Name, "默认的迁移名称应为update_schema")
+	
+	content, err := os.ReadFile(migrations[1].Path)
+	require.NoError(t, err, "无法读取升级迁移")
+	up := string(content)
+	assert.Contains(t, up, "-- 警告: 删除列 users.name\n", "破坏性变更应在文件开头列出")
+	assert.Contains(t, up, "ALTER TABLE `users` ADD COLUMN `age` bigint NOT NULL;\n")
+	assert.Contains(t, up, "CREATE INDEX `idx_users_age` ON `users` (`age`);\n")
+	assert.Less(t, strings.Index(up, "ADD COLUMN"), strings.Index(up, "DROP COLUMN"), "应先增加列再删除列")
+	
+	content, err = os.ReadFile(strings.TrimSuffix(migrations[1].Path, ".up.sql") + ".down.sql")
+	require.NoError(t, err, "无法读取回滚迁移")
+	down := string(content)
+	assert.Less(t, strings.Index(down, "DROP INDEX `idx_users_age`"), strings.Index(down, "DROP COLUMN `age`"), "回滚应按相反顺序执行")
+	
+	// 生成迁移后快照应更新
+	require.NoError(t, g.DiffMigration("", false, "myapp"))
+	migrations, _ = listMigrations()
+	assert.Len(t, migrations, 2, "快照更新后不应重复生成迁移")
+	
+	// 快照的方言与当前方言不一致
+	g.Dialect = "postgres"
+	assert.Error(t, g.DiffMigration("", false, "myapp"), "方言不一致应返回错误")
+}
+
+// 测试项目中还没有migrations目录时，根据已有表结构生成代码只记录快照
+func TestExistingTablesWithoutMigrationsDir(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":           "{{.Name}}\n",
+		"repository/base.go.tmpl":       "base\n",
+		"repository/repository.go.tmpl": "repository {{.Name}}\n",
+		"service/service.go.tmpl":       "service {{.Name}}\n",
+		"controller/controller.go.tmpl": "controller {{.Name}}\n",
+		"controller/helpers.go.tmpl":    "helpers\n",
+		"route/route.go.tmpl":           "route {{.Name}}\n",
+		"test/test.go.tmpl":             "test {{.Name}}\n",
+		"example/example.go.tmpl":       "example {{.Name}}\n",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	sqlPath := filepath.Join(tempDir, "schema.sql")
+	require.NoError(t, os.WriteFile(sqlPath, []byte(testSchemaSQL), 0644), "无法写入SQL文件")
+	
+	// gs create model --from-sql
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Migration = true
+	err = g.GenerateModelsFromSQL(sqlPath, []string{"users"}, "myapp")
+	require.NoError(t, err, "没有migrations目录时根据SQL文件生成模型失败")
+	
+	snapshot, err := loadSchemaSnapshot(schema.MySQL)
+	require.NoError(t, err, "无法读取表结构快照")
+	require.Len(t, snapshot.Tables, 1, "快照应记录已有的表")
+	assert.Equal(t, "users", snapshot.Tables[0].Name)
+	migrations, err := listMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, migrations, "已有的表不应生成建表迁移")
+	
+	// gs create feature --from-db
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, MigrationsDir)))
+	g = NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Migration = true
+	err = g.GenerateFeaturesFromDB("fake://app", []string{"orders"}, "myapp")
+	require.NoError(t, err, "没有migrations目录时根据数据库生成功能失败")
+	
+	snapshot, err = loadSchemaSnapshot(schema.MySQL)
+	require.NoError(t, err, "无法读取表结构快照")
+	require.Len(t, snapshot.Tables, 1, "快照应记录已有的表")
+	assert.Equal(t, "orders", snapshot.Tables[0].Name)
+	migrations, err = listMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, migrations, "已有的表不应生成建表迁移")
+}
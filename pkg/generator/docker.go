@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// DockerData Docker和Makefile模板数据，由项目中实际存在的文件推断
+type DockerData struct {
+	Name         string   // 项目名称，用作二进制文件名
+	Image        string   // 镜像名称，项目名称的小写形式
+	GoVersion    string   // 构建镜像的Go版本，来自go.mod
+	Port         int      // 容器内的监听端口
+	Database     string   // 数据库驱动: sqlite、mysql或postgres，为空时不使用数据库
+	DatabaseName string   // 数据库名称，SQLite为容器内数据库文件的文件名
+	ConfigFiles  []string // 复制到镜像中的配置文件，包括profile
+	Tests        bool     // 项目包含测试
+	Migrations   bool     // 项目包含cmd/migrate迁移命令
+	Seed         bool     // 项目包含cmd/seed夹具加载命令
+	Tracing      bool     // 项目包含tracing包
+}
+
+// dockerFiles Docker相关的模板及生成的文件
+var dockerFiles = []struct{ template, output string }{
+	{"Dockerfile.tmpl", "Dockerfile"},
+	{"docker-compose.yml.tmpl", "docker-compose.yml"},
+	{"dockerignore.tmpl", ".dockerignore"},
+	{"Makefile.tmpl", "Makefile"},
+}
+
+// goVersionPattern 匹配go.mod中的go指令
+var goVersionPattern = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+
+// serverDefaultsPattern 匹配config.go默认配置中的server配置
+var serverDefaultsPattern = regexp.MustCompile(`(?s)ServerConfig\{(.*?)\n\s*\}`)
+
+// envDefaultPattern 匹配配置文件中带默认值的环境变量引用，例如 ${PORT:-8080}
+var envDefaultPattern = regexp.MustCompile(`^\$\{\w+:-(.*)\}$`)
+
+// GenerateDocker 在projectDir生成多阶段构建的Dockerfile、docker-compose.yml、.dockerignore和Makefile，
+// 数据库、迁移和夹具命令等按项目中实际存在的文件生成，force为false时任一文件已存在则不生成任何文件
+func (g *Generator) GenerateDocker(projectDir string, force bool) error {
+	data, err := dockerData(projectDir)
+	if err != nil {
+		return err
+	}
+	
+	if !force {
+		for _, file := range dockerFiles {
+			outputFile := filepath.Join(projectDir, file.output)
+			if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+				return fmt.Errorf("文件已存在: %s(使用--force覆盖)", outputFile)
+			}
+		}
+	}
+	
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "docker")
+	for _, file := range dockerFiles {
+		outputFile := filepath.Join(projectDir, file.output)
+		if err := g.GenerateFromTemplate(filepath.Join(templatesDir, file.template), outputFile, data); err != nil {
+			return fmt.Errorf("生成%s失败: %v", file.output, err)
+		}
+		fmt.Printf("已生成文件: %s\n", outputFile)
+	}
+	return nil
+}
+
+// dockerData 读取projectDir中的go.mod、配置文件和已生成的命令，得到模板数据
+func dockerData(projectDir string) (DockerData, error) {
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return DockerData{}, err
+	}
+	modContent, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return DockerData{}, fmt.Errorf("未找到go.mod，请在项目根目录执行: %v", err)
+	}
+	
+	name := filepath.Base(absDir)
+	data := DockerData{
+		Name:      name,
+		Image:     strings.ToLower(name),
+		GoVersion: "1.22",
+		Port:      8080,
+		Tests:     hasTests(projectDir),
+		Tracing:   utils.FileExists(filepath.Join(projectDir, "tracing", "tracing.go")),
+	}
+	if match := goVersionPattern.FindSubmatch(modContent); match != nil {
+		data.GoVersion = string(match[1])
+	}
+	
+	// 数据库驱动，只生成项目支持的驱动对应的服务
+	switch driver := projectDriver(projectDir); driver {
+	case "sqlite", "mysql", "postgres":
+		data.Database = driver
+		data.Migrations = utils.FileExists(filepath.Join(projectDir, "cmd", "migrate", "main.go"))
+		data.Seed = utils.FileExists(filepath.Join(projectDir, "cmd", "seed", "main.go"))
+	}
+	
+	// 配置文件及profile
+	for _, ext := range []string{".yaml", ".yml", ".toml", ".json"} {
+		for _, pattern := range []string{"config" + ext, "config.*" + ext} {
+			matches, _ := filepath.Glob(filepath.Join(projectDir, pattern))
+			for _, match := range matches {
+				data.ConfigFiles = append(data.ConfigFiles, filepath.Base(match))
+			}
+		}
+	}
+	sort.Strings(data.ConfigFiles)
+	
+	// 端口和数据库名称
+	config := projectConfig(projectDir, data.ConfigFiles)
+	if port, err := strconv.Atoi(config.Server.Port); err == nil {
+		data.Port = port
+	}
+	data.DatabaseName = config.Database.Name
+	if data.DatabaseName == "" || strings.Contains(data.DatabaseName, "$") {
+		data.DatabaseName = strings.ReplaceAll(strings.ToLower(name), "-", "_")
+		if data.Database == "sqlite" {
+			data.DatabaseName = "app.db"
+		}
+	}
+	data.DatabaseName = filepath.Base(data.DatabaseName)
+	return data, nil
+}
+
+// fileConfig 生成模板需要的配置项
+type fileConfig struct {
+	Server struct {
+		Port            string `yaml:"port"`
+		ShutdownTimeout string `yaml:"shutdown_timeout"`
+	} `yaml:"server"`
+	Database struct {
+		Name string `yaml:"name"`
+	} `yaml:"database"`
+}
+
+// projectConfig 读取config/config.go默认配置中的server配置，再用configFiles中的config.yaml、
+// config.yml或config.json覆盖，引用环境变量的配置项使用其默认值
+func projectConfig(projectDir string, configFiles []string) fileConfig {
+	var config fileConfig
+	content, _ := os.ReadFile(filepath.Join(projectDir, "config", "config.go"))
+	if match := serverDefaultsPattern.FindSubmatch(content); match != nil {
+		for _, line := range strings.Split(string(match[1]), "\n") {
+			key, value, _ := strings.Cut(strings.TrimSpace(line), ":")
+			value = strings.TrimSuffix(strings.TrimSpace(value), ",")
+			switch key {
+			case "Port":
+				config.Server.Port = value
+			case "ShutdownTimeout":
+				config.Server.ShutdownTimeout = value
+			}
+		}
+	}
+	
+	for _, file := range configFiles {
+		if file == "config.yaml" || file == "config.yml" || file == "config.json" {
+			content, _ := os.ReadFile(filepath.Join(projectDir, file))
+			yaml.Unmarshal(content, &config)
+			break
+		}
+	}
+	config.Server.Port = configValue(config.Server.Port)
+	config.Server.ShutdownTimeout = configValue(config.Server.ShutdownTimeout)
+	config.Database.Name = configValue(config.Database.Name)
+	return config
+}
+
+// configValue 返回配置项的值，${VAR:-默认值}形式的环境变量引用返回默认值
+func configValue(value string) string {
+	if match := envDefaultPattern.FindStringSubmatch(value); match != nil {
+		return match[1]
+	}
+	return value
+}
+
+// hasTests 判断项目中是否有测试文件
+func hasTests(projectDir string) bool {
+	found := false
+	filepath.WalkDir(projectDir, func(path string, entry os.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return nil
+		case entry.IsDir() && path != projectDir && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "vendor"):
+			return filepath.SkipDir
+		case strings.HasSuffix(entry.Name(), "_test.go"):
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试按项目中的文件推断Docker模板数据
+func TestDockerData(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	projectDir := filepath.Join(tempDir, "Shop-API")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "cmd", "migrate"), 0755), "无法创建项目目录")
+	createTempFile(t, projectDir, "go.mod", "module example.com/shop\n\ngo 1.23.4\n")
+	createTempFile(t, projectDir, "config.yaml", `server:
+  port: ${PORT:-9000}
+database:
+  driver: postgres
+  name: ${DB_NAME:-shop}
+`)
+	createTempFile(t, projectDir, "config.prod.yaml", "log:\n  format: json\n")
+	createTempFile(t, filepath.Join(projectDir, "cmd", "migrate"), "main.go", "package main")
+	
+	data, err := dockerData(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, DockerData{
+		Name:         "Shop-API",
+		Image:        "shop-api",
+		GoVersion:    "1.23",
+		Port:         9000,
+		Database:     "postgres",
+		DatabaseName: "shop",
+		ConfigFiles:  []string{"config.prod.yaml", "config.yaml"},
+		Migrations:   true,
+	}, data)
+	
+	// 有测试文件和tracing包，不使用数据库时忽略迁移命令
+	createTempFile(t, projectDir, "config.yaml", "server:\n  port: 8081\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "tracing"), 0755))
+	createTempFile(t, filepath.Join(projectDir, "tracing"), "tracing.go", "package tracing")
+	createTempFile(t, filepath.Join(projectDir, "tracing"), "tracing_test.go", "package tracing")
+	data, err = dockerData(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, 8081, data.Port)
+	assert.Equal(t, "", data.Database)
+	assert.False(t, data.Migrations, "不使用数据库时不应生成迁移")
+	assert.True(t, data.Tests)
+	assert.True(t, data.Tracing)
+	
+	// 不是Go项目
+	_, err = dockerData(tempDir)
+	assert.ErrorContains(t, err, "未找到go.mod")
+}
+
+// 测试生成Docker和Makefile文件
+func TestGenerateDocker(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 创建测试模板
+	for _, file := range dockerFiles {
+		path := filepath.Join(tempDir, "templates", "component", "docker", file.template)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(file.output+" {{.Database}}"), 0644), "无法创建测试模板文件")
+	}
+	
+	projectDir := filepath.Join(tempDir, "app")
+	require.NoError(t, os.MkdirAll(projectDir, 0755), "无法创建项目目录")
+	createTempFile(t, projectDir, "go.mod", "module app\n\ngo 1.22\n")
+	createTempFile(t, projectDir, "config.yaml", "database:\n  driver: sqlite\n")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	require.NoError(t, g.GenerateDocker(projectDir, false))
+	content, err := os.ReadFile(filepath.Join(projectDir, ".dockerignore"))
+	require.NoError(t, err, "应生成.dockerignore")
+	assert.Equal(t, ".dockerignore sqlite", string(content))
+	
+	// 文件已存在时不生成任何文件，--force时覆盖
+	createTempFile(t, projectDir, "Makefile", "custom")
+	require.NoError(t, os.Remove(filepath.Join(projectDir, "Dockerfile")))
+	err = g.GenerateDocker(projectDir, false)
+	assert.ErrorContains(t, err, "文件已存在")
+	assert.NoFileExists(t, filepath.Join(projectDir, "Dockerfile"), "文件已存在时不应生成其他文件")
+	
+	require.NoError(t, g.GenerateDocker(projectDir, true))
+	content, err = os.ReadFile(filepath.Join(projectDir, "Makefile"))
+	require.NoError(t, err)
+	assert.Equal(t, "Makefile sqlite", string(content))
+	assert.FileExists(t, filepath.Join(projectDir, "Dockerfile"))
+}
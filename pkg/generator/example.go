@@ -11,10 +11,13 @@ import (
 
 // ExampleData 示例模板数据
 type ExampleData struct {
-	Name         string // 示例名称，首字母大写
-	PluralName   string // 复数名称，用于列表方法
-	ResourceName string // 资源名称，用于URL路径
-	Package      string // 项目包名
+	Name         string     // 示例名称，首字母大写
+	PluralName   string     // 复数名称，用于列表方法
+	ResourceName string     // 资源名称，用于URL路径
+	Package      string     // 项目包名
+	BasePath     string     // 路由组路径
+	Ops          Operations // 生成的增删改查接口
+	FeatureOptions
 }
 
 // GenerateExample 生成示例代码
@@ -23,21 +26,25 @@ func (g *Generator) GenerateExample(name string, packageName string) error {
 	name = formatName(name)
 	
 	// 准备模板数据
+	spec := g.resourceSpec(name)
 	data := ExampleData{
-		Name:         name,
-		PluralName:   PluralForm(name),
-		ResourceName: strings.ToLower(name) + "s",
-		Package:      packageName,
+		Name:           name,
+		PluralName:     PluralForm(name),
+		ResourceName:   strings.ToLower(name) + "s",
+		Package:        packageName,
+		BasePath:       spec.basePath(name),
+		Ops:            g.operations(name),
+		FeatureOptions: g.Options,
 	}
 	
-	// 确保目录存在
-	outputDir := filepath.Join("examples")
+	// 每个示例都是独立的main包，放在各自的目录中，多个示例可以一起构建
+	outputDir := filepath.Join("examples", strings.ToLower(name))
 	if err := utils.EnsureDir(outputDir); err != nil {
 		return fmt.Errorf("无法创建示例目录: %v", err)
 	}
 	
 	// 示例文件路径
-	outputFile := filepath.Join(outputDir, strings.ToLower(name)+"_example.go")
+	outputFile := filepath.Join(outputDir, "main.go")
 	
 	// 检查文件是否已存在
 	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
@@ -52,4 +59,4 @@ func (g *Generator) GenerateExample(name string, packageName string) error {
 	
 	fmt.Printf("已生成示例文件: %s\n", outputFile)
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// FactoriesDir 测试数据工厂所在目录
+const FactoriesDir = "factories"
+
+// FactoryData 测试数据工厂模板数据
+type FactoryData struct {
+	Name         string        // 模型名称，首字母大写
+	VarName      string        // 变量名称，首字母小写
+	TableName    string        // 表名
+	Package      string        // 项目包名
+	Dialect      string        // 数据库方言，用于生成seed命令
+	Fields       FactoryFields // 生成假数据的字段
+	Associations string        // 不生成值的外键字段，顿号分隔
+}
+
+// FactoryField 工厂中生成假数据的字段
+type FactoryField struct {
+	Name  string // Go字段名
+	Value string // 生成假数据的Go表达式
+}
+
+// FactoryFields 工厂字段列表
+type FactoryFields []FactoryField
+
+// KeyWidth 返回结构体字面量中键的对齐宽度
+func (f FactoryFields) KeyWidth() int {
+	width := 0
+	for _, field := range f {
+		if len(field.Name)+1 > width {
+			width = len(field.Name) + 1
+		}
+	}
+	return width
+}
+
+// GenerateFactory 根据models目录中的模型生成测试数据工厂，项目中还没有假数据生成器、
+// 夹具加载器和seed命令时一并生成
+func (g *Generator) GenerateFactory(name string, packageName string) error {
+	// 格式化名称
+	name = formatName(name)
+	
+	// 从模型文件中读取字段，工厂随模型的修改重新生成即可
+	models, structs, err := parseModelStructs("models")
+	if err != nil {
+		return err
+	}
+	var model *modelStruct
+	for _, m := range models {
+		if m.Name == name && m.Table != "" {
+			model = m
+		}
+	}
+	if model == nil {
+		return fmt.Errorf("没有找到模型 %s，请先执行 gs create model %s", name, name)
+	}
+	
+	dialect, err := g.migrationDialect()
+	if err != nil {
+		return err
+	}
+	data := FactoryData{
+		Name:      name,
+		VarName:   strings.ToLower(name[:1]) + name[1:],
+		TableName: model.Table,
+		Package:   packageName,
+		Dialect:   string(dialect),
+	}
+	data.Fields, data.Associations = factoryFields(structFields(structs, model.Type, ""))
+	
+	// 确保目录存在
+	if err := utils.EnsureDir(FactoriesDir); err != nil {
+		return fmt.Errorf("无法创建工厂目录: %v", err)
+	}
+	
+	// 工厂文件路径
+	outputFile := filepath.Join(FactoriesDir, strings.ToLower(name)+"_factory.go")
+	
+	// 检查文件是否已存在
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		return fmt.Errorf("工厂文件已存在: %s", outputFile)
+	}
+	
+	// 生成工厂文件
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "factory")
+	if err := g.GenerateFromTemplate(filepath.Join(templatesDir, "factory.go.tmpl"), outputFile, data); err != nil {
+		return fmt.Errorf("生成工厂失败: %v", err)
+	}
+	fmt.Printf("已生成工厂文件: %s\n", outputFile)
+	
+	// 假数据生成器、夹具加载器和cmd/seed命令
+	shared := []struct{ template, output string }{
+		{"faker.go.tmpl", filepath.Join(FactoriesDir, "faker.go")},
+		{"fixtures.go.tmpl", filepath.Join(FactoriesDir, "fixtures.go")},
+		{"main.go.tmpl", filepath.Join("cmd", "seed", "main.go")},
+	}
+	for _, file := range shared {
+		if err := g.generateSharedFile(filepath.Join(templatesDir, file.template), file.output, data); err != nil {
+			return fmt.Errorf("生成%s失败: %v", file.output, err)
+		}
+	}
+	return nil
+}
+
+// factoryFields 返回需要生成假数据的字段和不生成值的外键字段。
+// 主键、时间戳、有默认值、忽略或序列化的字段以及关联字段保持零值，由数据库或GORM填充
+func factoryFields(fields Fields) (FactoryFields, string) {
+	var result FactoryFields
+	var associations []string
+	for _, field := range fields {
+		tag := parseGormTag(field.Gorm)
+		switch {
+		case field.Name == "ID" || field.Name == "CreatedAt" || field.Name == "UpdatedAt" || field.Name == "DeletedAt":
+			continue
+		case tag.has("-", "PRIMARYKEY", "DEFAULT", "SERIALIZER", "AUTOCREATETIME", "AUTOUPDATETIME", "FOREIGNKEY", "MANY2MANY", "REFERENCES", "POLYMORPHIC"):
+			continue
+		case strings.HasSuffix(field.Name, "ID") && isIntegerType(strings.TrimPrefix(field.Type, "*")):
+			// 外键的取值取决于关联的记录，由调用方覆盖
+			associations = append(associations, field.Name)
+			continue
+		}
+	
+		value := fakeValue(field, tag)
+		if value == "" {
+			continue
+		}
+		result = append(result, FactoryField{Name: field.Name, Value: value})
+	}
+	return result, strings.Join(associations, "、")
+}
+
+// fakeValue 返回字段假数据的Go表达式，按字段名和类型选择，不支持的类型返回空字符串
+func fakeValue(field Field, tag gormSettings) string {
+	typ := strings.TrimPrefix(field.Type, "*")
+	name := strings.ToLower(field.Name)
+	contains := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				return true
+			}
+		}
+		return false
+	}
+	
+	var value string
+	switch {
+	case typ == "string":
+		switch {
+		case contains("email"):
+			value = "f.faker.Email()"
+		case contains("username", "nickname", "login"):
+			value = "f.faker.Username()"
+		case contains("firstname"):
+			value = "f.faker.FirstName()"
+		case contains("lastname"):
+			value = "f.faker.LastName()"
+		case contains("company"):
+			value = "f.faker.Company()"
+		case strings.HasSuffix(name, "name"):
+			value = "f.faker.Name()"
+		case contains("phone", "mobile"):
+			value = "f.faker.Phone()"
+		case contains("url", "website", "link", "avatar", "image", "photo"):
+			value = "f.faker.URL()"
+		case contains("uuid", "token"):
+			value = "f.faker.UUID()"
+		case contains("city"):
+			value = "f.faker.City()"
+		case contains("address"):
+			value = "f.faker.Address()"
+		case contains("title", "subject", "headline"):
+			value = "f.faker.Sentence()"
+		case contains("description", "content", "body", "text", "note", "bio", "summary", "remark", "comment", "message"):
+			value = "f.faker.Paragraph()"
+		case contains("code", "sku", "slug", "serial") || strings.HasSuffix(name, "no"):
+			value = "f.faker.Code()"
+		case tag.has("UNIQUE", "UNIQUEINDEX"):
+			value = "f.faker.UniqueWord()"
+		default:
+			value = "f.faker.Word()"
+		}
+		if size, err := strconv.Atoi(tag["SIZE"]); err == nil && size > 0 {
+			value = fmt.Sprintf("f.faker.Limit(%s, %d)", value, size)
+		}
+	case isIntegerType(typ):
+		min, max := 1, 1000
+		switch {
+		case name == "age":
+			min, max = 18, 80
+		case contains("price", "amount", "total", "cost", "balance", "fee"):
+			min, max = 100, 100000
+		case contains("quantity", "qty", "count", "stock", "num"):
+			min, max = 1, 100
+		case contains("year"):
+			min, max = 2000, 2030
+		}
+		if (typ == "int8" || typ == "uint8") && max > 100 {
+			max = 100
+		}
+		value = fmt.Sprintf("f.faker.Int(%d, %d)", min, max)
+		if typ != "int" {
+			value = fmt.Sprintf("%s(%s)", typ, value)
+		}
+	case typ == "float64" || typ == "float32":
+		value = "f.faker.Float(0, 100)"
+		if contains("price", "amount", "total", "cost", "balance", "fee") {
+			value = "f.faker.Float(1, 1000)"
+		}
+		if typ == "float32" {
+			value = fmt.Sprintf("float32(%s)", value)
+		}
+	case typ == "bool":
+		value = "f.faker.Bool()"
+	case typ == "time.Time":
+		value = "f.faker.Time()"
+	case field.Type == "[]byte":
+		return "[]byte(f.faker.Word())"
+	default:
+		return ""
+	}
+	
+	if strings.HasPrefix(field.Type, "*") {
+		return "ptr(" + value + ")"
+	}
+	return value
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试按字段名和类型选择假数据
+func TestFactoryFields(t *testing.T) {
+	fields := Fields{
+		{Name: "ID", Type: "uint", Gorm: "primaryKey"},
+		{Name: "Email", Type: "string", Gorm: "uniqueIndex"},
+		{Name: "Name", Type: "string", Gorm: "size:32"},
+		{Name: "Phone", Type: "*string"},
+		{Name: "Slug", Type: "string", Gorm: "unique"},
+		{Name: "Label", Type: "string", Gorm: "unique"},
+		{Name: "Age", Type: "uint8"},
+		{Name: "Price", Type: "float64"},
+		{Name: "Stock", Type: "int"},
+		{Name: "Active", Type: "bool"},
+		{Name: "PublishedAt", Type: "*time.Time"},
+		{Name: "Status", Type: "string", Gorm: "default:'draft'"},
+		{Name: "Tags", Type: "[]string", Gorm: "serializer:json"},
+		{Name: "CustomerID", Type: "uint", Gorm: "not null;index"},
+		{Name: "Customer", Type: "*Customer", Gorm: "foreignKey:CustomerID"},
+		{Name: "Items", Type: "[]OrderItem"},
+		{Name: "CreatedAt", Type: "time.Time"},
+		{Name: "DeletedAt", Type: "gorm.DeletedAt", Gorm: "index"},
+	}
+	
+	result, associations := factoryFields(fields)
+	assert.Equal(t, FactoryFields{
+		{Name: "Email", Value: "f.faker.Email()"},
+		{Name: "Name", Value: "f.faker.Limit(f.faker.Name(), 32)"},
+		{Name: "Phone", Value: "ptr(f.faker.Phone())"},
+		{Name: "Slug", Value: "f.faker.Code()"},
+		{Name: "Label", Value: "f.faker.UniqueWord()"},
+		{Name: "Age", Value: "uint8(f.faker.Int(18, 80))"},
+		{Name: "Price", Value: "f.faker.Float(1, 1000)"},
+		{Name: "Stock", Value: "f.faker.Int(1, 100)"},
+		{Name: "Active", Value: "f.faker.Bool()"},
+		{Name: "PublishedAt", Value: "ptr(f.faker.Time())"},
+	}, result, "主键、时间戳、有默认值、序列化和关联字段不应生成值")
+	assert.Equal(t, "CustomerID", associations, "外键应由调用方设置")
+	assert.Equal(t, 12, result.KeyWidth())
+}
+
+// 测试根据模型生成测试数据工厂
+func TestGenerateFactory(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"factory.go.tmpl":  "{{.Name}} {{.TableName}}{{range .Fields}} {{.Name}}={{.Value}}{{end}} [{{.Associations}}]",
+		"faker.go.tmpl":    "package factories",
+		"fixtures.go.tmpl": "package factories",
+		"main.go.tmpl":     "package main // {{.Package}} {{.Dialect}}",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", "factory", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	// 创建模型
+	require.NoError(t, os.MkdirAll("models", 0755), "无法创建模型目录")
+	createTempFile(t, "models", "order.go", "package models\n\ntype Order struct {\n\tID uint `gorm:\"primaryKey\"`\n\tTitle string\n\tCustomerID uint\n}\n\nfunc (Order) TableName() string {\n\treturn \"shop_orders\"\n}\n")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Dialect = "sqlite"
+	
+	// 测试生成工厂
+	err = g.GenerateFactory("order", "myapp")
+	require.NoError(t, err, "生成工厂失败")
+	content, err := os.ReadFile(filepath.Join("factories", "order_factory.go"))
+	require.NoError(t, err, "无法读取工厂文件")
+	assert.Equal(t, "Order shop_orders Title=f.faker.Sentence() [CustomerID]", string(content))
+	
+	content, err = os.ReadFile(filepath.Join("cmd", "seed", "main.go"))
+	require.NoError(t, err, "应生成seed命令")
+	assert.Equal(t, "package main // myapp sqlite", string(content))
+	assert.FileExists(t, filepath.Join("factories", "faker.go"))
+	assert.FileExists(t, filepath.Join("factories", "fixtures.go"))
+	
+	// 工厂已存在
+	assert.Error(t, g.GenerateFactory("Order", "myapp"), "工厂已存在时应返回错误")
+	
+	// 模型不存在
+	assert.Error(t, g.GenerateFactory("Customer", "myapp"), "模型不存在时应返回错误")
+}
@@ -2,21 +2,90 @@ package generator
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yggai/gs/pkg/utils"
 )
 
+// FeatureOptions 组件生成选项
+type FeatureOptions struct {
+	Versioned  bool   // 启用乐观锁：模型增加Version列，接口使用ETag/If-Match
+	Protected  bool   // 路由组受认证中间件保护，需先生成认证模块
+	RBAC       bool   // 按操作校验权限，隐含Protected
+	SoftDelete bool   // 模型增加DeletedAt列，删除时只标记不物理删除
+	Paginated  bool   // 列表接口按page和page_size分页，并返回总数
+	Migration  bool   // 生成模型时同时生成建表迁移
+	Traced     bool   // 服务和仓储方法接收context并创建子span，项目包含tracing包时自动启用
+	Auth       string // 受保护路由使用的认证策略jwt或apikey，按项目中已生成的认证中间件确定
+}
+
+// featureOptions 返回组件模板使用的选项，项目以 gs init --with=tracing 初始化时启用Traced
+func (g *Generator) featureOptions() FeatureOptions {
+	options := g.Options
+	if _, err := os.Stat(filepath.Join("tracing", "tracing.go")); err == nil {
+		options.Traced = true
+	}
+	if options.Protected || options.RBAC {
+		options.Auth = authStrategy()
+	}
+	return options
+}
+
+// authStrategy 返回项目中已生成的认证中间件对应的策略，两者都存在时使用JWT，都不存在时返回空
+func authStrategy() string {
+	for _, strategy := range []string{"jwt", "apikey"} {
+		if utils.FileExists(applyAuthFiles[strategy]) {
+			return strategy
+		}
+	}
+	return ""
+}
+
 // GenerateFeature 生成完整功能代码，包含模型、服务、控制器、路由等
 func (g *Generator) GenerateFeature(name string, packageName string) error {
 	// 格式化名称
 	name = formatName(name)
 	
+	// 权限校验基于当前登录用户，因此路由必须受保护
+	if g.Options.RBAC {
+		g.Options.Protected = true
+	}
+	
+	// 受保护的路由依赖认证模块，权限校验基于JWT认证的登录用户
+	if g.Options.Protected {
+		switch authStrategy() {
+		case "":
+			return fmt.Errorf("未找到认证中间件，请先执行 gs create auth")
+		case "apikey":
+			if g.Options.RBAC {
+				return fmt.Errorf("--rbac按登录用户校验权限，需要JWT认证中间件，请先执行 gs create auth --strategy=jwt")
+			}
+		}
+	}
+	
+	// 只有自定义接口的资源不需要模型、仓储和服务
+	crud := g.resourceSpec(name).CRUD
+	
 	// 生成模型
-	if err := g.GenerateModel(name, packageName); err != nil {
-		return fmt.Errorf("生成模型失败: %v", err)
+	if crud {
+		if err := g.GenerateModel(name, packageName); err != nil {
+			return fmt.Errorf("生成模型失败: %v", err)
+		}
+	}
+	
+	// 乐观锁需要仓储层执行带版本条件的更新
+	if crud && g.Options.Versioned {
+		if err := g.GenerateRepository(name, packageName); err != nil {
+			return fmt.Errorf("生成仓储失败: %v", err)
+		}
 	}
 	
 	// 生成服务
-	if err := g.GenerateService(name, packageName); err != nil {
-		return fmt.Errorf("生成服务失败: %v", err)
+	if crud {
+		if err := g.GenerateService(name, packageName); err != nil {
+			return fmt.Errorf("生成服务失败: %v", err)
+		}
 	}
 	
 	// 生成控制器
@@ -30,13 +99,24 @@ func (g *Generator) GenerateFeature(name string, packageName string) error {
 	}
 	
 	// 生成测试
-	if err := g.GenerateTest(name, packageName); err != nil {
-		return fmt.Errorf("生成测试失败: %v", err)
+	if crud {
+		if err := g.GenerateTest(name, packageName); err != nil {
+			return fmt.Errorf("生成测试失败: %v", err)
+		}
+	}
+	
+	// 生成权限常量和权限测试
+	if g.Options.RBAC {
+		if err := g.GenerateRBAC(name, packageName); err != nil {
+			return fmt.Errorf("生成权限失败: %v", err)
+		}
 	}
 	
 	// 生成示例
-	if err := g.GenerateExample(name, packageName); err != nil {
-		return fmt.Errorf("生成示例失败: %v", err)
+	if crud {
+		if err := g.GenerateExample(name, packageName); err != nil {
+			return fmt.Errorf("生成示例失败: %v", err)
+		}
 	}
 	
 	fmt.Printf("已成功生成 %s 的完整功能代码\n", name)
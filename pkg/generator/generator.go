@@ -11,6 +11,9 @@ import (
 // Generator 代码生成器
 type Generator struct {
 	TemplatesDir string
+	Options      FeatureOptions           // 组件生成选项，对所有组件生效
+	Specs        map[string]*ResourceSpec // 按资源名索引的资源定义，未定义的资源使用默认模板
+	Dialect      string                   // 迁移使用的SQL方言，为空时从项目配置读取
 }
 
 // NewGenerator 创建一个新的代码生成器
@@ -65,6 +68,59 @@ func (g *Generator) GenerateFromTemplate(templateName string, outputPath string,
 	return nil
 }
 
+// generateSharedFile 生成多个组件共用的文件，文件已存在时跳过
+func (g *Generator) generateSharedFile(templatePath string, outputPath string, data interface{}) error {
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		return nil
+	}
+	
+	if err := g.GenerateFromTemplate(templatePath, outputPath, data); err != nil {
+		return err
+	}
+	
+	fmt.Printf("已生成文件: %s\n", outputPath)
+	return nil
+}
+
+// generateTree 按模板目录的结构生成一组文件，输出路径去掉.tmpl后缀
+// 任一目标文件已存在时不生成任何文件
+func (g *Generator) generateTree(templatesDir string, outputDir string, data interface{}) error {
+	var templates []string
+	err := filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".tmpl" {
+			templates = append(templates, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("无法读取模板目录: %v", err)
+	}
+	
+	// 计算输出路径并检查文件是否已存在
+	outputs := make([]string, len(templates))
+	for i, templatePath := range templates {
+		rel, err := filepath.Rel(templatesDir, templatePath)
+		if err != nil {
+			return err
+		}
+		outputs[i] = filepath.Join(outputDir, strings.TrimSuffix(rel, ".tmpl"))
+		if _, err := os.Stat(outputs[i]); !os.IsNotExist(err) {
+			return fmt.Errorf("文件已存在: %s", outputs[i])
+		}
+	}
+	
+	for i, templatePath := range templates {
+		if err := g.GenerateFromTemplate(templatePath, outputs[i], data); err != nil {
+			return fmt.Errorf("生成文件失败 %s: %v", outputs[i], err)
+		}
+		fmt.Printf("已生成文件: %s\n", outputs[i])
+	}
+	return nil
+}
+
 // CapitalizeFirst 将字符串的第一个字母大写
 func CapitalizeFirst(s string) string {
 	if s == "" {
@@ -97,4 +153,65 @@ func PluralForm(s string) string {
 	}
 	
 	return s + "s"
-} 
\ No newline at end of file
+}
+
+// SingularForm 返回名词的单数形式，是PluralForm的逆操作（简单处理）。
+// 候选为去掉s、去掉es和ies改为y，返回第一个复数形式与输入相同的候选，都不相同时返回去掉s的形式；
+// 单数以e结尾时(cache、size、movie)去掉es或ies的形式也能还原，由singularEndsWithE决定先尝试哪个
+func SingularForm(s string) string {
+	lower := strings.ToLower(s)
+	if len(s) < 2 || !strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "ss") ||
+		strings.HasSuffix(lower, "us") || strings.HasSuffix(lower, "is") {
+		return s
+	}
+	
+	candidates := []string{s[:len(s)-1]}
+	if strings.HasSuffix(lower, "es") {
+		candidates = append(candidates, s[:len(s)-2])
+	}
+	if strings.HasSuffix(lower, "ies") && len(s) > 3 {
+		candidates = append(candidates, s[:len(s)-3]+"y")
+	}
+	if !singularEndsWithE(lower) {
+		candidates = append(candidates[1:], candidates[0])
+	}
+	for _, candidate := range candidates {
+		if strings.EqualFold(PluralForm(candidate), s) {
+			return candidate
+		}
+	}
+	return s[:len(s)-1]
+}
+
+// singularENouns 单数以e结尾、复数与去掉e后的复数相同的常见名词，
+// 例如caches既可以是cache也可以是cach的复数
+var singularENouns = []string{
+	"cache", "niche", "ache", "avalanche", "moustache", "mustache", "quiche", "cliche",
+	"use", "abuse", "excuse", "fuse", "muse", "ruse", "refuse",
+	"movie", "cookie", "zombie", "calorie", "rookie", "hoodie", "selfie", "smoothie", "brownie", "genie", "prairie",
+	"pie", "tie", "lie", "die",
+}
+
+// singularEndsWithE 判断小写的复数名词的单数是否以e结尾
+func singularEndsWithE(lower string) bool {
+	for _, noun := range singularENouns {
+		// 名词在开头或前面是辅音，避免coaches匹配aches、copies匹配pies
+		if strings.HasSuffix(lower, noun+"s") {
+			rest := lower[:len(lower)-len(noun)-1]
+			if rest == "" || (len(noun) > 3 && !strings.ContainsAny(rest[len(rest)-1:], "aeiou")) {
+				return true
+			}
+		}
+	}
+	switch {
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "zzes"):
+		return false
+	case strings.HasSuffix(lower, "uses"):
+		// house、cause等us前是元音，status、bus等拉丁词us前是辅音
+		return len(lower) > 4 && strings.ContainsAny(lower[len(lower)-5:len(lower)-4], "aeiou")
+	case strings.HasSuffix(lower, "ses"), strings.HasSuffix(lower, "zes"):
+		// response、course、size等
+		return true
+	}
+	return false
+}
@@ -162,6 +162,45 @@ func TestPluralForm(t *testing.T) {
 	}
 }
 
+// 测试SingularForm函数
+func TestSingularForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"空字符串", "", ""},
+		{"常规名词", "books", "book"},
+		{"以ies结尾", "cities", "city"},
+		{"以ses结尾", "classes", "class"},
+		{"以xes结尾", "boxes", "box"},
+		{"以ches结尾", "watches", "watch"},
+		{"以ss结尾", "address", "address"},
+		{"以us结尾", "status", "status"},
+		{"已是单数形式", "order", "order"},
+		{"以use结尾", "warehouses", "warehouse"},
+		{"以ze结尾", "sizes", "size"},
+		{"以che结尾", "caches", "cache"},
+		{"以ie结尾", "movies", "movie"},
+		{"以uses结尾的拉丁词", "statuses", "status"},
+		{"以ouses结尾", "houses", "house"},
+		{"以ses结尾的其他词", "responses", "response"},
+		{"以oaches结尾", "coaches", "coach"},
+		{"以pies结尾", "copies", "copy"},
+		{"以ys结尾", "keys", "key"},
+		{"以shes结尾", "dishes", "dish"},
+		{"以zzes结尾", "buzzes", "buzz"},
+		{"首字母大写", "Warehouses", "Warehouse"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SingularForm(tt.input)
+			assert.Equal(t, tt.expected, result, "单数形式转换不正确")
+		})
+	}
+}
+
 // 测试CapitalizeFirst和LowercaseFirst函数
 func TestStringHelpers(t *testing.T) {
 	tests := []struct {
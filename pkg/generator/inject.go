@@ -0,0 +1,366 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+)
+
+// injectImport 向Go源码添加import，已存在时原样返回
+func injectImport(src []byte, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == importPath {
+			return src, nil
+		}
+	}
+	
+	line := "\t" + strconv.Quote(importPath) + "\n"
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		
+		// import (...) 形式：按顺序插入到最后一组import中，排在该组第一个import之前时
+		// 另起一组，例如第三方包之后的项目包；否则插入到右括号之前
+		if gen.Rparen.IsValid() {
+			group := lastImportGroup(fset, gen)
+			if len(group) > 0 {
+				if first, _ := strconv.Unquote(group[0].Path.Value); importPath < first {
+					line = "\n" + line
+				} else {
+					for _, spec := range group {
+						if path, _ := strconv.Unquote(spec.Path.Value); path > importPath {
+							start := fset.Position(spec.Pos()).Offset
+							lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+							return splice(src, lineStart, lineStart, line), nil
+						}
+					}
+				}
+			}
+			offset := fset.Position(gen.Rparen).Offset
+			prefix := src[:offset]
+			if len(prefix) > 0 && prefix[len(prefix)-1] != '\n' {
+				line = "\n" + line
+			}
+			return splice(src, offset, offset, line), nil
+		}
+		
+		// import "x" 形式：改写为import块
+		start := fset.Position(gen.Pos()).Offset
+		end := fset.Position(gen.End()).Offset
+		spec := src[fset.Position(gen.Specs[0].Pos()).Offset:end]
+		block := "import (\n\t" + string(spec) + "\n" + line + ")"
+		return splice(src, start, end, block), nil
+	}
+	
+	// 没有import声明：插入到package语句之后
+	offset := fset.Position(file.Name.End()).Offset
+	return splice(src, offset, offset, "\n\nimport (\n"+line+")"), nil
+}
+
+// lastImportGroup 返回import块中最后一组连续的import，组之间以空行分隔
+func lastImportGroup(fset *token.FileSet, gen *ast.GenDecl) []*ast.ImportSpec {
+	var group []*ast.ImportSpec
+	lastLine := 0
+	for _, s := range gen.Specs {
+		spec, ok := s.(*ast.ImportSpec)
+		if !ok {
+			continue
+		}
+		if line := fset.Position(spec.Pos()).Line; lastLine > 0 && line > lastLine+1 {
+			group = nil
+		}
+		group = append(group, spec)
+		lastLine = fset.Position(spec.End()).Line
+	}
+	return group
+}
+
+// injectAfterAssign 在第一个满足match的赋值语句之后插入一行代码，赋值语句后紧跟的
+// <变量>.Use(...)调用之后，使多次插入的中间件按插入顺序执行，也不会排在已注册的中间件之前。
+// stmt接收赋值语句左侧的变量名，返回要插入的语句；返回的语句已存在时原样返回
+func injectAfterAssign(src []byte, match func(call *ast.CallExpr) bool, stmt func(varName string) string) ([]byte, error) {
+	return injectBeforeUse(src, match, nil, stmt)
+}
+
+// injectBeforeUse 与injectAfterAssign相同，但插入到紧跟的Use调用中第一个注册了满足before的中间件的调用之前，
+// 用于必须先于某个已注册的中间件执行的中间件；没有这样的调用或before为nil时插入到这些Use调用之后
+func injectBeforeUse(src []byte, match func(call *ast.CallExpr) bool, before func(call *ast.CallExpr) bool, stmt func(varName string) string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	var target *ast.AssignStmt
+	ast.Inspect(file, func(n ast.Node) bool {
+		if target != nil {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if ok && match(call) {
+			target = assign
+		}
+		return true
+	})
+	if target == nil {
+		return nil, fmt.Errorf("未找到插入位置")
+	}
+	
+	ident, ok := target.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("未找到插入位置")
+	}
+	code := stmt(ident.Name)
+	if bytes.Contains(src, []byte(code)) {
+		return src, nil
+	}
+	
+	// 沿用赋值语句所在行的缩进
+	start := fset.Position(target.Pos()).Offset
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	indent := string(src[lineStart:start])
+	
+	offset := fset.Position(target.End()).Offset
+	for _, next := range followingStmts(file, target) {
+		use := useCall(next, ident.Name)
+		if use == nil {
+			break
+		}
+		if before != nil && registers(use, before) {
+			// 插入到该调用所在行之前
+			start := fset.Position(next.Pos()).Offset
+			lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+			return splice(src, lineStart, lineStart, indent+code+"\n"), nil
+		}
+		offset = fset.Position(next.End()).Offset
+	}
+	return splice(src, offset, offset, "\n"+indent+code), nil
+}
+
+// followingStmts 返回与stmt在同一代码块中、位于stmt之后的语句
+func followingStmts(file *ast.File, stmt ast.Stmt) []ast.Stmt {
+	var following []ast.Stmt
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if block, ok := n.(*ast.BlockStmt); ok {
+			for i, s := range block.List {
+				if s == stmt {
+					following = block.List[i+1:]
+					found = true
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return following
+}
+
+// useCall 语句为<name>.Use(...)调用时返回该调用，否则返回nil
+func useCall(stmt ast.Stmt, name string) *ast.CallExpr {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || !isSelectorCall(call, name, "Use") {
+		return nil
+	}
+	return call
+}
+
+// registers 判断Use调用的参数中是否有满足match的中间件
+func registers(use *ast.CallExpr, match func(call *ast.CallExpr) bool) bool {
+	for _, arg := range use.Args {
+		if call, ok := arg.(*ast.CallExpr); ok && match(call) {
+			return true
+		}
+	}
+	return false
+}
+
+// injectStructField 在结构体typeName的末尾添加字段，同名字段已存在时原样返回
+func injectStructField(src []byte, typeName string, fieldName string, field string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	var structType *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if ok && spec.Name.Name == typeName {
+			structType, _ = spec.Type.(*ast.StructType)
+			return false
+		}
+		return structType == nil
+	})
+	if structType == nil {
+		return nil, fmt.Errorf("未找到结构体 %s", typeName)
+	}
+	
+	for _, f := range structType.Fields.List {
+		for _, name := range f.Names {
+			if name.Name == fieldName {
+				return src, nil
+			}
+		}
+	}
+	
+	offset := fset.Position(structType.Fields.Closing).Offset
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	return splice(src, lineStart, lineStart, "\t"+field+"\n"), nil
+}
+
+// injectCompositeField 在函数funcName返回的结构体字面量末尾添加字段，同名字段已存在时原样返回，
+// 用于在DefaultConfig等返回默认值的函数中加入新字段的默认值
+func injectCompositeField(src []byte, funcName string, fieldName string, elt string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	fn := findFunc(file, funcName)
+	if fn == nil {
+		return nil, fmt.Errorf("未找到函数 %s", funcName)
+	}
+	var lit *ast.CompositeLit
+	for _, stmt := range fn.Body.List {
+		if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			lit, _ = ret.Results[0].(*ast.CompositeLit)
+		}
+	}
+	if lit == nil {
+		return nil, fmt.Errorf("函数 %s 没有返回结构体字面量", funcName)
+	}
+	
+	for _, e := range lit.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == fieldName {
+				return src, nil
+			}
+		}
+	}
+	
+	// 插入到右括号所在行之前，比右括号多缩进一级
+	offset := fset.Position(lit.Rbrace).Offset
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	indent := string(src[lineStart:offset])
+	return splice(src, lineStart, lineStart, indent+"\t"+elt+"\n"), nil
+}
+
+// injectBeforeStmt 在函数funcName中第一个满足match的顶层语句之前插入一行代码，沿用该语句的缩进，
+// 代码已存在时原样返回
+func injectBeforeStmt(src []byte, funcName string, match func(stmt ast.Stmt) bool, code string) ([]byte, error) {
+	if bytes.Contains(src, []byte(code)) {
+		return src, nil
+	}
+	
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析源码: %v", err)
+	}
+	
+	fn := findFunc(file, funcName)
+	if fn == nil {
+		return nil, fmt.Errorf("未找到函数 %s", funcName)
+	}
+	for _, stmt := range fn.Body.List {
+		if !match(stmt) {
+			continue
+		}
+		start := fset.Position(stmt.Pos()).Offset
+		lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+		indent := string(src[lineStart:start])
+		return splice(src, lineStart, lineStart, indent+code+"\n"), nil
+	}
+	return nil, fmt.Errorf("未在函数 %s 中找到插入位置", funcName)
+}
+
+// findFunc 返回名为name的函数或方法声明，找不到时返回nil
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name && fn.Body != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// formatSource 按gofmt格式化源码，用于插入的字段需要与已有字段重新对齐的文件
+func formatSource(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("无法格式化源码: %v", err)
+	}
+	return formatted, nil
+}
+
+// injectFile 读取文件，依次应用修改函数后写回
+func injectFile(path string, edits ...func([]byte) ([]byte, error)) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("无法读取文件: %v", err)
+	}
+	
+	for _, edit := range edits {
+		if src, err = edit(src); err != nil {
+			return fmt.Errorf("无法修改文件 %s: %v", path, err)
+		}
+	}
+	
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		return fmt.Errorf("无法写入文件: %v", err)
+	}
+	return nil
+}
+
+// isSelectorCall 判断调用是否为 pkg.Func(...) 形式，pkg为空时匹配任意接收者
+func isSelectorCall(call *ast.CallExpr, pkg string, names ...string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if pkg != "" {
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkg {
+			return false
+		}
+	}
+	for _, name := range names {
+		if sel.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splice 用text替换src[start:end]
+func splice(src []byte, start, end int, text string) []byte {
+	result := make([]byte, 0, len(src)+len(text))
+	result = append(result, src[:start]...)
+	result = append(result, text...)
+	return append(result, src[end:]...)
+}
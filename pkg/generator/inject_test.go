@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试injectImport函数
+func TestInjectImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"import块",
+			"package main\n\nimport (\n\t\"fmt\"\n)\n",
+			"package main\n\nimport (\n\t\"fmt\"\n\t\"myapp/middlewares\"\n)\n",
+		},
+		{
+			"单行import",
+			"package main\n\nimport \"fmt\"\n",
+			"package main\n\nimport (\n\t\"fmt\"\n\t\"myapp/middlewares\"\n)\n",
+		},
+		{
+			"没有import",
+			"package main\n\nfunc main() {}\n",
+			"package main\n\nimport (\n\t\"myapp/middlewares\"\n)\n\nfunc main() {}\n",
+		},
+		{
+			"按顺序插入到最后一组",
+			"package main\n\nimport (\n\t\"fmt\"\n\n\t\"myapp/config\"\n\t\"myapp/routes\"\n)\n",
+			"package main\n\nimport (\n\t\"fmt\"\n\n\t\"myapp/config\"\n\t\"myapp/middlewares\"\n\t\"myapp/routes\"\n)\n",
+		},
+		{
+			"第三方包之后另起一组",
+			"package main\n\nimport (\n\t\"fmt\"\n\n\t\"orm.io/orm\"\n)\n",
+			"package main\n\nimport (\n\t\"fmt\"\n\n\t\"orm.io/orm\"\n\n\t\"myapp/middlewares\"\n)\n",
+		},
+		{
+			"已存在",
+			"package main\n\nimport (\n\t\"myapp/middlewares\"\n)\n",
+			"package main\n\nimport (\n\t\"myapp/middlewares\"\n)\n",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := injectImport([]byte(tt.input), "myapp/middlewares")
+			require.NoError(t, err, "添加import失败")
+			assert.Equal(t, tt.expected, string(result), "添加import结果不正确")
+		})
+	}
+	
+	_, err := injectImport([]byte("not go code"), "myapp/middlewares")
+	assert.Error(t, err, "期望对无效源码返回错误，但没有")
+}
+
+// 测试injectAfterAssign函数
+func TestInjectAfterAssign(t *testing.T) {
+	src := `package main
+
+func main() {
+	r := gin.Default()
+	r.Run()
+}
+`
+	matchEngine := func(call *ast.CallExpr) bool {
+		return isSelectorCall(call, "gin", "Default", "New")
+	}
+	stmt := func(name string) string {
+		return name + ".Use(middlewares.Logger())"
+	}
+	
+	result, err := injectAfterAssign([]byte(src), matchEngine, stmt)
+	require.NoError(t, err, "插入语句失败")
+	
+	expected := `package main
+
+func main() {
+	r := gin.Default()
+	r.Use(middlewares.Logger())
+	r.Run()
+}
+`
+	assert.Equal(t, expected, string(result), "插入语句结果不正确")
+	
+	// 重复插入不应产生重复语句
+	again, err := injectAfterAssign(result, matchEngine, stmt)
+	require.NoError(t, err, "重复插入语句失败")
+	assert.Equal(t, expected, string(again), "重复插入产生了重复语句")
+	
+	// 插入到已注册的中间件之后
+	src = `package main
+
+func main() {
+	r := gin.New()
+	r.Use(logger.Middleware(slog.Default()), logger.Recovery())
+	r.Use(middlewares.Cors())
+
+	r.Run()
+}
+`
+	result, err = injectAfterAssign([]byte(src), matchEngine, stmt)
+	require.NoError(t, err, "插入语句失败")
+	assert.Equal(t, `package main
+
+func main() {
+	r := gin.New()
+	r.Use(logger.Middleware(slog.Default()), logger.Recovery())
+	r.Use(middlewares.Cors())
+	r.Use(middlewares.Logger())
+
+	r.Run()
+}
+`, string(result), "应插入到紧跟赋值语句的Use调用之后")
+	
+	// 插入到注册了指定中间件的Use调用之前
+	matchLogger := func(call *ast.CallExpr) bool {
+		return isSelectorCall(call, "logger", "Middleware")
+	}
+	result, err = injectBeforeUse([]byte(src), matchEngine, matchLogger, stmt)
+	require.NoError(t, err, "插入语句失败")
+	assert.Equal(t, `package main
+
+func main() {
+	r := gin.New()
+	r.Use(middlewares.Logger())
+	r.Use(logger.Middleware(slog.Default()), logger.Recovery())
+	r.Use(middlewares.Cors())
+
+	r.Run()
+}
+`, string(result), "应插入到注册了指定中间件的Use调用之前")
+	
+	// 没有注册指定中间件时插入到已注册的中间件之后
+	result, err = injectBeforeUse([]byte("package main\n\nfunc main() {\n\tr := gin.New()\n\tr.Run()\n}\n"), matchEngine, matchLogger, stmt)
+	require.NoError(t, err, "插入语句失败")
+	assert.Contains(t, string(result), "r := gin.New()\n\tr.Use(middlewares.Logger())\n")
+	
+	// 找不到插入位置
+	_, err = injectAfterAssign([]byte("package main\n\nfunc main() {}\n"), matchEngine, stmt)
+	assert.Error(t, err, "期望在找不到插入位置时返回错误，但没有")
+}
+
+// 测试injectStructField函数
+func TestInjectStructField(t *testing.T) {
+	src := "package config\n\ntype Config struct {\n\tServer ServerConfig\n}\n"
+	
+	result, err := injectStructField([]byte(src), "Config", "Auth", "Auth auth.Config")
+	require.NoError(t, err, "添加字段失败")
+	
+	expected := "package config\n\ntype Config struct {\n\tServer ServerConfig\n\tAuth auth.Config\n}\n"
+	assert.Equal(t, expected, string(result), "添加字段结果不正确")
+	
+	// 字段已存在时不重复添加
+	again, err := injectStructField(result, "Config", "Auth", "Auth auth.Config")
+	require.NoError(t, err, "重复添加字段失败")
+	assert.Equal(t, expected, string(again), "重复添加产生了重复字段")
+	
+	// 找不到结构体
+	_, err = injectStructField([]byte(src), "Settings", "Auth", "Auth auth.Config")
+	assert.Error(t, err, "期望在找不到结构体时返回错误，但没有")
+}
@@ -0,0 +1,290 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonShape 从JSON示例推断出的值结构
+type jsonShape struct {
+	Kind   string                // null、bool、int、float、string、time、object、array或mixed
+	Keys   []string              // 对象的键，保持示例中的顺序
+	Fields map[string]*jsonShape // 对象各键的值
+	Elem   *jsonShape            // 数组元素，空数组时为nil
+}
+
+// jsonModel 根据JSON示例生成的结构体
+type jsonModel struct {
+	Names []string                 // 结构体名称，第一个为顶层结构体
+	Specs map[string]*ResourceSpec // 各结构体的字段
+	dto   bool                     // 不生成gorm标签和表名
+}
+
+// GenerateModelFromJSON 根据JSON示例推断字段类型生成模型，嵌套对象生成单独的数据结构，
+// dto为true时顶层结构体也只生成数据结构，不含gorm标签和表名
+func (g *Generator) GenerateModelFromJSON(name string, path string, dto bool, packageName string) error {
+	name = formatName(name)
+	if name == "" {
+		return fmt.Errorf("模型名称不能为空")
+	}
+	
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("无法读取JSON示例: %v", err)
+	}
+	defer file.Close()
+	
+	shape, err := parseJSONShape(file)
+	if err != nil {
+		return fmt.Errorf("无法解析JSON示例 %s: %v", path, err)
+	}
+	
+	model, err := inferJSONModel(name, shape, dto)
+	if err != nil {
+		return err
+	}
+	
+	// 先检查全部文件，避免只生成一部分
+	for _, structName := range model.Names {
+		outputFile := filepath.Join("models", strings.ToLower(structName)+".go")
+		if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+			return fmt.Errorf("模型文件已存在: %s", outputFile)
+		}
+	}
+	
+	if g.Specs == nil {
+		g.Specs = map[string]*ResourceSpec{}
+	}
+	for structName, spec := range model.Specs {
+		g.Specs[structName] = spec
+	}
+	
+	for i, structName := range model.Names {
+		if i == 0 && !dto {
+			err = g.GenerateModel(structName, packageName)
+		} else {
+			err = g.GenerateDTO(structName, packageName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已根据 %s 生成 %d 个结构体\n", path, len(model.Names))
+	return nil
+}
+
+// parseJSONShape 读取JSON并推断结构，对象的键保持原有顺序
+func parseJSONShape(r io.Reader) (*jsonShape, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	shape, err := decodeJSONShape(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("JSON值之后有多余内容")
+	}
+	return shape, nil
+}
+
+// decodeJSONShape 读取一个JSON值
+func decodeJSONShape(dec *json.Decoder) (*jsonShape, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			shape := &jsonShape{Kind: "object", Fields: map[string]*jsonShape{}}
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyToken.(string)
+				value, err := decodeJSONShape(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := shape.Fields[key]; !ok {
+					shape.Keys = append(shape.Keys, key)
+				}
+				shape.Fields[key] = mergeJSONShape(shape.Fields[key], value)
+			}
+			_, err := dec.Token()
+			return shape, err
+		}
+		shape := &jsonShape{Kind: "array"}
+		for dec.More() {
+			elem, err := decodeJSONShape(dec)
+			if err != nil {
+				return nil, err
+			}
+			shape.Elem = mergeJSONShape(shape.Elem, elem)
+		}
+		_, err := dec.Token()
+		return shape, err
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return &jsonShape{Kind: "time"}, nil
+		}
+		return &jsonShape{Kind: "string"}, nil
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return &jsonShape{Kind: "int"}, nil
+		}
+		return &jsonShape{Kind: "float"}, nil
+	case bool:
+		return &jsonShape{Kind: "bool"}, nil
+	}
+	return &jsonShape{Kind: "null"}, nil
+}
+
+// mergeJSONShape 合并同一位置的两个值，例如数组中的多个元素
+func mergeJSONShape(a, b *jsonShape) *jsonShape {
+	switch {
+	case a == nil || a.Kind == "null":
+		return b
+	case b == nil || b.Kind == "null":
+		return a
+	case a.Kind == b.Kind:
+		switch a.Kind {
+		case "object":
+			merged := &jsonShape{Kind: "object", Keys: append([]string{}, a.Keys...), Fields: map[string]*jsonShape{}}
+			for key, value := range a.Fields {
+				merged.Fields[key] = value
+			}
+			for _, key := range b.Keys {
+				if _, ok := merged.Fields[key]; !ok {
+					merged.Keys = append(merged.Keys, key)
+				}
+				merged.Fields[key] = mergeJSONShape(merged.Fields[key], b.Fields[key])
+			}
+			return merged
+		case "array":
+			return &jsonShape{Kind: "array", Elem: mergeJSONShape(a.Elem, b.Elem)}
+		}
+		return a
+	case a.Kind == "int" && b.Kind == "float" || a.Kind == "float" && b.Kind == "int":
+		return &jsonShape{Kind: "float"}
+	case a.Kind == "time" && b.Kind == "string" || a.Kind == "string" && b.Kind == "time":
+		return &jsonShape{Kind: "string"}
+	}
+	return &jsonShape{Kind: "mixed"}
+}
+
+// inferJSONModel 将顶层对象转换为结构体，顶层为对象数组时使用数组元素
+func inferJSONModel(name string, shape *jsonShape, dto bool) (*jsonModel, error) {
+	if shape.Kind == "array" && shape.Elem != nil {
+		shape = shape.Elem
+	}
+	if shape.Kind != "object" {
+		return nil, fmt.Errorf("JSON示例的顶层必须是对象或对象数组")
+	}
+	
+	model := &jsonModel{Specs: map[string]*ResourceSpec{}, dto: dto}
+	model.addStruct(name, shape)
+	return model, nil
+}
+
+// addStruct 为对象生成结构体，名称重复时加上数字后缀，返回结构体名称
+func (m *jsonModel) addStruct(name string, shape *jsonShape) string {
+	structName := name
+	for n := 2; m.Specs[structName] != nil; n++ {
+		structName = fmt.Sprintf("%s%d", name, n)
+	}
+	spec := &ResourceSpec{}
+	m.Specs[structName] = spec
+	m.Names = append(m.Names, structName)
+	
+	top := len(m.Names) == 1 && !m.dto
+	used := map[string]bool{}
+	if top {
+		// 与模型的TableName方法重名
+		used["TableName"] = true
+	}
+	for _, key := range shape.Keys {
+		value := shape.Fields[key]
+		fieldName := goName(key)
+		if fieldName == "" {
+			fieldName = "Field"
+		}
+		base := fieldName
+		for n := 2; used[fieldName]; n++ {
+			fieldName = fmt.Sprintf("%s%d", base, n)
+		}
+		used[fieldName] = true
+	
+		field := Field{Name: fieldName, JSONName: key}
+		field.Type, field.Comment = m.goType(structName+base, value)
+		if top {
+			switch {
+			case fieldName == "ID":
+				if value.Kind == "int" {
+					field.Type = "uint"
+				}
+				field.Gorm = "primaryKey"
+			case value.Kind == "object" || value.Kind == "array" || field.Type == "any":
+				// 嵌套结构以JSON格式保存在一列中
+				field.Gorm = "serializer:json"
+			}
+		}
+		spec.Fields = append(spec.Fields, field)
+	}
+	
+	// 模型需要主键和时间戳，示例中没有时补上
+	if top {
+		spec.Complete = true
+		if !used["ID"] {
+			spec.Fields = append(Fields{{Name: "ID", Type: "uint", JSONName: "id", Gorm: "primaryKey"}}, spec.Fields...)
+		}
+		for _, timestamp := range []Field{
+			{Name: "CreatedAt", Type: "time.Time", JSONName: "created_at"},
+			{Name: "UpdatedAt", Type: "time.Time", JSONName: "updated_at"},
+		} {
+			if !used[timestamp.Name] && shape.Fields[timestamp.JSONName] == nil {
+				spec.Fields = append(spec.Fields, timestamp)
+			}
+		}
+	}
+	return structName
+}
+
+// goType 返回值对应的Go类型，无法确定类型时同时返回说明
+func (m *jsonModel) goType(name string, shape *jsonShape) (string, string) {
+	switch shape.Kind {
+	case "bool":
+		return "bool", ""
+	case "int":
+		return "int64", ""
+	case "float":
+		return "float64", ""
+	case "string":
+		return "string", ""
+	case "time":
+		return "time.Time", ""
+	case "object":
+		if len(shape.Keys) == 0 {
+			return "map[string]any", "示例中为空对象，请确认结构"
+		}
+		return m.addStruct(name, shape), ""
+	case "array":
+		if shape.Elem == nil {
+			return "[]any", "示例中为空数组，请确认元素类型"
+		}
+		elem, comment := m.goType(SingularForm(name), shape.Elem)
+		return "[]" + elem, comment
+	case "null":
+		return "any", "示例中为null，请确认类型"
+	}
+	return "any", "示例中的值类型不一致，请确认类型"
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试用webhook请求体
+const testWebhookJSON = `{
+  "event_id": 9007199254740993,
+  "type": "order.paid",
+  "amount": 12.5,
+  "paid": true,
+  "occurred_at": "2024-05-01T10:00:00+08:00",
+  "note": null,
+  "tags": [],
+  "customer": {"id": 7, "email": "a@example.com"},
+  "lineItems": [{"sku": "A1", "price": 10}, {"sku": "B2", "price": 2.5}]
+}`
+
+// 测试根据JSON示例推断结构体
+func TestInferJSONModel(t *testing.T) {
+	shape, err := parseJSONShape(strings.NewReader(testWebhookJSON))
+	require.NoError(t, err, "解析JSON失败")
+	
+	model, err := inferJSONModel("Webhook", shape, false)
+	require.NoError(t, err, "推断结构体失败")
+	assert.Equal(t, []string{"Webhook", "WebhookCustomer", "WebhookLineItem"}, model.Names, "嵌套结构体名称不正确")
+	
+	webhook := model.Specs["Webhook"]
+	assert.True(t, webhook.Complete)
+	assert.Equal(t, Fields{
+		{Name: "ID", Type: "uint", JSONName: "id", Gorm: "primaryKey"},
+		{Name: "EventID", Type: "int64", JSONName: "event_id"},
+		{Name: "Type", Type: "string", JSONName: "type"},
+		{Name: "Amount", Type: "float64", JSONName: "amount"},
+		{Name: "Paid", Type: "bool", JSONName: "paid"},
+		{Name: "OccurredAt", Type: "time.Time", JSONName: "occurred_at"},
+		{Name: "Note", Type: "any", JSONName: "note", Gorm: "serializer:json", Comment: "示例中为null，请确认类型"},
+		{Name: "Tags", Type: "[]any", JSONName: "tags", Gorm: "serializer:json", Comment: "示例中为空数组，请确认元素类型"},
+		{Name: "Customer", Type: "WebhookCustomer", JSONName: "customer", Gorm: "serializer:json"},
+		{Name: "LineItems", Type: "[]WebhookLineItem", JSONName: "lineItems", Gorm: "serializer:json"},
+		{Name: "CreatedAt", Type: "time.Time", JSONName: "created_at"},
+		{Name: "UpdatedAt", Type: "time.Time", JSONName: "updated_at"},
+	}, webhook.Fields, "字段顺序、类型或标签不正确")
+	
+	assert.Equal(t, Fields{
+		{Name: "ID", Type: "int64", JSONName: "id"},
+		{Name: "Email", Type: "string", JSONName: "email"},
+	}, model.Specs["WebhookCustomer"].Fields, "嵌套结构体不应有gorm标签")
+	assert.Equal(t, "float64", model.Specs["WebhookLineItem"].Fields[1].Type, "数组元素中的整数和小数应合并为float64")
+	
+	// 只生成数据结构时不添加主键和时间戳
+	model, err = inferJSONModel("Webhook", shape, true)
+	require.NoError(t, err)
+	assert.False(t, model.Specs["Webhook"].Complete)
+	assert.Len(t, model.Specs["Webhook"].Fields, 9)
+	assert.Empty(t, model.Specs["Webhook"].Fields[6].Gorm)
+	
+	// 示例中的id作为主键
+	shape, err = parseJSONShape(strings.NewReader(`[{"id": "evt_1", "createdAt": 1}]`))
+	require.NoError(t, err)
+	model, err = inferJSONModel("Event", shape, false)
+	require.NoError(t, err)
+	assert.Equal(t, Fields{
+		{Name: "ID", Type: "string", JSONName: "id", Gorm: "primaryKey"},
+		{Name: "CreatedAt", Type: "int64", JSONName: "createdAt"},
+		{Name: "UpdatedAt", Type: "time.Time", JSONName: "updated_at"},
+	}, model.Specs["Event"].Fields)
+	
+	// 空对象无法推断字段，使用map而不是空结构体
+	shape, err = parseJSONShape(strings.NewReader(`{"meta": {}, "items": [{}]}`))
+	require.NoError(t, err)
+	model, err = inferJSONModel("Event", shape, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Event"}, model.Names, "空对象不应生成结构体")
+	assert.Equal(t, Fields{
+		{Name: "Meta", Type: "map[string]any", JSONName: "meta", Comment: "示例中为空对象，请确认结构"},
+		{Name: "Items", Type: "[]map[string]any", JSONName: "items", Comment: "示例中为空对象，请确认结构"},
+	}, model.Specs["Event"].Fields)
+	
+	// 测试顶层不是对象的情况
+	shape, err = parseJSONShape(strings.NewReader(`[1, 2]`))
+	require.NoError(t, err)
+	_, err = inferJSONModel("Event", shape, false)
+	assert.Error(t, err, "期望在顶层不是对象时返回错误，但没有")
+}
+
+// 测试根据JSON示例生成模型
+func TestGenerateModelFromJSON(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl": "model {{.Name}} {{.TableName}}\n{{range .Fields}}{{.Name}} {{.Type}} {{.ModelTag}}\n{{end}}",
+		"model/dto.go.tmpl":   "dto {{.Name}}\n{{range .Fields}}{{.Name}} {{.Type}} {{.RequestTag}}\n{{end}}",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	jsonPath := createTempFile(t, tempDir, "sample.json", testWebhookJSON)
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试生成模型
+	err = g.GenerateModelFromJSON("webhook", jsonPath, false, "myapp")
+	require.NoError(t, err, "根据JSON示例生成模型失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "models", "webhook.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.Contains(t, string(content), "model Webhook webhooks\n")
+	assert.Contains(t, string(content), "LineItems []WebhookLineItem `json:\"lineItems\" gorm:\"serializer:json\"`\n", "json标签应保留原始键名")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "models", "webhookcustomer.go"))
+	require.NoError(t, err, "无法读取生成的数据结构文件")
+	assert.Equal(t, "dto WebhookCustomer\nID int64 `json:\"id\"`\nEmail string `json:\"email\"`\n", string(content))
+	
+	// 测试只生成数据结构
+	err = g.GenerateModelFromJSON("Payload", jsonPath, true, "myapp")
+	require.NoError(t, err, "根据JSON示例生成数据结构失败")
+	content, err = os.ReadFile(filepath.Join(tempDir, "models", "payload.go"))
+	require.NoError(t, err, "无法读取生成的数据结构文件")
+	assert.True(t, strings.HasPrefix(string(content), "dto Payload\nEventID int64"), "顶层结构体不应包含主键")
+	
+	// 测试文件已存在的情况
+	err = g.GenerateModelFromJSON("Webhook", jsonPath, true, "myapp")
+	assert.Error(t, err, "期望在文件已存在时返回错误，但没有")
+	
+	// 测试JSON格式错误的情况
+	badPath := createTempFile(t, tempDir, "bad.json", `{"id": 1,}`)
+	err = g.GenerateModelFromJSON("Bad", badPath, false, "myapp")
+	assert.Error(t, err, "期望在JSON格式错误时返回错误，但没有")
+	_, err = os.Stat(filepath.Join(tempDir, "models", "bad.go"))
+	assert.True(t, os.IsNotExist(err), "解析失败时不应生成文件")
+}
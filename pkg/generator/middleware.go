@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// MiddlewareKinds 支持的中间件类型及说明
+var MiddlewareKinds = map[string]string{
+	"blank":           "空白中间件",
+	"requestid":       "请求ID",
+	"cors":            "跨域资源共享",
+	"recovery":        "panic恢复",
+	"timeout":         "请求超时",
+	"ratelimit":       "令牌桶限流",
+	"gzip":            "gzip响应压缩",
+	"securityheaders": "安全响应头",
+	"metrics":         "Prometheus请求指标(需要metrics包)",
+}
+
+// MiddlewareData 中间件模板数据
+type MiddlewareData struct {
+	Name      string // 中间件名称，首字母大写
+	VarName   string // 变量名称，首字母小写
+	SnakeName string // snake_case名称，metrics类型用作指标名称前缀
+	Kind      string // 中间件类型
+	Package   string // 项目包名
+}
+
+// MiddlewareKindNames 返回排序后的中间件类型列表
+func MiddlewareKindNames() []string {
+	names := make([]string, 0, len(MiddlewareKinds))
+	for name := range MiddlewareKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateMiddleware 生成中间件代码及其单元测试
+func (g *Generator) GenerateMiddleware(name string, packageName string, kind string) error {
+	// 格式化名称
+	name = formatName(name)
+	
+	// 校验中间件类型
+	if kind == "" {
+		kind = "blank"
+	}
+	kind = strings.ToLower(kind)
+	if _, ok := MiddlewareKinds[kind]; !ok {
+		return fmt.Errorf("不支持的中间件类型 '%s'，可用类型: %s", kind, strings.Join(MiddlewareKindNames(), ", "))
+	}
+	
+	// 指标注册到metrics.Registry，由项目的/metrics输出
+	if kind == "metrics" && !utils.FileExists(filepath.Join("metrics", "metrics.go")) {
+		return fmt.Errorf("metrics类型的中间件将指标注册到metrics.Registry，但项目中没有metrics包，请使用 gs init --with=metrics 创建项目")
+	}
+	
+	// 准备模板数据
+	data := MiddlewareData{
+		Name:      name,
+		VarName:   strings.ToLower(name[:1]) + name[1:],
+		SnakeName: toSnakeName(name),
+		Kind:      kind,
+		Package:   packageName,
+	}
+	
+	// 确保目录存在
+	outputDir := filepath.Join("middlewares")
+	if err := utils.EnsureDir(outputDir); err != nil {
+		return fmt.Errorf("无法创建中间件目录: %v", err)
+	}
+	
+	// 中间件文件路径
+	outputFile := filepath.Join(outputDir, strings.ToLower(name)+".go")
+	testFile := filepath.Join(outputDir, strings.ToLower(name)+"_test.go")
+	
+	// 检查文件是否已存在
+	for _, file := range []string{outputFile, testFile} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			return fmt.Errorf("中间件文件已存在: %s", file)
+		}
+	}
+	
+	// 生成中间件文件
+	templateDir := filepath.Join(g.TemplatesDir, "component", "middleware")
+	if err := g.GenerateFromTemplate(filepath.Join(templateDir, kind+".go.tmpl"), outputFile, data); err != nil {
+		return fmt.Errorf("生成中间件失败: %v", err)
+	}
+	fmt.Printf("已生成中间件文件: %s\n", outputFile)
+	
+	// 生成测试文件
+	if err := g.GenerateFromTemplate(filepath.Join(templateDir, kind+"_test.go.tmpl"), testFile, data); err != nil {
+		return fmt.Errorf("生成中间件测试失败: %v", err)
+	}
+	fmt.Printf("已生成测试文件: %s\n", testFile)
+	
+	return nil
+}
+
+// RegisterMiddlewareGlobal 在main.go中为Gin引擎全局注册中间件。requestid类型的中间件注册在
+// logger包的请求日志中间件之前，使请求日志和上下文中的请求ID与响应头一致
+func (g *Generator) RegisterMiddlewareGlobal(name string, packageName string, kind string) error {
+	name = formatName(name)
+	
+	// 请求日志中间件沿用已设置的请求ID
+	var before func(call *ast.CallExpr) bool
+	if strings.ToLower(kind) == "requestid" {
+		before = func(call *ast.CallExpr) bool {
+			return isSelectorCall(call, "logger", "Middleware")
+		}
+	}
+	
+	mainFile := "main.go"
+	err := injectFile(mainFile,
+		func(src []byte) ([]byte, error) {
+			return injectImport(src, packageName+"/middlewares")
+		},
+		func(src []byte) ([]byte, error) {
+			return injectBeforeUse(src, func(call *ast.CallExpr) bool {
+				return isSelectorCall(call, "gin", "Default", "New")
+			}, before, func(engine string) string {
+				return middlewareUseStatement(engine, name)
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("已在 %s 中全局注册中间件: %s\n", mainFile, name)
+	return nil
+}
+
+// RegisterMiddlewareOnGroup 在指定资源的路由组上注册中间件
+func (g *Generator) RegisterMiddlewareOnGroup(name string, resource string, packageName string) error {
+	name = formatName(name)
+	resource = formatName(resource)
+	
+	routeFile := filepath.Join("routes", strings.ToLower(resource)+"_routes.go")
+	if _, err := os.Stat(routeFile); os.IsNotExist(err) {
+		return fmt.Errorf("路由文件不存在: %s", routeFile)
+	}
+	
+	err := injectFile(routeFile,
+		func(src []byte) ([]byte, error) {
+			return injectImport(src, packageName+"/middlewares")
+		},
+		func(src []byte) ([]byte, error) {
+			return injectAfterAssign(src, func(call *ast.CallExpr) bool {
+				return isSelectorCall(call, "", "Group")
+			}, func(group string) string {
+				return middlewareUseStatement(group, name)
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("已在 %s 的路由组上注册中间件: %s\n", routeFile, name)
+	return nil
+}
+
+// middlewareUseStatement 返回使用默认配置注册中间件的语句
+func middlewareUseStatement(target string, name string) string {
+	return fmt.Sprintf("%s.Use(middlewares.%s(middlewares.Default%sOptions()))", target, name, name)
+}
@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试生成中间件
+func TestGenerateMiddleware(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建templates目录结构
+	templatesDir := filepath.Join(tempDir, "templates", "component", "middleware")
+	err = os.MkdirAll(templatesDir, 0755)
+	require.NoError(t, err, "无法创建模板目录")
+	
+	// 创建测试模板
+	err = os.WriteFile(filepath.Join(templatesDir, "cors.go.tmpl"), []byte("func {{.Name}}(opts {{.Name}}Options) gin.HandlerFunc\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = os.WriteFile(filepath.Join(templatesDir, "cors_test.go.tmpl"), []byte("func Test{{.Name}}(t *testing.T)\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试生成中间件
+	err = g.GenerateMiddleware("cors", "myapp", "CORS")
+	require.NoError(t, err, "生成中间件失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "middlewares", "cors.go"))
+	require.NoError(t, err, "无法读取生成的中间件文件")
+	assert.Equal(t, "func Cors(opts CorsOptions) gin.HandlerFunc\n", string(content), "生成的中间件内容不符合预期")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "middlewares", "cors_test.go"))
+	require.NoError(t, err, "无法读取生成的中间件测试文件")
+	assert.Equal(t, "func TestCors(t *testing.T)\n", string(content), "生成的中间件测试内容不符合预期")
+	
+	// 测试文件已存在的情况
+	err = g.GenerateMiddleware("cors", "myapp", "cors")
+	assert.Error(t, err, "期望在中间件文件已存在时返回错误，但没有")
+	
+	// 测试不支持的类型
+	err = g.GenerateMiddleware("Auth", "myapp", "unknown")
+	assert.Error(t, err, "期望对不支持的中间件类型返回错误，但没有")
+	
+	// metrics类型需要项目的metrics包
+	err = os.WriteFile(filepath.Join(templatesDir, "metrics.go.tmpl"), []byte("namespace {{.SnakeName}}\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = os.WriteFile(filepath.Join(templatesDir, "metrics_test.go.tmpl"), []byte("test\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = g.GenerateMiddleware("HTTPMetrics", "myapp", "metrics")
+	assert.ErrorContains(t, err, "gs init --with=metrics", "期望在没有metrics包时返回错误，但没有")
+	
+	require.NoError(t, os.MkdirAll("metrics", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("metrics", "metrics.go"), []byte("package metrics\n"), 0644))
+	err = g.GenerateMiddleware("HTTPMetrics", "myapp", "metrics")
+	require.NoError(t, err, "生成中间件失败")
+	content, err = os.ReadFile(filepath.Join(tempDir, "middlewares", "httpmetrics.go"))
+	require.NoError(t, err, "无法读取生成的中间件文件")
+	assert.Equal(t, "namespace http_metrics\n", string(content), "指标名称前缀应为snake_case名称")
+}
+
+// 测试注册中间件
+func TestRegisterMiddleware(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 全局注册
+	mainContent := `package main
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	r := gin.Default()
+	r.Run(":8080")
+}
+`
+	err = os.WriteFile("main.go", []byte(mainContent), 0644)
+	require.NoError(t, err, "无法创建main.go")
+	
+	err = g.RegisterMiddlewareGlobal("RequestID", "myapp", "requestid")
+	require.NoError(t, err, "全局注册中间件失败")
+	
+	content, err := os.ReadFile("main.go")
+	require.NoError(t, err, "无法读取main.go")
+	assert.Contains(t, string(content), "\t\"myapp/middlewares\"\n", "main.go未导入中间件包")
+	assert.Contains(t, string(content), "r := gin.Default()\n\tr.Use(middlewares.RequestID(middlewares.DefaultRequestIDOptions()))\n", "main.go未注册中间件")
+	
+	// 请求ID中间件注册在请求日志中间件之前，其他中间件注册在已有的中间件之后
+	mainContent = `package main
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	r := gin.New()
+	r.Use(tracing.Middleware())
+	r.Use(logger.Middleware(slog.Default()), logger.Recovery())
+	r.Run(":8080")
+}
+`
+	err = os.WriteFile("main.go", []byte(mainContent), 0644)
+	require.NoError(t, err, "无法创建main.go")
+	require.NoError(t, g.RegisterMiddlewareGlobal("Cors", "myapp", "cors"), "全局注册中间件失败")
+	require.NoError(t, g.RegisterMiddlewareGlobal("RequestID", "myapp", "requestid"), "全局注册中间件失败")
+	
+	content, err = os.ReadFile("main.go")
+	require.NoError(t, err, "无法读取main.go")
+	assert.Contains(t, string(content), `	r.Use(tracing.Middleware())
+	r.Use(middlewares.RequestID(middlewares.DefaultRequestIDOptions()))
+	r.Use(logger.Middleware(slog.Default()), logger.Recovery())
+	r.Use(middlewares.Cors(middlewares.DefaultCorsOptions()))
+`, "请求ID中间件应在请求日志中间件之前执行")
+	
+	// 路由组注册
+	routeContent := `package routes
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUserRoutes(router *gin.Engine) {
+	group := router.Group("/api/users")
+	{
+	}
+}
+`
+	err = os.MkdirAll("routes", 0755)
+	require.NoError(t, err, "无法创建routes目录")
+	err = os.WriteFile(filepath.Join("routes", "user_routes.go"), []byte(routeContent), 0644)
+	require.NoError(t, err, "无法创建路由文件")
+	
+	err = g.RegisterMiddlewareOnGroup("RateLimit", "user", "myapp")
+	require.NoError(t, err, "在路由组上注册中间件失败")
+	
+	content, err = os.ReadFile(filepath.Join("routes", "user_routes.go"))
+	require.NoError(t, err, "无法读取路由文件")
+	assert.Contains(t, string(content), "group := router.Group(\"/api/users\")\n\tgroup.Use(middlewares.RateLimit(middlewares.DefaultRateLimitOptions()))\n", "路由组未注册中间件")
+	
+	// 路由文件不存在
+	err = g.RegisterMiddlewareOnGroup("RateLimit", "order", "myapp")
+	assert.Error(t, err, "期望在路由文件不存在时返回错误，但没有")
+}
@@ -0,0 +1,445 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yggai/gs/pkg/schema"
+	"github.com/yggai/gs/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// MigrationsDir 迁移文件所在目录
+const MigrationsDir = "migrations"
+
+// MigrationData 迁移模板数据
+type MigrationData struct {
+	Version  string   // 版本号，生成时的UTC时间，例如 20240101120000
+	Name     string   // 迁移名称，小写下划线分隔
+	Dialect  string   // SQL方言
+	Package  string   // 项目包名
+	Up       []string // 升级语句，为空时生成待填写的迁移
+	Down     []string // 回滚语句
+	Warnings []string // 需要在执行前确认的破坏性变更
+}
+
+// migrationFile 已有的迁移文件
+type migrationFile struct {
+	Version int64  // 版本号
+	Name    string // 迁移名称
+	Path    string // 升级文件路径
+}
+
+// migrationFilePattern 匹配升级迁移文件名，例如 20240101120000_create_users.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// GenerateMigration 生成一对待填写的升级和回滚迁移
+func (g *Generator) GenerateMigration(name string, packageName string) error {
+	name = migrationName(name)
+	if name == "" {
+		return fmt.Errorf("迁移名称不能为空")
+	}
+	
+	dialect, err := g.migrationDialect()
+	if err != nil {
+		return err
+	}
+	return g.generateMigration(MigrationData{Name: name, Dialect: string(dialect), Package: packageName})
+}
+
+// generateModelMigration 生成模型的建表迁移并记录到表结构快照，已有同一张表的建表迁移时跳过，
+// existing为true时表已存在，只记录快照
+func (g *Generator) generateModelMigration(data ModelData, existing bool) error {
+	dialect, err := g.migrationDialect()
+	if err != nil {
+		return err
+	}
+	snapshot, err := loadSchemaSnapshot(dialect)
+	if err != nil {
+		return err
+	}
+	table := modelTable(data.TableName, data.columns(), dialect)
+	if existing {
+		snapshot.setTable(table)
+		return snapshot.save()
+	}
+	
+	name := "create_" + migrationName(data.TableName)
+	migrations, err := listMigrations()
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if migration.Name == name {
+			fmt.Printf("已存在建表迁移 %s，跳过\n", migration.Path)
+			return nil
+		}
+	}
+	
+	err = g.generateMigration(MigrationData{
+		Name:    name,
+		Dialect: string(dialect),
+		Package: data.Package,
+		Up:      dialect.CreateTable(table),
+		Down:    []string{dialect.DropTable(table.Name)},
+	})
+	if err != nil {
+		return err
+	}
+	snapshot.setTable(table)
+	return snapshot.save()
+}
+
+// generateMigration 按版本号生成升级和回滚迁移文件，项目中还没有迁移执行器时一并生成
+func (g *Generator) generateMigration(data MigrationData) error {
+	if err := utils.EnsureDir(MigrationsDir); err != nil {
+		return fmt.Errorf("无法创建迁移目录: %v", err)
+	}
+	
+	version, err := nextMigrationVersion()
+	if err != nil {
+		return err
+	}
+	data.Version = version
+	
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "migration")
+	for _, direction := range []string{"up", "down"} {
+		outputFile := filepath.Join(MigrationsDir, fmt.Sprintf("%s_%s.%s.sql", version, data.Name, direction))
+		templatePath := filepath.Join(templatesDir, direction+".sql.tmpl")
+		if err := g.GenerateFromTemplate(templatePath, outputFile, data); err != nil {
+			return fmt.Errorf("生成迁移失败: %v", err)
+		}
+		fmt.Printf("已生成迁移文件: %s\n", outputFile)
+	}
+	
+	// 迁移执行器：内嵌迁移文件的migrations包和cmd/migrate命令
+	if err := g.generateSharedFile(filepath.Join(templatesDir, "migrations.go.tmpl"), filepath.Join(MigrationsDir, "migrations.go"), data); err != nil {
+		return fmt.Errorf("生成迁移执行器失败: %v", err)
+	}
+	if err := utils.EnsureDir(filepath.Join("cmd", "migrate")); err != nil {
+		return fmt.Errorf("无法创建命令目录: %v", err)
+	}
+	if err := g.generateSharedFile(filepath.Join(templatesDir, "main.go.tmpl"), filepath.Join("cmd", "migrate", "main.go"), data); err != nil {
+		return fmt.Errorf("生成迁移命令失败: %v", err)
+	}
+	return nil
+}
+
+// migrationDialect 返回迁移使用的SQL方言，未指定时读取项目配置中的数据库驱动，默认为mysql
+func (g *Generator) migrationDialect() (schema.Dialect, error) {
+	name := g.Dialect
+	if name == "" {
+		name = configuredDriver()
+	}
+	if name == "" {
+		name = string(schema.MySQL)
+	}
+	return schema.ParseDialect(name)
+}
+
+// configDriverPattern 匹配config.go中默认配置的数据库驱动
+var configDriverPattern = regexp.MustCompile(`Driver:\s*"(\w+)"`)
+
+// tomlDriverPattern 匹配TOML配置文件中的数据库驱动
+var tomlDriverPattern = regexp.MustCompile(`(?m)^\s*driver\s*=\s*"(\w+)"`)
+
+// configuredDriver 读取当前目录下项目配置的数据库驱动
+func configuredDriver() string {
+	return projectDriver(".")
+}
+
+// projectDriver 依次从项目根目录和config目录中的配置文件(config.yaml、config.yml、config.toml、config.json)
+// 和config/config.go的默认配置中读取projectDir项目的数据库驱动，引用环境变量的驱动忽略
+func projectDriver(projectDir string) string {
+	for _, dir := range []string{".", "config"} {
+		for _, ext := range []string{".yaml", ".yml", ".toml", ".json"} {
+			content, err := os.ReadFile(filepath.Join(projectDir, dir, "config"+ext))
+			if err != nil {
+				continue
+			}
+			driver := ""
+			if ext == ".toml" {
+				if match := tomlDriverPattern.FindSubmatch(content); match != nil {
+					driver = string(match[1])
+				}
+			} else {
+				// YAML兼容JSON
+				var config struct {
+					Database struct {
+						Driver string `yaml:"driver"`
+					} `yaml:"database"`
+				}
+				if yaml.Unmarshal(content, &config) == nil {
+					driver = config.Database.Driver
+				}
+			}
+			if driver != "" && !strings.Contains(driver, "$") {
+				return driver
+			}
+		}
+	}
+	
+	content, err := os.ReadFile(filepath.Join(projectDir, "config", "config.go"))
+	if err != nil {
+		return ""
+	}
+	if match := configDriverPattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// listMigrations 返回migrations目录中的迁移，按版本排序
+func listMigrations() ([]migrationFile, error) {
+	entries, err := os.ReadDir(MigrationsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法读取迁移目录: %v", err)
+	}
+	
+	var migrations []migrationFile
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		migrations = append(migrations, migrationFile{
+			Version: version,
+			Name:    match[2],
+			Path:    filepath.Join(MigrationsDir, entry.Name()),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// nextMigrationVersion 返回新迁移的版本号，使用当前UTC时间，
+// 不晚于已有迁移时顺延，保证同一秒内生成的多个迁移也按生成顺序执行
+func nextMigrationVersion() (string, error) {
+	version, _ := strconv.ParseInt(time.Now().UTC().Format("20060102150405"), 10, 64)
+	migrations, err := listMigrations()
+	if err != nil {
+		return "", err
+	}
+	if n := len(migrations); n > 0 && migrations[n-1].Version >= version {
+		version = migrations[n-1].Version + 1
+	}
+	return strconv.FormatInt(version, 10), nil
+}
+
+// migrationName 将迁移名称转换为小写下划线形式，例如 AddIndexToUsers -> add_index_to_users
+func migrationName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	
+	var parts []string
+	for _, part := range strings.Split(toSnakeName(name), "_") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// gormSettings 解析后的gorm标签，键为大写的标签名
+type gormSettings map[string]string
+
+// parseGormTag 解析gorm标签，例如 column:user_name;size:64;not null
+func parseGormTag(tag string) gormSettings {
+	settings := gormSettings{}
+	for _, part := range strings.Split(tag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, ":")
+		settings[strings.ToUpper(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return settings
+}
+
+// has 判断是否设置了标签
+func (t gormSettings) has(keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := t[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// modelTable 按GORM迁移的规则将模型字段转换为表结构：
+// 列名、主键、非空、默认值和索引来自gorm标签，列类型按方言由Go类型映射，关联字段不生成列
+func modelTable(name string, fields Fields, dialect schema.Dialect) *schema.Table {
+	table := &schema.Table{Name: name}
+	var idColumn *schema.Column
+	var idType string
+	
+	for _, field := range fields {
+		tag := parseGormTag(field.Gorm)
+		if tag["-"] == "all" || tag["-"] == "migration" || tag.has("FOREIGNKEY", "MANY2MANY", "REFERENCES", "POLYMORPHIC") {
+			continue
+		}
+	
+		size, _ := strconv.Atoi(tag["SIZE"])
+		typ := tag["TYPE"]
+		if typ == "" {
+			typ = sqlColumnType(dialect, field.Type, size, tag.has("SERIALIZER"))
+		}
+		if typ == "" {
+			continue
+		}
+	
+		column := &schema.Column{
+			Name:    tag["COLUMN"],
+			Type:    typ,
+			NotNull: tag.has("NOT NULL"),
+			Default: tag["DEFAULT"],
+			Comment: tag["COMMENT"],
+		}
+		if column.Name == "" {
+			column.Name = toSnakeName(field.Name)
+		}
+		if column.Default != "" && strings.TrimPrefix(field.Type, "*") == "string" &&
+			!strings.HasPrefix(column.Default, "'") && !strings.EqualFold(column.Default, "null") {
+			column.Default = schema.String(column.Default)
+		}
+		table.Columns = append(table.Columns, column)
+	
+		if tag.has("PRIMARYKEY", "PRIMARY_KEY") {
+			column.NotNull = true
+			column.AutoIncrement = tag["AUTOINCREMENT"] != "false" && isIntegerType(strings.TrimPrefix(field.Type, "*"))
+			table.PrimaryKey = append(table.PrimaryKey, column.Name)
+		} else if tag.has("AUTOINCREMENT") && tag["AUTOINCREMENT"] != "false" {
+			column.AutoIncrement = true
+		}
+		if field.Name == "ID" {
+			idColumn, idType = column, field.Type
+		}
+	
+		if tag.has("UNIQUE") {
+			// 与GORM一致，唯一约束命名为 uni_<表名>_<列名>
+			addIndex(table, fmt.Sprintf("uni_%s_%s", name, column.Name), column.Name, true)
+		}
+		for _, key := range []string{"INDEX", "UNIQUEINDEX"} {
+			value, ok := tag[key]
+			if !ok {
+				continue
+			}
+			indexName, options, _ := strings.Cut(value, ",")
+			if indexName == "" {
+				indexName = fmt.Sprintf("idx_%s_%s", name, column.Name)
+			}
+			addIndex(table, indexName, column.Name, key == "UNIQUEINDEX" || strings.Contains(strings.ToLower(options), "unique"))
+		}
+	}
+	
+	// 没有声明主键时GORM使用ID字段作为主键
+	if len(table.PrimaryKey) == 0 && idColumn != nil {
+		idColumn.NotNull = true
+		idColumn.AutoIncrement = isIntegerType(strings.TrimPrefix(idType, "*"))
+		table.PrimaryKey = []string{idColumn.Name}
+	}
+	if len(table.PrimaryKey) > 1 {
+		for _, column := range table.Columns {
+			column.AutoIncrement = false
+		}
+	}
+	return table
+}
+
+// addIndex 将列加入具名索引，同名索引合并为联合索引
+func addIndex(table *schema.Table, name string, column string, unique bool) {
+	for i := range table.Indexes {
+		if table.Indexes[i].Name == name {
+			table.Indexes[i].Columns = append(table.Indexes[i].Columns, column)
+			table.Indexes[i].Unique = table.Indexes[i].Unique || unique
+			return
+		}
+	}
+	table.Indexes = append(table.Indexes, schema.Index{Name: name, Columns: []string{column}, Unique: unique})
+}
+
+// sqlColumnType 返回Go类型在方言中对应的列类型，关联、切片等不对应列的类型返回空
+func sqlColumnType(dialect schema.Dialect, goType string, size int, serializer bool) string {
+	pick := func(mysql, postgres, sqlite string) string {
+		switch dialect {
+		case schema.PostgreSQL:
+			return postgres
+		case schema.SQLite:
+			return sqlite
+		}
+		return mysql
+	}
+	
+	t := strings.TrimPrefix(goType, "*")
+	if serializer {
+		return pick("json", "jsonb", "text")
+	}
+	switch t {
+	case "string":
+		if size > 0 {
+			return fmt.Sprintf("varchar(%d)", size)
+		}
+		return pick("varchar(255)", "text", "text")
+	case "bool":
+		return "boolean"
+	case "int8":
+		return pick("tinyint", "smallint", "integer")
+	case "int16":
+		return pick("smallint", "smallint", "integer")
+	case "int32":
+		return pick("int", "integer", "integer")
+	case "int", "int64":
+		return pick("bigint", "bigint", "integer")
+	case "uint8":
+		return pick("tinyint unsigned", "smallint", "integer")
+	case "uint16":
+		return pick("smallint unsigned", "integer", "integer")
+	case "uint32":
+		return pick("int unsigned", "bigint", "integer")
+	case "uint", "uint64":
+		return pick("bigint unsigned", "bigint", "integer")
+	case "float32":
+		return pick("float", "real", "real")
+	case "float64":
+		return pick("double", "double precision", "real")
+	case "time.Time", "gorm.DeletedAt", "sql.NullTime":
+		return pick("datetime(3)", "timestamptz", "datetime")
+	case "[]byte":
+		return pick("longblob", "bytea", "blob")
+	case "sql.NullString":
+		return pick("varchar(255)", "text", "text")
+	case "sql.NullInt64":
+		return pick("bigint", "bigint", "integer")
+	case "sql.NullBool":
+		return "boolean"
+	case "sql.NullFloat64":
+		return pick("double", "double precision", "real")
+	}
+	
+	// 切片、映射和models中的结构体是关联或需要序列化的字段，不生成列
+	if t == "" || t == "any" || t == "interface{}" || strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") ||
+		!strings.Contains(t, ".") && t[0] >= 'A' && t[0] <= 'Z' {
+		return ""
+	}
+	return pick("longtext", "text", "text")
+}
@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// 测试根据模型字段和gorm标签生成表结构
+func TestModelTable(t *testing.T) {
+	fields := Fields{
+		{Name: "ID", Type: "uint", JSONName: "id", Gorm: "primaryKey"},
+		{Name: "Email", Type: "string", Gorm: "size:191;not null;uniqueIndex"},
+		{Name: "Nickname", Type: "*string", Gorm: "column:nick;default:guest"},
+		{Name: "Status", Type: "string", Gorm: "type:varchar(16);index:idx_status_role"},
+		{Name: "Role", Type: "int8", Gorm: "index:idx_status_role"},
+		{Name: "Tags", Type: "[]string", Gorm: "serializer:json"},
+		{Name: "UserID", Type: "uint", Gorm: "not null;index"},
+		{Name: "User", Type: "*User", Gorm: "foreignKey:UserID"},
+		{Name: "Items", Type: "[]OrderItem"},
+		{Name: "Ignored", Type: "string", Gorm: "-:migration"},
+		{Name: "DeletedAt", Type: "gorm.DeletedAt", Gorm: "index"},
+	}
+	
+	table := modelTable("orders", fields, schema.MySQL)
+	assert.Equal(t, []string{"id"}, table.PrimaryKey)
+	assert.Equal(t, &schema.Column{Name: "id", Type: "bigint unsigned", NotNull: true, AutoIncrement: true}, table.Columns[0])
+	assert.Equal(t, &schema.Column{Name: "email", Type: "varchar(191)", NotNull: true}, table.Columns[1])
+	assert.Equal(t, &schema.Column{Name: "nick", Type: "varchar(255)", Default: "'guest'"}, table.Columns[2], "字符串默认值应加上引号")
+	assert.Equal(t, "varchar(16)", table.Columns[3].Type, "type标签应原样使用")
+	assert.Equal(t, "json", table.Columns[5].Type, "序列化字段应使用JSON列")
+	
+	var names []string
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"id", "email", "nick", "status", "role", "tags", "user_id", "deleted_at"}, names, "关联字段和忽略迁移的字段不应生成列")
+	assert.Equal(t, []schema.Index{
+		{Name: "idx_orders_email", Columns: []string{"email"}, Unique: true},
+		{Name: "idx_status_role", Columns: []string{"status", "role"}},
+		{Name: "idx_orders_user_id", Columns: []string{"user_id"}},
+		{Name: "idx_orders_deleted_at", Columns: []string{"deleted_at"}},
+	}, table.Indexes, "同名索引应合并为联合索引")
+	
+	// 没有声明主键时使用ID字段
+	table = modelTable("events", Fields{{Name: "ID", Type: "string"}, {Name: "Created", Type: "time.Time"}}, schema.PostgreSQL)
+	assert.Equal(t, []string{"id"}, table.PrimaryKey)
+	assert.False(t, table.Columns[0].AutoIncrement, "字符串主键不应自增")
+	assert.Equal(t, "timestamptz", table.Columns[1].Type)
+}
+
+// 测试迁移名称格式化
+func TestMigrationName(t *testing.T) {
+	assert.Equal(t, "add_index_to_users", migrationName("AddIndexToUsers"))
+	assert.Equal(t, "add_status_to_orders", migrationName("add-status to orders"))
+	assert.Equal(t, "", migrationName("--"))
+}
+
+// 测试生成迁移和模型的建表迁移
+func TestGenerateMigration(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":          "model {{.Name}}",
+		"migration/up.sql.tmpl":        "-- up {{.Dialect}}\n{{range .Up}}{{.}};\n{{end}}",
+		"migration/down.sql.tmpl":      "-- down {{.Dialect}}\n{{range .Down}}{{.}};\n{{end}}",
+		"migration/migrations.go.tmpl": "package migrations",
+		"migration/main.go.tmpl":       "package main // {{.Package}} {{.Dialect}}",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	// 创建生成器，方言从项目配置中读取
+	require.NoError(t, os.MkdirAll("config", 0755), "无法创建配置目录")
+	createTempFile(t, "config", "config.go", `Driver:   "postgres",`)
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Migration = true
+	g.Options.SoftDelete = true
+	
+	// 测试生成模型时生成建表迁移
+	err = g.GenerateModel("User", "myapp")
+	require.NoError(t, err, "生成模型失败")
+	migrations, err := listMigrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 1, "应生成一个建表迁移")
+	assert.Equal(t, "create_users", migrations[0].Name)
+	
+	content, err := os.ReadFile(migrations[0].Path)
+	require.NoError(t, err, "无法读取升级迁移")
+	assert.Contains(t, string(content), "-- up postgres\nCREATE TABLE \"users\" (\n  \"id\" bigserial NOT NULL,\n  \"name\" text,\n")
+	assert.Contains(t, string(content), "CREATE INDEX \"idx_users_deleted_at\" ON \"users\" (\"deleted_at\");\n", "软删除列应有索引")
+	content, err = os.ReadFile(strings.TrimSuffix(migrations[0].Path, ".up.sql") + ".down.sql")
+	require.NoError(t, err, "无法读取回滚迁移")
+	assert.Equal(t, "-- down postgres\nDROP TABLE IF EXISTS \"users\";\n", string(content))
+	
+	content, err = os.ReadFile(filepath.Join("cmd", "migrate", "main.go"))
+	require.NoError(t, err, "应生成迁移命令")
+	assert.Equal(t, "package main // myapp postgres", string(content))
+	
+	// 已有建表迁移时不重复生成
+	require.NoError(t, os.Remove(filepath.Join("models", "user.go")))
+	require.NoError(t, g.GenerateModel("User", "myapp"), "重新生成模型失败")
+	migrations, _ = listMigrations()
+	assert.Len(t, migrations, 1, "不应重复生成建表迁移")
+	
+	// 表已存在的模型不生成迁移
+	g.Specs = map[string]*ResourceSpec{"Legacy": {Existing: true, Complete: true}}
+	require.NoError(t, g.GenerateModel("Legacy", "myapp"), "生成模型失败")
+	migrations, _ = listMigrations()
+	assert.Len(t, migrations, 1, "已存在的表不应生成建表迁移")
+	
+	// 测试生成空迁移，同一秒内生成的迁移版本递增
+	g.Dialect = "sqlite"
+	require.NoError(t, g.GenerateMigration("AddIndexToUsers", "myapp"), "生成迁移失败")
+	require.NoError(t, g.GenerateMigration("add_status", "myapp"), "生成迁移失败")
+	migrations, _ = listMigrations()
+	require.Len(t, migrations, 3)
+	assert.Equal(t, "add_index_to_users", migrations[1].Name)
+	assert.Equal(t, "add_status", migrations[2].Name)
+	assert.Less(t, migrations[1].Version, migrations[2].Version, "迁移版本应递增")
+	content, err = os.ReadFile(migrations[2].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "-- up sqlite\n", string(content), "--dialect应优先于项目配置")
+	
+	// 不支持的方言
+	g.Dialect = "oracle"
+	assert.Error(t, g.GenerateMigration("noop", "myapp"), "不支持的方言应返回错误")
+	assert.Error(t, NewGenerator("").GenerateMigration("", "myapp"), "迁移名称为空应返回错误")
+}
+
+// 测试从项目配置中读取数据库驱动
+func TestConfiguredDriver(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	assert.Equal(t, "", configuredDriver(), "没有配置时应返回空字符串")
+	
+	require.NoError(t, os.MkdirAll("config", 0755), "无法创建配置目录")
+	createTempFile(t, "config", "config.go", `Driver:   "mysql",`)
+	assert.Equal(t, "mysql", configuredDriver(), "应读取config.go中的默认配置")
+	
+	createTempFile(t, "config", "config.toml", "[database]\ndriver = \"postgres\"\n")
+	assert.Equal(t, "postgres", configuredDriver(), "应读取TOML配置文件")
+	
+	createTempFile(t, tempDir, "config.json", `{"database": {"driver": "${DB_DRIVER}"}}`)
+	assert.Equal(t, "postgres", configuredDriver(), "引用环境变量的驱动应忽略")
+	
+	createTempFile(t, tempDir, "config.yaml", "database:\n  driver: sqlite\n")
+	assert.Equal(t, "sqlite", configuredDriver(), "项目根目录的YAML配置文件优先")
+}
@@ -11,10 +11,14 @@ import (
 
 // ModelData 模型模板数据
 type ModelData struct {
-	Name       string // 模型名称，首字母大写
-	TableName  string // 表名，全小写
-	VarName    string // 变量名称，首字母小写
-	Package    string // 项目包名
+	Name      string // 模型名称，首字母大写
+	TableName string // 表名，全小写
+	VarName   string // 变量名称，首字母小写
+	Package   string // 项目包名
+	Fields    Fields // 模型字段，为空时使用默认的Name字段
+	Complete  bool   // Fields已包含全部列，不再添加ID和时间戳
+	Comment   string // 模型说明
+	FeatureOptions
 }
 
 // GenerateModel 生成模型代码
@@ -23,11 +27,16 @@ func (g *Generator) GenerateModel(name string, packageName string) error {
 	name = formatName(name)
 	
 	// 准备模板数据
+	spec := g.resourceSpec(name)
 	data := ModelData{
-		Name:      name,
-		TableName: strings.ToLower(name) + "s",
-		VarName:   strings.ToLower(name[:1]) + name[1:],
-		Package:   packageName,
+		Name:           name,
+		TableName:      spec.tableName(name),
+		VarName:        strings.ToLower(name[:1]) + name[1:],
+		Package:        packageName,
+		Fields:         spec.Fields,
+		Complete:       spec.Complete,
+		Comment:        spec.Comment,
+		FeatureOptions: g.Options,
 	}
 	
 	// 确保目录存在
@@ -51,5 +60,83 @@ func (g *Generator) GenerateModel(name string, packageName string) error {
 	}
 	
 	fmt.Printf("已生成模型文件: %s\n", outputFile)
+	
+	// 生成建表迁移，表已存在时只记录表结构快照
+	if g.Options.Migration {
+		if err := g.generateModelMigration(data, spec.Existing); err != nil {
+			return err
+		}
+	}
 	return nil
-} 
\ No newline at end of file
+}
+
+// columns 返回模型结构体的全部字段，与模型模板的输出一致
+func (d ModelData) columns() Fields {
+	if d.Complete {
+		return d.Fields
+	}
+	
+	fields := Fields{{Name: "ID", Type: "uint", JSONName: "id", Gorm: "primaryKey"}}
+	if len(d.Fields) > 0 {
+		fields = append(fields, d.Fields...)
+	} else {
+		fields = append(fields, Field{Name: "Name", Type: "string", JSONName: "name"})
+	}
+	if d.Versioned {
+		fields = append(fields, Field{Name: "Version", Type: "int", JSONName: "version", Gorm: "not null;default:1"})
+	}
+	fields = append(fields,
+		Field{Name: "CreatedAt", Type: "time.Time", JSONName: "created_at"},
+		Field{Name: "UpdatedAt", Type: "time.Time", JSONName: "updated_at"},
+	)
+	if d.SoftDelete {
+		fields = append(fields, Field{Name: "DeletedAt", Type: "gorm.DeletedAt", JSONName: "-", Gorm: "index"})
+	}
+	return fields
+}
+
+// DTOData 数据结构模板数据
+type DTOData struct {
+	Name    string // 结构体名称，首字母大写
+	Comment string // 结构体说明
+	Package string // 项目包名
+	Fields  Fields // 结构体字段
+}
+
+// GenerateDTO 在models目录中生成不含gorm标签和表名的数据结构，字段来自资源定义
+func (g *Generator) GenerateDTO(name string, packageName string) error {
+	// 格式化名称
+	name = formatName(name)
+	
+	// 准备模板数据
+	spec := g.resourceSpec(name)
+	data := DTOData{
+		Name:    name,
+		Comment: spec.Comment,
+		Package: packageName,
+		Fields:  spec.Fields,
+	}
+	
+	// 确保目录存在
+	outputDir := filepath.Join("models")
+	if err := utils.EnsureDir(outputDir); err != nil {
+		return fmt.Errorf("无法创建模型目录: %v", err)
+	}
+	
+	// 数据结构文件路径
+	outputFile := filepath.Join(outputDir, strings.ToLower(name)+".go")
+	
+	// 检查文件是否已存在
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		return fmt.Errorf("模型文件已存在: %s", outputFile)
+	}
+	
+	// 生成数据结构文件
+	templatePath := filepath.Join(g.TemplatesDir, "component", "model", "dto.go.tmpl")
+	if err := g.GenerateFromTemplate(templatePath, outputFile, data); err != nil {
+		return fmt.Errorf("生成数据结构失败: %v", err)
+	}
+	
+	fmt.Printf("已生成数据结构文件: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,828 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yggai/gs/pkg/openapi"
+)
+
+// initialisms 生成Go字段名时整体大写的缩写
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "sql": true, "uid": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// ignoredFields 模型模板中固定生成的字段，来自外部定义时跳过
+var ignoredFields = map[string]bool{
+	"ID": true, "Version": true, "CreatedAt": true, "UpdatedAt": true,
+}
+
+// openAPIPlan 从OpenAPI文档解析出的生成计划
+type openAPIPlan struct {
+	Resources []string                 // 按标签或路径前缀划分的资源
+	DTOs      []string                 // 资源模型以外的数据结构
+	Specs     map[string]*ResourceSpec // 资源和数据结构的定义
+	Warnings  []string                 // 无法转换的结构，例如oneOf、anyOf
+}
+
+// openAPIOperation 文档中的一个接口
+type openAPIOperation struct {
+	Method    string
+	Path      string
+	Operation *openapi.Operation
+}
+
+// GenerateFromOpenAPI 读取OpenAPI文档，由components.schemas生成模型和数据结构，
+// 并按标签(没有标签时按路径前缀)为每个资源生成服务、控制器、路由和测试
+func (g *Generator) GenerateFromOpenAPI(path string, packageName string) error {
+	doc, err := openapi.Load(path)
+	if err != nil {
+		return err
+	}
+	
+	plan, err := planOpenAPI(doc)
+	if err != nil {
+		return err
+	}
+	
+	if g.Specs == nil {
+		g.Specs = map[string]*ResourceSpec{}
+	}
+	for name, spec := range plan.Specs {
+		g.Specs[name] = spec
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Printf("警告: %s\n", warning)
+	}
+	
+	// 生成数据结构
+	for _, name := range plan.DTOs {
+		if err := g.GenerateDTO(name, packageName); err != nil {
+			return err
+		}
+	}
+	
+	// 生成资源
+	for _, name := range plan.Resources {
+		if err := g.GenerateFeature(name, packageName); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已根据 %s 生成 %d 个资源和 %d 个数据结构\n", path, len(plan.Resources), len(plan.DTOs))
+	return nil
+}
+
+// planOpenAPI 将文档中的接口按标签或路径前缀分组，判断哪些接口符合REST约定
+func planOpenAPI(doc *openapi.Document) (*openAPIPlan, error) {
+	plan := &openAPIPlan{Specs: map[string]*ResourceSpec{}, Warnings: flattenAllOf(doc)}
+	
+	groups, keys := groupOperations(doc)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("OpenAPI文档中没有定义接口")
+	}
+	
+	models := map[string]bool{}
+	for _, key := range keys {
+		name := formatName(SingularForm(goName(key)))
+		if name == "" {
+			return nil, fmt.Errorf("无法根据 %q 确定资源名称", key)
+		}
+		if _, ok := plan.Specs[name]; ok {
+			return nil, fmt.Errorf("资源名称重复: %s", name)
+		}
+	
+		spec, schema := planResource(doc, name, key, groups[key])
+		if schema != "" {
+			spec.Fields = schemaFields(doc, doc.Schema(schema))
+			if formatName(goName(schema)) == name {
+				models[schema] = true
+			}
+		}
+		plan.Resources = append(plan.Resources, name)
+		plan.Specs[name] = spec
+	}
+	
+	// 资源模型以外的对象类型组件生成数据结构
+	var schemas []string
+	if doc.Components != nil {
+		for name := range doc.Components.Schemas {
+			schemas = append(schemas, name)
+		}
+	}
+	sort.Strings(schemas)
+	for _, schema := range schemas {
+		if models[schema] || !isObject(doc.Schema(schema)) {
+			continue
+		}
+		name := formatName(goName(schema))
+		if spec, ok := plan.Specs[name]; ok && spec.CRUD {
+			return nil, fmt.Errorf("数据结构 %s 与资源模型重名", schema)
+		}
+		plan.DTOs = append(plan.DTOs, name)
+		plan.Specs[name] = &ResourceSpec{
+			Fields:  schemaFields(doc, doc.Schema(schema)),
+			Comment: firstLine(doc.Schema(schema).Description),
+		}
+	}
+	
+	return plan, nil
+}
+
+// groupOperations 按第一个标签分组，没有标签的接口按公共前缀之后的第一段路径分组
+func groupOperations(doc *openapi.Document) (map[string][]openAPIOperation, []string) {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	
+	prefix := commonPrefix(paths)
+	groups := map[string][]openAPIOperation{}
+	var keys []string
+	for _, path := range paths {
+		for _, method := range openapi.Methods {
+			op := doc.Paths[path].Operation(method)
+			if op == nil {
+				continue
+			}
+	
+			key := ""
+			if len(op.Tags) > 0 {
+				key = op.Tags[0]
+			} else if segments := staticSegments(path); len(segments) > len(prefix) {
+				key = segments[len(prefix)]
+			} else if len(segments) > 0 {
+				key = segments[len(segments)-1]
+			}
+	
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], openAPIOperation{Method: method, Path: path, Operation: op})
+		}
+	}
+	return groups, keys
+}
+
+// planResource 计算资源的路由组路径、符合REST约定的接口和自定义接口，并返回资源模型对应的组件名
+func planResource(doc *openapi.Document, name string, key string, ops []openAPIOperation) (*ResourceSpec, string) {
+	spec := &ResourceSpec{BasePath: basePathFor(name, key, ops)}
+	
+	schema := ""
+	if doc.Schema(name) != nil {
+		schema = name
+	}
+	
+	// 集合路径需为复数名词或存在 /{id} 路径，例如 /login 上的POST不视为创建
+	collection := false
+	segments := staticSegments(spec.BasePath)
+	if len(segments) > 0 && SingularForm(segments[len(segments)-1]) != segments[len(segments)-1] {
+		collection = true
+	}
+	for _, op := range ops {
+		if isParamPath(relativePath(op.Path, spec.BasePath)) {
+			collection = true
+		}
+	}
+	
+	used := map[string]bool{}
+	operations := Operations{}
+	requests := map[string]string{}
+	for _, op := range ops {
+		rel := relativePath(op.Path, spec.BasePath)
+	
+		// 集合路径和 /{id} 路径上的标准方法沿用GenerateFeature的命名，只生成文档中声明的接口
+		crud := true
+		switch {
+		case !collection:
+			crud = false
+		case rel == "" && op.Method == "GET":
+			operations.List = true
+		case rel == "" && op.Method == "POST":
+			operations.Create = true
+		case isParamPath(rel) && op.Method == "GET":
+			operations.Get = true
+		case isParamPath(rel) && op.Method == "PUT":
+			operations.Put = true
+		case isParamPath(rel) && op.Method == "PATCH":
+			operations.Patch = true
+		case isParamPath(rel) && op.Method == "DELETE":
+			operations.Delete = true
+		default:
+			crud = false
+		}
+		if crud {
+			spec.CRUD = true
+			if schema == "" {
+				schema = crudSchema(doc, op)
+			}
+			if ref := openapi.SchemaRef(requestSchema(op.Operation)); ref != "" && isObject(doc.Schema(ref)) {
+				requests[op.Method] = ref
+			}
+			continue
+		}
+	
+		handler := Handler{
+			Name:    handlerName(op, name, rel),
+			Method:  op.Method,
+			Path:    ginPath(rel),
+			Summary: firstLine(op.Operation.Summary),
+		}
+		if ref := openapi.SchemaRef(requestSchema(op.Operation)); ref != "" && isObject(doc.Schema(ref)) {
+			handler.Request = formatName(goName(ref))
+		}
+		for used[handler.Name] {
+			handler.Name += CapitalizeFirst(strings.ToLower(op.Method))
+		}
+		used[handler.Name] = true
+		spec.Handlers = append(spec.Handlers, handler)
+	}
+	
+	// 创建和更新接口绑定文档中声明的请求体，与资源模型相同时使用由模型字段生成的请求结构
+	if spec.CRUD {
+		spec.Operations = &operations
+		request := func(ref string) string {
+			if ref == "" || ref == schema {
+				return ""
+			}
+			return formatName(goName(ref))
+		}
+		spec.CreateRequest = request(requests["POST"])
+		if spec.UpdateRequest = request(requests["PUT"]); !operations.Put {
+			spec.UpdateRequest = request(requests["PATCH"])
+		}
+	}
+	
+	// 自定义接口的处理函数不能与增删改查接口重名
+	if spec.CRUD {
+		for i := range spec.Handlers {
+			for _, reserved := range []string{"Get" + PluralForm(name), "Get" + name, "Create" + name, "Update" + name, "Delete" + name} {
+				if spec.Handlers[i].Name == reserved {
+					spec.Handlers[i].Name += "Custom"
+				}
+			}
+		}
+	}
+	return spec, schema
+}
+
+// relativePath 返回相对路由组的路径
+func relativePath(path string, basePath string) string {
+	if basePath == "/" {
+		return path
+	}
+	return strings.TrimPrefix(path, basePath)
+}
+
+// crudSchema 从标准接口的请求体或响应体推断资源模型对应的组件名
+func crudSchema(doc *openapi.Document, op openAPIOperation) string {
+	if ref := openapi.SchemaRef(requestSchema(op.Operation)); ref != "" && isObject(doc.Schema(ref)) {
+		return ref
+	}
+	for _, code := range []string{"200", "201"} {
+		response, ok := op.Operation.Responses[code]
+		if !ok || response == nil {
+			continue
+		}
+		media, ok := response.Content["application/json"]
+		if !ok || media == nil || media.Schema == nil {
+			continue
+		}
+		schema := media.Schema
+		if schema.Type == "array" {
+			schema = schema.Items
+		}
+		if ref := openapi.SchemaRef(schema); ref != "" && isObject(doc.Schema(ref)) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// requestSchema 返回接口的JSON请求体
+func requestSchema(op *openapi.Operation) *openapi.Schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	if media, ok := op.RequestBody.Content["application/json"]; ok && media != nil {
+		return media.Schema
+	}
+	return nil
+}
+
+// basePathFor 计算资源的路由组路径：分组内路径的公共前缀，截断到资源名所在的路径段
+func basePathFor(name string, key string, ops []openAPIOperation) string {
+	paths := make([]string, 0, len(ops))
+	for _, op := range ops {
+		paths = append(paths, op.Path)
+	}
+	
+	prefix := staticSegments(paths[0])
+	for _, path := range paths[1:] {
+		prefix = sharedSegments(prefix, staticSegments(path))
+	}
+	
+	// 公共前缀可能包含自定义接口的路径，例如只有 /orders/search 时截断到 /orders
+	for i, segment := range prefix {
+		if segmentMatches(segment, name, key) {
+			prefix = prefix[:i+1]
+			break
+		}
+	}
+	return "/" + strings.Join(prefix, "/")
+}
+
+// segmentMatches 判断路径段是否为资源名的单复数形式
+func segmentMatches(segment string, name string, key string) bool {
+	word := formatName(goName(segment))
+	return segment == key || word == name || word == PluralForm(name) || formatName(SingularForm(word)) == name
+}
+
+// handlerName 返回自定义接口的处理函数名，优先使用operationId
+func handlerName(op openAPIOperation, name string, rel string) string {
+	if op.Operation.OperationID != "" {
+		return formatName(goName(op.Operation.OperationID))
+	}
+	parts := []string{CapitalizeFirst(strings.ToLower(op.Method)), name}
+	for _, segment := range strings.Split(rel, "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			parts = append(parts, goName(segment))
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// ginPath 将相对路径转换为gin格式，紧跟在路由组之后的路径参数统一命名为id，避免与 /:id 路由冲突
+func ginPath(rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if i == 1 {
+				segments[i] = ":id"
+			} else {
+				segments[i] = ":" + segment[1:len(segment)-1]
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isParamPath 判断相对路径是否只有一个路径参数，例如 /{id}
+func isParamPath(rel string) bool {
+	return strings.HasPrefix(rel, "/{") && strings.HasSuffix(rel, "}") && strings.Count(rel, "/") == 1
+}
+
+// commonPrefix 返回所有路径开头的公共静态路径段，至少为每个路径保留一段
+func commonPrefix(paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	prefix := staticSegments(paths[0])
+	for _, path := range paths[1:] {
+		prefix = sharedSegments(prefix, staticSegments(path))
+	}
+	for _, path := range paths {
+		if n := len(staticSegments(path)); n <= len(prefix) {
+			prefix = prefix[:max(n-1, 0)]
+		}
+	}
+	return prefix
+}
+
+// staticSegments 返回路径中第一个路径参数之前的路径段
+func staticSegments(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") {
+			break
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// sharedSegments 返回两组路径段的公共前缀
+func sharedSegments(a []string, b []string) []string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// schemaFields 将对象类型组件的属性转换为模型字段
+func schemaFields(doc *openapi.Document, schema *openapi.Schema) Fields {
+	if schema == nil {
+		return nil
+	}
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	
+	var fields Fields
+	for _, prop := range schema.Properties {
+		field := Field{
+			Name:     goName(prop.Name),
+			JSONName: prop.Name,
+			ReadOnly: prop.Schema.ReadOnly,
+			Comment:  firstLine(prop.Schema.Description),
+		}
+		if field.Name == "" || ignoredFields[field.Name] {
+			continue
+		}
+	
+		resolved := resolveSchema(doc, prop.Schema)
+		field.Type = goType(doc, prop.Schema)
+		field.Gorm = gormTag(field.Type, resolved, required[prop.Name] && !prop.Schema.Nullable)
+		field.Binding = bindingRules(field.Type, resolved, required[prop.Name])
+		if needsSample(resolved) {
+			field.Sample = sampleValue(doc, prop.Schema, 0)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// schemaFlattener 合并文档中的allOf，记录无法转换的oneOf、anyOf
+type schemaFlattener struct {
+	doc      *openapi.Document
+	done     map[*openapi.Schema]bool
+	warnings []string
+}
+
+// flattenAllOf 将文档中组件和接口请求体、响应体里的allOf就地合并为对象类型，
+// 返回使用了oneOf、anyOf的位置的警告
+func flattenAllOf(doc *openapi.Document) []string {
+	f := &schemaFlattener{doc: doc, done: map[*openapi.Schema]bool{}}
+	if doc.Components != nil {
+		names := make([]string, 0, len(doc.Components.Schemas))
+		for name := range doc.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			f.flatten(doc.Components.Schemas[name], "组件 "+name)
+		}
+	}
+	
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range openapi.Methods {
+			op := doc.Paths[path].Operation(method)
+			if op == nil {
+				continue
+			}
+			f.flatten(requestSchema(op), method+" "+path+" 的请求体")
+			codes := make([]string, 0, len(op.Responses))
+			for code := range op.Responses {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				if response := op.Responses[code]; response != nil {
+					if media := response.Content["application/json"]; media != nil {
+						f.flatten(media.Schema, method+" "+path+" 的"+code+"响应")
+					}
+				}
+			}
+		}
+	}
+	return f.warnings
+}
+
+// flatten 合并schema及其属性、元素中的allOf：依次合并各成员(经$ref解析并递归合并)的属性和必填字段，
+// 最后合并自身声明的属性，同名属性以后出现的为准。只有一个$ref成员时视为对该组件的引用
+func (f *schemaFlattener) flatten(schema *openapi.Schema, name string) {
+	if schema == nil || f.done[schema] {
+		return
+	}
+	f.done[schema] = true
+	
+	for _, variant := range []struct {
+		keyword string
+		schemas []*openapi.Schema
+	}{{"oneOf", schema.OneOf}, {"anyOf", schema.AnyOf}} {
+		if len(variant.schemas) > 0 {
+			f.warnings = append(f.warnings, fmt.Sprintf("%s 使用了%s，不支持多态结构，需要手动定义对应的类型", name, variant.keyword))
+		}
+	}
+	
+	if members := schema.AllOf; len(members) > 0 {
+		schema.AllOf = nil
+		if len(members) == 1 && members[0].Ref != "" && len(schema.Properties) == 0 {
+			schema.Ref = members[0].Ref
+			return
+		}
+	
+		own, ownRequired := schema.Properties, schema.Required
+		schema.Properties, schema.Required = nil, nil
+		for _, member := range members {
+			memberName := name
+			if ref := openapi.SchemaRef(member); ref != "" {
+				memberName = "组件 " + ref
+				if member = f.doc.Schema(ref); member == nil {
+					f.warnings = append(f.warnings, fmt.Sprintf("%s 的allOf引用的组件 %s 不存在", name, ref))
+					continue
+				}
+			}
+			f.flatten(member, memberName)
+			mergeProperties(schema, member.Properties, member.Required)
+		}
+		mergeProperties(schema, own, ownRequired)
+		if schema.Type == "" {
+			schema.Type = "object"
+		}
+	}
+	
+	for _, prop := range schema.Properties {
+		f.flatten(prop.Schema, name+" 的属性 "+prop.Name)
+	}
+	f.flatten(schema.Items, name+" 的元素")
+	f.flatten(schema.AdditionalProperties, name+" 的值")
+}
+
+// mergeProperties 将属性和必填字段合并到schema，同名属性覆盖已有的属性
+func mergeProperties(schema *openapi.Schema, properties openapi.Properties, required []string) {
+	for _, prop := range properties {
+		replaced := false
+		for i := range schema.Properties {
+			if schema.Properties[i].Name == prop.Name {
+				schema.Properties[i] = prop
+				replaced = true
+			}
+		}
+		if !replaced {
+			schema.Properties = append(schema.Properties, prop)
+		}
+	}
+	for _, name := range required {
+		if !slices.Contains(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// resolveSchema 解析$ref，返回实际的Schema
+func resolveSchema(doc *openapi.Document, schema *openapi.Schema) *openapi.Schema {
+	for i := 0; schema != nil && schema.Ref != "" && i < 8; i++ {
+		schema = doc.Schema(openapi.SchemaRef(schema))
+	}
+	if schema == nil {
+		return &openapi.Schema{}
+	}
+	return schema
+}
+
+// isObject 判断Schema是否为带属性的对象类型
+func isObject(schema *openapi.Schema) bool {
+	return schema != nil && schema.Ref == "" && (schema.Type == "object" || schema.Type == "") && len(schema.Properties) > 0
+}
+
+// goType 将Schema转换为Go类型，引用的对象类型组件使用models中的同名结构体
+func goType(doc *openapi.Document, schema *openapi.Schema) string {
+	if schema == nil {
+		return "interface{}"
+	}
+	if ref := openapi.SchemaRef(schema); ref != "" {
+		if isObject(doc.Schema(ref)) {
+			return formatName(goName(ref))
+		}
+		return goType(doc, resolveSchema(doc, schema))
+	}
+	
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		switch schema.Format {
+		case "int64":
+			return "int64"
+		case "int32":
+			return "int32"
+		}
+		return "int"
+	case "number":
+		if schema.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(doc, schema.Items)
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return "map[string]" + goType(doc, schema.AdditionalProperties)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// gormTag 返回字段的gorm标签，切片、映射和结构体以JSON序列化存储，必填字段的列为not null
+func gormTag(goType string, schema *openapi.Schema, required bool) string {
+	var tags []string
+	switch {
+	case strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "interface{}":
+		tags = append(tags, "serializer:json")
+	case goType == "time.Time" || strings.HasPrefix(goType, "int") || strings.HasPrefix(goType, "float") || goType == "bool":
+	case goType == "string":
+		if schema.MaxLength != nil {
+			tags = append(tags, fmt.Sprintf("size:%d", *schema.MaxLength))
+		}
+	default:
+		tags = append(tags, "serializer:json")
+	}
+	if required {
+		tags = append(tags, "not null")
+	}
+	return strings.Join(tags, ";")
+}
+
+// bindingRules 将Schema约束转换为gin的binding校验规则，非必填字段的约束加上omitempty
+func bindingRules(goType string, schema *openapi.Schema, required bool) string {
+	var rules []string
+	switch schema.Format {
+	case "email":
+		rules = append(rules, "email")
+	case "uri", "url":
+		rules = append(rules, "url")
+	case "uuid":
+		rules = append(rules, "uuid")
+	}
+	if len(schema.Enum) > 0 && !strings.Contains(strings.Join(schema.Enum, ""), " ") {
+		rules = append(rules, "oneof="+strings.Join(schema.Enum, " "))
+	}
+	switch {
+	case schema.Type == "array":
+		rules = appendBound(rules, "min", schema.MinItems)
+		rules = appendBound(rules, "max", schema.MaxItems)
+	case goType == "string":
+		rules = appendBound(rules, "min", schema.MinLength)
+		rules = appendBound(rules, "max", schema.MaxLength)
+	case schema.Type == "integer" || schema.Type == "number":
+		if schema.Minimum != nil {
+			rules = append(rules, "gte="+strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+		}
+		if schema.Maximum != nil {
+			rules = append(rules, "lte="+strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+		}
+	}
+	
+	// bool的零值false无法通过required校验
+	if required && goType != "bool" {
+		return strings.Join(append([]string{"required"}, rules...), ",")
+	}
+	if len(rules) > 0 {
+		return strings.Join(append([]string{"omitempty"}, rules...), ",")
+	}
+	return ""
+}
+
+// appendBound 追加长度或元素个数的上下限规则
+func appendBound(rules []string, name string, value *int) []string {
+	if value == nil {
+		return rules
+	}
+	return append(rules, fmt.Sprintf("%s=%d", name, *value))
+}
+
+// needsSample 判断字段是否有约束，测试请求中需要专门生成合法的值
+func needsSample(schema *openapi.Schema) bool {
+	return schema.Format != "" || len(schema.Enum) > 0 || schema.MinLength != nil || schema.MaxLength != nil ||
+		schema.Minimum != nil || schema.Maximum != nil || schema.MinItems != nil || len(schema.Properties) > 0 ||
+		(schema.Items != nil && needsSample(schema.Items))
+}
+
+// sampleValue 生成满足Schema约束的JSON值，对象只填写必填字段
+func sampleValue(doc *openapi.Document, schema *openapi.Schema, depth int) string {
+	schema = resolveSchema(doc, schema)
+	if len(schema.Enum) > 0 {
+		if schema.Type == "string" || schema.Type == "" {
+			return strconv.Quote(schema.Enum[0])
+		}
+		return schema.Enum[0]
+	}
+	
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return `"2024-01-01T00:00:00Z"`
+		case "date":
+			return `"2024-01-01"`
+		case "email":
+			return `"test@example.com"`
+		case "uri", "url":
+			return `"https://example.com"`
+		case "uuid":
+			return `"123e4567-e89b-12d3-a456-426614174000"`
+		}
+		n := 4
+		if schema.MinLength != nil && *schema.MinLength > n {
+			n = *schema.MinLength
+		}
+		if schema.MaxLength != nil && *schema.MaxLength < n {
+			n = *schema.MaxLength
+		}
+		return strconv.Quote(strings.Repeat("a", n))
+	case "integer", "number":
+		value := 1.0
+		if schema.Minimum != nil && *schema.Minimum > value {
+			value = *schema.Minimum
+		}
+		if schema.Maximum != nil && *schema.Maximum < value {
+			value = *schema.Maximum
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case "boolean":
+		return "true"
+	case "array":
+		n := 0
+		if schema.MinItems != nil {
+			n = *schema.MinItems
+		}
+		items := make([]string, n)
+		for i := range items {
+			items[i] = sampleValue(doc, schema.Items, depth+1)
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+	
+	if depth > 4 || len(schema.Properties) == 0 {
+		return "{}"
+	}
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	var pairs []string
+	for _, prop := range schema.Properties {
+		if required[prop.Name] && !prop.Schema.ReadOnly {
+			pairs = append(pairs, strconv.Quote(prop.Name)+":"+sampleValue(doc, prop.Schema, depth+1))
+		}
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// goName 将JSON属性名、路径段或标签转换为Go标识符，例如 customer_id -> CustomerID
+func goName(s string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	
+	var name strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			name.WriteString(strings.ToUpper(w))
+		} else {
+			name.WriteString(CapitalizeFirst(w))
+		}
+	}
+	result := name.String()
+	if result != "" && unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// firstLine 返回说明文字的第一行
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,405 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/openapi"
+)
+
+// 测试用OpenAPI文档
+const testOpenAPIDocument = `openapi: 3.0.3
+info:
+  title: Shop
+  version: 1.0.0
+paths:
+  /api/v1/orders:
+    get:
+      tags: [orders]
+      responses:
+        "200": {description: ok}
+    post:
+      tags: [orders]
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Order"}
+      responses:
+        "201": {description: created}
+  /api/v1/orders/{orderId}:
+    get:
+      tags: [orders]
+      responses: {"200": {description: ok}}
+    patch:
+      tags: [orders]
+      responses: {"200": {description: ok}}
+  /api/v1/orders/{orderId}/cancel:
+    post:
+      tags: [orders]
+      operationId: cancelOrder
+      summary: 取消订单
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/CancelRequest"}
+      responses: {"200": {description: ok}}
+  /api/v1/login:
+    post:
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Order:
+      type: object
+      required: [customer_email, status, gift, address]
+      properties:
+        id: {type: integer, readOnly: true}
+        customer_email: {type: string, format: email, description: 客户邮箱}
+        status: {type: string, enum: [new, paid]}
+        title: {type: string, minLength: 3, maxLength: 120}
+        quantity: {type: integer, format: int32, minimum: 1}
+        gift: {type: boolean}
+        items: {type: array, items: {$ref: "#/components/schemas/OrderItem"}}
+        address: {$ref: "#/components/schemas/Address"}
+        placed_at: {type: string, format: date-time, readOnly: true}
+        created_at: {type: string, format: date-time}
+    OrderItem:
+      type: object
+      properties:
+        sku: {type: string}
+    Address:
+      type: object
+      description: 收货地址
+      required: [street]
+      properties:
+        street: {type: string}
+    CancelRequest:
+      type: object
+      properties:
+        reason: {type: string}
+    Status:
+      type: string
+      enum: [a, b]
+`
+
+// writeOpenAPIDocument 在目录中写入测试用OpenAPI文档
+func writeOpenAPIDocument(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "api.yaml")
+	err := os.WriteFile(path, []byte(testOpenAPIDocument), 0644)
+	require.NoError(t, err, "无法写入OpenAPI文档")
+	return path
+}
+
+// 测试解析OpenAPI文档的生成计划
+func TestPlanOpenAPI(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	doc, err := openapi.Load(writeOpenAPIDocument(t, tempDir))
+	require.NoError(t, err, "读取OpenAPI文档失败")
+	
+	plan, err := planOpenAPI(doc)
+	require.NoError(t, err, "解析生成计划失败")
+	
+	assert.Equal(t, []string{"Login", "Order"}, plan.Resources, "资源划分不正确")
+	assert.Equal(t, []string{"Address", "CancelRequest", "OrderItem"}, plan.DTOs, "数据结构不正确")
+	
+	// 符合REST约定的资源
+	order := plan.Specs["Order"]
+	assert.True(t, order.CRUD, "订单应生成增删改查接口")
+	require.NotNil(t, order.Operations, "应记录文档声明的接口")
+	assert.Equal(t, Operations{List: true, Get: true, Create: true, Patch: true}, *order.Operations, "只生成文档声明的接口")
+	assert.Empty(t, order.CreateRequest, "请求体与模型相同时使用默认请求结构")
+	assert.Equal(t, "/api/v1/orders", order.BasePath, "路由组路径不正确")
+	require.Len(t, order.Handlers, 1, "自定义接口数量不正确")
+	assert.Equal(t, Handler{Name: "CancelOrder", Method: "POST", Path: "/:id/cancel", Summary: "取消订单", Request: "CancelRequest"}, order.Handlers[0])
+	
+	// 字段类型、标签和测试值
+	fields := map[string]Field{}
+	for _, field := range order.Fields {
+		fields[field.Name] = field
+	}
+	assert.NotContains(t, fields, "ID", "模板固定的字段应跳过")
+	assert.NotContains(t, fields, "CreatedAt", "模板固定的字段应跳过")
+	assert.Equal(t, Field{Name: "CustomerEmail", Type: "string", JSONName: "customer_email", Binding: "required,email", Comment: "客户邮箱", Gorm: "not null", Sample: `"test@example.com"`}, fields["CustomerEmail"])
+	assert.Equal(t, "required,oneof=new paid", fields["Status"].Binding)
+	assert.Equal(t, `"new"`, fields["Status"].Sample)
+	assert.Equal(t, "omitempty,min=3,max=120", fields["Title"].Binding, "非必填字段的约束应加上omitempty")
+	assert.Equal(t, "size:120", fields["Title"].Gorm, "非必填字段允许为空")
+	assert.Equal(t, "int32", fields["Quantity"].Type)
+	assert.Equal(t, "omitempty,gte=1", fields["Quantity"].Binding)
+	assert.Empty(t, fields["Gift"].Binding, "bool字段不能使用required")
+	assert.Equal(t, "[]OrderItem", fields["Items"].Type)
+	assert.Equal(t, "serializer:json", fields["Items"].Gorm)
+	assert.Equal(t, "Address", fields["Address"].Type)
+	assert.Equal(t, `{"street":"aaaa"}`, fields["Address"].Sample, "嵌套对象应填写必填字段")
+	assert.Equal(t, "time.Time", fields["PlacedAt"].Type)
+	assert.True(t, fields["PlacedAt"].ReadOnly)
+	
+	// 不符合REST约定的资源只生成自定义接口
+	login := plan.Specs["Login"]
+	assert.False(t, login.CRUD, "/login上的POST不应视为创建")
+	assert.Equal(t, "/api/v1/login", login.BasePath)
+	assert.Equal(t, []Handler{{Name: "PostLogin", Method: "POST", Path: ""}}, []Handler(login.Handlers))
+	
+	// 数据结构
+	assert.Equal(t, "收货地址", plan.Specs["Address"].Comment)
+	assert.Equal(t, "required", plan.Specs["Address"].Fields[0].Binding)
+}
+
+// 测试绑定文档声明的请求体
+func TestPlanOpenAPIRequestBody(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	path := filepath.Join(tempDir, "pets.yaml")
+	document := `openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses: {"201": {description: created}}
+  /pets/{petId}:
+    get:
+      responses: {"200": {description: ok}}
+    delete:
+      responses: {"204": {description: deleted}}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        tag: {type: string, nullable: true}
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string}
+`
+	require.NoError(t, os.WriteFile(path, []byte(document), 0644), "无法写入OpenAPI文档")
+	
+	doc, err := openapi.Load(path)
+	require.NoError(t, err, "读取OpenAPI文档失败")
+	plan, err := planOpenAPI(doc)
+	require.NoError(t, err, "解析生成计划失败")
+	
+	pet := plan.Specs["Pet"]
+	assert.Equal(t, Operations{Get: true, Create: true, Delete: true}, *pet.Operations, "未声明的列表和更新接口不应生成")
+	assert.Equal(t, "NewPet", pet.CreateRequest, "创建接口应绑定声明的请求体")
+	assert.Empty(t, pet.UpdateRequest)
+	assert.Equal(t, []string{"NewPet"}, plan.DTOs)
+	
+	fields := map[string]Field{}
+	for _, field := range pet.Fields {
+		fields[field.Name] = field
+	}
+	assert.Equal(t, "not null", fields["Name"].Gorm, "必填字段应映射为NOT NULL")
+	assert.Empty(t, fields["Tag"].Gorm, "可为空的字段不应映射为NOT NULL")
+}
+
+// 测试合并allOf，oneOf和anyOf给出警告
+func TestPlanOpenAPIAllOf(t *testing.T) {
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	path := filepath.Join(tempDir, "petstore.yaml")
+	document := `openapi: 3.0.3
+info: {title: Petstore, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses: {"200": {description: ok}}
+  /pets/{id}:
+    get:
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Pet:
+      allOf:
+        - $ref: "#/components/schemas/NewPet"
+        - type: object
+          required: [id, born]
+          properties:
+            id: {type: integer, format: int64}
+            born: {type: string, format: date-time}
+    NewPet:
+      allOf:
+        - $ref: "#/components/schemas/Named"
+        - type: object
+          properties:
+            tag: {type: string}
+            owner:
+              description: 主人
+              allOf: [{$ref: "#/components/schemas/Owner"}]
+            extra:
+              anyOf: [{type: string}, {type: integer}]
+    Named:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+    Owner:
+      type: object
+      properties:
+        nick: {type: string}
+    Shape:
+      oneOf:
+        - {$ref: "#/components/schemas/Owner"}
+        - {$ref: "#/components/schemas/Named"}
+`
+	require.NoError(t, os.WriteFile(path, []byte(document), 0644), "无法写入OpenAPI文档")
+	
+	doc, err := openapi.Load(path)
+	require.NoError(t, err, "读取OpenAPI文档失败")
+	plan, err := planOpenAPI(doc)
+	require.NoError(t, err, "解析生成计划失败")
+	
+	// 经$ref递归合并成员的属性和必填字段
+	var names []string
+	fields := map[string]Field{}
+	for _, field := range plan.Specs["Pet"].Fields {
+		names = append(names, field.Name)
+		fields[field.Name] = field
+	}
+	assert.Equal(t, []string{"Name", "Tag", "Owner", "Extra", "Born"}, names, "应合并allOf各成员的属性")
+	assert.Equal(t, "required", fields["Name"].Binding, "应合并被引用组件的必填字段")
+	assert.Equal(t, "required", fields["Born"].Binding)
+	assert.Equal(t, "Owner", fields["Owner"].Type, "只有一个$ref的allOf视为引用")
+	assert.Equal(t, "interface{}", fields["Extra"].Type)
+	assert.Equal(t, []string{"Named", "NewPet", "Owner"}, plan.DTOs, "合并后的对象类型应生成数据结构")
+	
+	assert.Equal(t, []string{
+		"组件 NewPet 的属性 extra 使用了anyOf，不支持多态结构，需要手动定义对应的类型",
+		"组件 Shape 使用了oneOf，不支持多态结构，需要手动定义对应的类型",
+	}, plan.Warnings)
+}
+
+// 测试根据OpenAPI文档生成功能
+func TestGenerateFromOpenAPI(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"model/model.go.tmpl":           "{{range .Fields}}{{.Name}} {{.Type}}\n{{end}}",
+		"model/dto.go.tmpl":             "dto {{.Name}}\n",
+		"service/service.go.tmpl":       "service {{.Name}}\n",
+		"controller/controller.go.tmpl": "{{.CRUD}}{{range .Handlers}} {{.Name}}{{end}}\n",
+		"route/route.go.tmpl":           "{{.BasePath}} {{.Ops.Patch}}\n",
+		"test/test.go.tmpl":             "{{.Fields.Payload \"Test\"}}\n",
+		"example/example.go.tmpl":       "example {{.Name}}\n",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "component", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试生成
+	err = g.GenerateFromOpenAPI(writeOpenAPIDocument(t, tempDir), "myapp")
+	require.NoError(t, err, "根据OpenAPI文档生成失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "models", "order.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.Contains(t, string(content), "CustomerEmail string\n", "模型字段不符合预期")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "models", "address.go"))
+	require.NoError(t, err, "无法读取生成的数据结构文件")
+	assert.Equal(t, "dto Address\n", string(content))
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "controllers", "order_controller.go"))
+	require.NoError(t, err, "无法读取生成的控制器文件")
+	assert.Equal(t, "true CancelOrder\n", string(content), "生成的控制器内容不符合预期")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "routes", "order_routes.go"))
+	require.NoError(t, err, "无法读取生成的路由文件")
+	assert.Equal(t, "/api/v1/orders true\n", string(content), "生成的路由内容不符合预期")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "tests", "order_test.go"))
+	require.NoError(t, err, "无法读取生成的测试文件")
+	assert.Contains(t, string(content), `"customer_email":"test@example.com"`, "测试请求体不符合预期")
+	assert.NotContains(t, string(content), `"placed_at"`, "只读字段不应出现在请求体中")
+	
+	// 只有自定义接口的资源不生成模型、服务和测试
+	content, err = os.ReadFile(filepath.Join(tempDir, "controllers", "login_controller.go"))
+	require.NoError(t, err, "无法读取生成的控制器文件")
+	assert.Equal(t, "false PostLogin\n", string(content))
+	for _, file := range []string{"models/login.go", "services/login_service.go", "tests/login_test.go"} {
+		_, err = os.Stat(filepath.Join(tempDir, file))
+		assert.True(t, os.IsNotExist(err), "不应生成文件: %s", file)
+	}
+	
+	// 测试文件已存在的情况
+	err = g.GenerateFromOpenAPI(filepath.Join(tempDir, "api.yaml"), "myapp")
+	assert.Error(t, err, "期望在文件已存在时返回错误，但没有")
+	
+	// 测试文档不存在的情况
+	err = g.GenerateFromOpenAPI(filepath.Join(tempDir, "missing.yaml"), "myapp")
+	assert.Error(t, err, "期望在文档不存在时返回错误，但没有")
+}
+
+// 测试生成Go标识符
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"customer_id": "CustomerID",
+		"createdAt":   "CreatedAt",
+		"order-items": "OrderItems",
+		"api_url":     "APIURL",
+		"2fa":         "X2fa",
+		"cancelOrder": "CancelOrder",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, goName(input), "goName(%q)", input)
+	}
+}
+
+// 测试生成测试请求体
+func TestFieldsPayload(t *testing.T) {
+	var fields Fields
+	assert.Equal(t, `{"name":"TestOrder"}`, fields.Payload("TestOrder"), "未定义字段时应与默认模型一致")
+	assert.True(t, fields.Echoes())
+	
+	fields = Fields{
+		{Name: "Title", Type: "string", JSONName: "title"},
+		{Name: "Count", Type: "int", JSONName: "count"},
+		{Name: "Tags", Type: "[]string", JSONName: "tags"},
+		{Name: "Email", Type: "string", JSONName: "email", Sample: `"a@b.c"`},
+		{Name: "Code", Type: "string", JSONName: "code", ReadOnly: true},
+	}
+	assert.Equal(t, `{"title":"First","count":1,"tags":[],"email":"a@b.c"}`, fields.Payload("First"))
+	assert.True(t, fields.Echoes())
+	assert.False(t, fields[1:].Echoes(), "没有无约束的字符串字段时不会回显")
+}
@@ -4,18 +4,81 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/yggai/gs/pkg/schema"
 )
 
+// ProjectFeatures 初始化项目时可以通过--with启用的可选模块及说明
+var ProjectFeatures = map[string]string{
+	"docker":  "Docker: 多阶段构建的Dockerfile、docker-compose.yml、.dockerignore和Makefile",
+	"metrics": "Prometheus指标: /metrics端点和请求计数、耗时、并发中间件",
+	"tracing": "OpenTelemetry链路追踪: OTLP或stdout导出器、traceparent传播中间件，服务和仓储方法创建子span",
+}
+
+// ProjectFeatureNames 返回排序后的可选模块列表
+func ProjectFeatureNames() []string {
+	names := make([]string, 0, len(ProjectFeatures))
+	for name := range ProjectFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LogLevels 生成的项目支持的日志级别
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// LogFormats 生成的项目支持的日志格式
+var LogFormats = []string{"text", "json"}
+
+// ProjectOptions 初始化项目的选项
+type ProjectOptions struct {
+	Database     string   // 数据库驱动: sqlite、mysql或postgres，为空时不生成database包
+	ConfigReload bool     // 生成配置文件热加载
+	With         []string // 启用的可选模块，见ProjectFeatures
+	LogLevel     string   // 默认日志级别，见LogLevels，为空时为info
+	LogFormat    string   // 默认日志格式，见LogFormats，为空时为text
+	Auth         string   // 认证策略，需要数据库，认证模块、路由和配置由InitFromSpec随后生成
+	Examples     []string // 示例资源，需要数据库，模板据此注册其路由，资源由InitFromSpec随后生成
+}
+
+// Enabled 判断是否启用了可选模块，模板中用 {{if .Enabled "metrics"}} 判断
+func (o ProjectOptions) Enabled(feature string) bool {
+	for _, name := range o.With {
+		if name == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// includes 判断项目模板中的文件或目录是否需要生成，path为相对project模板目录的路径，
+// 只在启用相应选项时生成的模板返回false
+func (o ProjectOptions) includes(path string) bool {
+	switch filepath.ToSlash(path) {
+	case "database":
+		return o.Database != ""
+	case "config/watcher.go.tmpl", "config/watcher_test.go.tmpl":
+		return o.ConfigReload
+	case "metrics", "tracing":
+		return o.Enabled(path)
+	}
+	return true
+}
+
 // ProjectData 项目模板数据
 type ProjectData struct {
-	Name    string // 项目名称
-	Module  string // Go模块名称
-	Version string // 版本号
+	ProjectOptions
+	Name         string // 项目名称
+	Module       string // Go模块名称
+	Version      string // 版本号
+	DatabaseName string // MySQL和PostgreSQL的数据库名称，由项目名称转换而来
 }
 
-// InitProject 初始化项目
-func (g *Generator) InitProject(name string, moduleName string) error {
+// InitProject 初始化项目，options为零值时不使用数据库和可选模块
+func (g *Generator) InitProject(name string, moduleName string, options ProjectOptions) error {
 	// 验证项目名称
 	if name == "" {
 		return fmt.Errorf("项目名称不能为空")
@@ -26,11 +89,65 @@ func (g *Generator) InitProject(name string, moduleName string) error {
 		moduleName = name
 	}
 	
+	// 数据库驱动，none表示不使用数据库
+	switch strings.ToLower(options.Database) {
+	case "", "none":
+		options.Database = ""
+	default:
+		dialect, err := schema.ParseDialect(options.Database)
+		if err != nil {
+			return fmt.Errorf("不支持的数据库: %s(可选 sqlite、mysql、postgres、none)", options.Database)
+		}
+		options.Database = string(dialect)
+	}
+	
+	// 可选模块，忽略大小写和重复
+	var features []string
+	for _, feature := range options.With {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		if feature == "" {
+			continue
+		}
+		if _, ok := ProjectFeatures[feature]; !ok {
+			return fmt.Errorf("不支持的模块 '%s'，可用模块: %s", feature, strings.Join(ProjectFeatureNames(), ", "))
+		}
+		if !(ProjectOptions{With: features}).Enabled(feature) {
+			features = append(features, feature)
+		}
+	}
+	options.With = features
+	
+	// 日志级别和格式，写入config.yaml和默认配置
+	level, err := oneOf("日志级别", options.LogLevel, "info", LogLevels)
+	if err != nil {
+		return err
+	}
+	format, err := oneOf("日志格式", options.LogFormat, "text", LogFormats)
+	if err != nil {
+		return err
+	}
+	options.LogLevel, options.LogFormat = level, format
+	
+	// 认证模块和示例资源的仓储基于GORM
+	if options.Database == "" && (options.Auth != "" || len(options.Examples) > 0) {
+		return fmt.Errorf("认证模块和示例资源需要数据库")
+	}
+	if _, ok := AuthStrategies[options.Auth]; options.Auth != "" && !ok {
+		return fmt.Errorf("不支持的认证策略 '%s'，可用策略: %s", options.Auth, strings.Join(AuthStrategyNames(), ", "))
+	}
+	var examples []string
+	for _, example := range options.Examples {
+		examples = append(examples, formatName(example))
+	}
+	options.Examples = examples
+	
 	// 准备模板数据
 	data := ProjectData{
-		Name:    name,
-		Module:  moduleName,
-		Version: "v0.1.0",
+		ProjectOptions: options,
+		Name:           name,
+		Module:         moduleName,
+		Version:        "v0.1.0",
+		DatabaseName:   strings.ReplaceAll(strings.ToLower(name), "-", "_"),
 	}
 	
 	// 项目目录路径
@@ -50,11 +167,37 @@ func (g *Generator) InitProject(name string, moduleName string) error {
 	templatesDir := filepath.Join(g.TemplatesDir, "project")
 	
 	// 递归遍历模板目录并生成项目文件
-	return g.generateProjectFiles(templatesDir, projectDir, data)
+	if err := g.generateProjectFiles(templatesDir, "", projectDir, data); err != nil {
+		return err
+	}
+	
+	// Docker和Makefile按生成的项目文件生成
+	if options.Enabled("docker") {
+		if err := g.GenerateDocker(projectDir, false); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("项目 %s 初始化成功！\n", data.Name)
+	return nil
+}
+
+// oneOf 忽略大小写校验value是否为options之一，为空时返回默认值def
+func oneOf(name, value, def string, options []string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return def, nil
+	}
+	for _, option := range options {
+		if value == option {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("不支持的%s '%s'(可选 %s)", name, value, strings.Join(options, "、"))
 }
 
-// generateProjectFiles 递归生成项目文件
-func (g *Generator) generateProjectFiles(templatesDir, outputDir string, data ProjectData) error {
+// generateProjectFiles 递归生成项目文件，relDir为templatesDir相对project模板目录的路径
+func (g *Generator) generateProjectFiles(templatesDir, relDir, outputDir string, data ProjectData) error {
 	// 获取模板目录中的所有文件和子目录
 	entries, err := os.ReadDir(templatesDir)
 	if err != nil {
@@ -64,6 +207,12 @@ func (g *Generator) generateProjectFiles(templatesDir, outputDir string, data Pr
 	// 遍历每个条目
 	for _, entry := range entries {
 		templatePath := filepath.Join(templatesDir, entry.Name())
+		relPath := filepath.Join(relDir, entry.Name())
+		
+		// 跳过未启用的可选模板
+		if !data.includes(relPath) {
+			continue
+		}
 		
 		// 计算输出路径，移除.tmpl后缀
 		outputName := strings.TrimSuffix(entry.Name(), ".tmpl")
@@ -75,7 +224,7 @@ func (g *Generator) generateProjectFiles(templatesDir, outputDir string, data Pr
 				return fmt.Errorf("无法创建目录: %v", err)
 			}
 			
-			if err := g.generateProjectFiles(templatePath, outputPath, data); err != nil {
+			if err := g.generateProjectFiles(templatePath, relPath, outputPath, data); err != nil {
 				return err
 			}
 		} else {
@@ -101,6 +250,5 @@ func (g *Generator) generateProjectFiles(templatesDir, outputDir string, data Pr
 		}
 	}
 	
-	fmt.Printf("项目 %s 初始化成功！\n", data.Name)
 	return nil
 } 
\ No newline at end of file
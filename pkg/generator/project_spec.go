@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/yggai/gs/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectSpec gs init的项目规格，命令行标志和交互式向导的回答都转换为ProjectSpec，
+// 保存为规格文件后可以用 gs init --spec 复用
+type ProjectSpec struct {
+	Name         string   `yaml:"name"`                    // 项目名称，也是项目目录
+	Module       string   `yaml:"module,omitempty"`        // Go模块路径，为空时与项目名称相同
+	Database     string   `yaml:"database"`                // 数据库驱动: sqlite、mysql、postgres或none，为空时为sqlite
+	ConfigReload bool     `yaml:"config_reload,omitempty"` // 生成配置文件热加载
+	With         []string `yaml:"with,omitempty"`          // 启用的可选模块，见ProjectFeatures
+	Auth         string   `yaml:"auth,omitempty"`          // 认证策略jwt或apikey，为空时不生成认证模块
+	LogLevel     string   `yaml:"log_level,omitempty"`     // 默认日志级别，见LogLevels
+	LogFormat    string   `yaml:"log_format,omitempty"`    // 默认日志格式，见LogFormats
+	Examples     []string `yaml:"examples,omitempty"`      // 示例资源，按gs create feature生成
+}
+
+// modulePathPattern 匹配Go模块路径，以/分隔的各段由字母、数字和._~-组成
+var modulePathPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]+(/[A-Za-z0-9._~-]+)*$`)
+
+// resourceNamePattern 匹配示例资源名称
+var resourceNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// LoadProjectSpec 读取并校验项目规格文件
+func LoadProjectSpec(path string) (*ProjectSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取项目规格文件: %v", err)
+	}
+	
+	// 拼错的键直接报错，避免静默忽略
+	var spec ProjectSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("无法解析项目规格文件 %s: %v", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("项目规格文件 %s 有误: %v", path, err)
+	}
+	return &spec, nil
+}
+
+// Save 将项目规格保存到path
+func (s *ProjectSpec) Save(path string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# gs init的项目规格，复用: gs init --spec %s，指定项目名称: gs init <名称> --spec %s\n", path, path)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("无法生成项目规格: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("无法写入项目规格文件: %v", err)
+	}
+	return nil
+}
+
+// Validate 校验项目规格并统一取值：补全默认值，名称转为小写，去掉重复的模块和资源。
+// 项目名称的格式由调用方校验
+func (s *ProjectSpec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("项目名称不能为空")
+	}
+	if s.Module == "" {
+		s.Module = s.Name
+	}
+	if err := ValidateModulePath(s.Module); err != nil {
+		return err
+	}
+	
+	// 数据库驱动，none表示不使用数据库
+	switch strings.ToLower(s.Database) {
+	case "":
+		s.Database = "sqlite"
+	case "none":
+		s.Database = "none"
+	default:
+		dialect, err := schema.ParseDialect(s.Database)
+		if err != nil {
+			return fmt.Errorf("不支持的数据库: %s(可选 sqlite、mysql、postgres、none)", s.Database)
+		}
+		s.Database = string(dialect)
+	}
+	
+	// 可选模块，忽略大小写和重复
+	var features []string
+	for _, feature := range s.With {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		if feature == "" {
+			continue
+		}
+		if _, ok := ProjectFeatures[feature]; !ok {
+			return fmt.Errorf("不支持的模块 '%s'，可用模块: %s", feature, strings.Join(ProjectFeatureNames(), ", "))
+		}
+		if !(ProjectOptions{With: features}).Enabled(feature) {
+			features = append(features, feature)
+		}
+	}
+	s.With = features
+	
+	// 认证策略，none表示不生成认证模块
+	s.Auth = strings.ToLower(strings.TrimSpace(s.Auth))
+	if s.Auth == "none" {
+		s.Auth = ""
+	}
+	if _, ok := AuthStrategies[s.Auth]; s.Auth != "" && !ok {
+		return fmt.Errorf("不支持的认证策略 '%s'，可用策略: %s", s.Auth, strings.Join(AuthStrategyNames(), ", "))
+	}
+	
+	var err error
+	if s.LogLevel, err = oneOf("日志级别", s.LogLevel, "info", LogLevels); err != nil {
+		return err
+	}
+	if s.LogFormat, err = oneOf("日志格式", s.LogFormat, "text", LogFormats); err != nil {
+		return err
+	}
+	
+	// 示例资源，名称不区分大小写
+	var examples []string
+	names := map[string]bool{}
+	for _, name := range s.Examples {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !resourceNamePattern.MatchString(name) {
+			return fmt.Errorf("示例资源名称 '%s' 只能包含字母、数字和下划线，并以字母开头", name)
+		}
+		if key := formatName(name); !names[key] {
+			names[key] = true
+			examples = append(examples, name)
+		}
+	}
+	s.Examples = examples
+	
+	// 认证模块和示例资源的仓储基于GORM
+	if s.Database == "none" && (s.Auth != "" || len(s.Examples) > 0) {
+		return fmt.Errorf("认证模块和示例资源需要数据库，database不能为none")
+	}
+	return nil
+}
+
+// ValidateModulePath 校验Go模块路径
+func ValidateModulePath(path string) error {
+	if !modulePathPattern.MatchString(path) {
+		return fmt.Errorf("模块路径 '%s' 无效，例如 github.com/username/myapp", path)
+	}
+	return nil
+}
+
+// Options 返回项目规格对应的InitProject选项
+func (s *ProjectSpec) Options() ProjectOptions {
+	return ProjectOptions{
+		Database:     s.Database,
+		ConfigReload: s.ConfigReload,
+		With:         s.With,
+		LogLevel:     s.LogLevel,
+		LogFormat:    s.LogFormat,
+		Auth:         s.Auth,
+		Examples:     s.Examples,
+	}
+}
+
+// InitFromSpec 按项目规格初始化项目，再在项目目录中生成认证模块和示例资源，
+// 与初始化后在项目中执行 gs create auth 和 gs create feature 相同，另外生成认证模块的建表迁移，
+// 并在RegisterRoutes中注册认证路由和示例资源的路由
+func (g *Generator) InitFromSpec(spec ProjectSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	
+	// Docker和Makefile最后生成，包含示例资源的迁移命令
+	options := spec.Options()
+	docker := options.Enabled("docker")
+	options.With = nil
+	for _, feature := range spec.With {
+		if feature != "docker" {
+			options.With = append(options.With, feature)
+		}
+	}
+	if err := g.InitProject(spec.Name, spec.Module, options); err != nil {
+		return err
+	}
+	
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(spec.Name); err != nil {
+		return fmt.Errorf("无法进入项目目录: %v", err)
+	}
+	defer os.Chdir(wd)
+	
+	// 与gs create auth和gs create feature一致，生成模型时同时生成建表迁移
+	g.Options.Migration = true
+	if spec.Auth != "" {
+		if err := g.GenerateAuth(spec.Module, spec.Auth); err != nil {
+			return err
+		}
+	}
+	for _, name := range spec.Examples {
+		if err := g.GenerateFeature(name, spec.Module); err != nil {
+			return err
+		}
+	}
+	
+	if docker {
+		return g.GenerateDocker(".", false)
+	}
+	return nil
+}
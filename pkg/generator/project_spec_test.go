@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试校验项目规格并补全默认值
+func TestProjectSpecValidate(t *testing.T) {
+	spec := ProjectSpec{
+		Name:     "shop",
+		Database: "PostgreSQL",
+		With:     []string{"Docker", "metrics", " docker"},
+		Auth:     "JWT",
+		Examples: []string{"product", "Product", " order "},
+	}
+	require.NoError(t, spec.Validate())
+	assert.Equal(t, ProjectSpec{
+		Name:      "shop",
+		Module:    "shop",
+		Database:  "postgres",
+		With:      []string{"docker", "metrics"},
+		Auth:      "jwt",
+		LogLevel:  "info",
+		LogFormat: "text",
+		Examples:  []string{"product", "order"},
+	}, spec)
+	
+	tests := []struct {
+		name string
+		spec ProjectSpec
+		err  string
+	}{
+		{"缺少名称", ProjectSpec{}, "项目名称不能为空"},
+		{"模块路径无效", ProjectSpec{Name: "shop", Module: "github.com/a b"}, "模块路径 'github.com/a b' 无效"},
+		{"不支持的数据库", ProjectSpec{Name: "shop", Database: "oracle"}, "不支持的数据库: oracle"},
+		{"不支持的模块", ProjectSpec{Name: "shop", With: []string{"cache"}}, "不支持的模块 'cache'"},
+		{"不支持的认证策略", ProjectSpec{Name: "shop", Auth: "oauth"}, "不支持的认证策略 'oauth'"},
+		{"不支持的日志级别", ProjectSpec{Name: "shop", LogLevel: "trace"}, "不支持的日志级别 'trace'"},
+		{"不支持的日志格式", ProjectSpec{Name: "shop", LogFormat: "xml"}, "不支持的日志格式 'xml'"},
+		{"示例资源名称无效", ProjectSpec{Name: "shop", Examples: []string{"1st"}}, "示例资源名称 '1st'"},
+		{"示例资源需要数据库", ProjectSpec{Name: "shop", Database: "none", Examples: []string{"product"}}, "需要数据库"},
+		{"认证模块需要数据库", ProjectSpec{Name: "shop", Database: "none", Auth: "apikey"}, "需要数据库"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.spec.Validate(), tt.err)
+		})
+	}
+}
+
+// 测试保存和读取项目规格文件
+func TestProjectSpecSaveLoad(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	spec := ProjectSpec{Name: "shop", Module: "example.com/shop", Database: "none", With: []string{"metrics"}, LogFormat: "json"}
+	require.NoError(t, spec.Validate())
+	path := filepath.Join(tempDir, "gs-init.yaml")
+	require.NoError(t, spec.Save(path))
+	
+	loaded, err := LoadProjectSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, spec, *loaded)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "database: none", "不使用数据库时应保存none，避免读取时使用默认的sqlite")
+	
+	// 拼错的键直接报错
+	createTempFile(t, tempDir, "typo.yaml", "name: shop\ndatabse: mysql\n")
+	_, err = LoadProjectSpec(filepath.Join(tempDir, "typo.yaml"))
+	assert.ErrorContains(t, err, "databse")
+	
+	createTempFile(t, tempDir, "invalid.yaml", "name: shop\nlog_level: verbose\n")
+	_, err = LoadProjectSpec(filepath.Join(tempDir, "invalid.yaml"))
+	assert.ErrorContains(t, err, "不支持的日志级别")
+}
+
+// testAPIKeyModel 认证模块生成的模型，用于检查建表迁移
+const testAPIKeyModel = `package models
+
+type APIKey struct {
+	ID      uint   ` + "`gorm:\"primaryKey\"`" + `
+	KeyHash string ` + "`gorm:\"size:64;not null\"`" + `
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+`
+
+// testRoutesTemplate 注册示例资源路由的routes.go模板
+const testRoutesTemplate = `package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterRoutes(router *gin.Engine, db *gorm.DB) {
+{{- range .Examples}}
+	Register{{.}}Routes(router)
+{{- end}}
+}
+`
+
+// 测试按项目规格初始化项目
+func TestInitFromSpec(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"project/go.mod.tmpl":                          "module {{.Module}}\n\ngo 1.22\n",
+		"project/config.yaml.tmpl":                     "log:\n  level: {{.LogLevel}}\n  format: {{.LogFormat}}\ndatabase:\n  driver: {{.Database}}\n",
+		"project/routes/routes.go.tmpl":                testRoutesTemplate,
+		"component/auth/apikey/auth/api_key.go.tmpl":   "package auth // {{.Package}}",
+		"component/auth/apikey/models/api_key.go.tmpl": testAPIKeyModel,
+		"component/repository/base.go.tmpl":            "package repositories",
+		"component/repository/repository.go.tmpl":      "repository {{.Name}}\n",
+		"component/model/model.go.tmpl":                "{{.Name}}\n",
+		"component/service/service.go.tmpl":            "service {{.Name}}\n",
+		"component/controller/controller.go.tmpl":      "controller {{.Name}}\n",
+		"component/controller/helpers.go.tmpl":         "helpers\n",
+		"component/route/route.go.tmpl":                "route {{.Name}}\n",
+		"component/test/test.go.tmpl":                  "test {{.Name}}\n",
+		"component/example/example.go.tmpl":            "example {{.Name}}\n",
+		"component/migration/up.sql.tmpl":              "{{range .Up}}{{.}};\n{{end}}",
+		"component/migration/down.sql.tmpl":            "{{range .Down}}{{.}};\n{{end}}",
+		"component/migration/migrations.go.tmpl":       "package migrations",
+		"component/migration/main.go.tmpl":             "package main",
+	}
+	for _, file := range dockerFiles {
+		templates["component/docker/"+file.template] = file.output + " {{.Database}}"
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	spec := ProjectSpec{Name: "shop", Module: "example.com/shop", Database: "mysql", With: []string{"docker"}, Auth: "apikey", LogLevel: "debug", LogFormat: "json", Examples: []string{"product"}}
+	require.NoError(t, g.InitFromSpec(spec))
+	
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, tempDir, wd, "生成后应回到原来的目录")
+	
+	content, err := os.ReadFile(filepath.Join("shop", "config.yaml"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "log:\n  level: debug\n  format: json\ndatabase:\n  driver: mysql\n"))
+	assert.Contains(t, string(content), "\napi_key:\n  header: X-API-Key\n", "应加入认证配置")
+	content, err = os.ReadFile(filepath.Join("shop", "auth", "api_key.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package auth // example.com/shop", string(content), "认证模块应使用规格中的模块路径")
+	content, err = os.ReadFile(filepath.Join("shop", "Makefile"))
+	require.NoError(t, err)
+	assert.Equal(t, "Makefile mysql", string(content))
+	
+	// RegisterRoutes注册认证和示例资源的路由
+	content, err = os.ReadFile(filepath.Join("shop", "routes", "routes.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "func RegisterRoutes(router *gin.Engine, db *gorm.DB, cfg config.Config) {")
+	assert.Contains(t, string(content), "\tRegisterAPIKeyRoutes(router, db, cfg.APIKey)\n")
+	assert.Contains(t, string(content), "\tRegisterProductRoutes(router)\n")
+	
+	// 认证模块的表和示例资源的表都有建表迁移
+	auth, err := filepath.Glob(filepath.Join("shop", MigrationsDir, "*_create_auth_tables.up.sql"))
+	require.NoError(t, err)
+	require.Len(t, auth, 1, "应生成认证模块的建表迁移")
+	content, err = os.ReadFile(auth[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "CREATE TABLE `api_keys`")
+	products, err := filepath.Glob(filepath.Join("shop", MigrationsDir, "*_create_products.up.sql"))
+	require.NoError(t, err)
+	assert.Len(t, products, 1, "应生成示例资源的建表迁移")
+	
+	// 每个示例在各自的目录中
+	content, err = os.ReadFile(filepath.Join("shop", "examples", "product", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "example Product\n", string(content))
+	
+	// 规格无效时不创建项目目录
+	err = g.InitFromSpec(ProjectSpec{Name: "bad", Database: "none", Auth: "jwt"})
+	assert.ErrorContains(t, err, "需要数据库")
+	assert.NoDirExists(t, "bad")
+}
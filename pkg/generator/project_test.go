@@ -57,7 +57,7 @@ func TestInitProject(t *testing.T) {
 	err = os.Chdir(projectDir)
 	require.NoError(t, err, "无法切换到项目目录")
 	
-	err = g.InitProject("my-app", "github.com/username/my-app")
+	err = g.InitProject("my-app", "github.com/username/my-app", ProjectOptions{})
 	require.NoError(t, err, "项目初始化失败")
 	
 	// 验证主要文件是否已生成
@@ -210,7 +210,7 @@ func TestInitProject_Errors(t *testing.T) {
 	g := NewGenerator(filepath.Join(tempDir, "templates"))
 	
 	// 测试模板不存在的情况
-	err := g.InitProject("test-app", "github.com/username/test-app")
+	err := g.InitProject("test-app", "github.com/username/test-app", ProjectOptions{})
 	assert.Error(t, err, "期望在模板不存在时返回错误，但没有")
 	
 	// 创建模板目录但不包含实际模板
@@ -219,6 +219,117 @@ func TestInitProject_Errors(t *testing.T) {
 	require.NoError(t, err, "无法创建项目模板目录")
 	
 	// 再次测试，应该返回特定的错误
-	err = g.InitProject("test-app", "github.com/username/test-app")
+	err = g.InitProject("test-app", "github.com/username/test-app", ProjectOptions{})
 	assert.Error(t, err, "期望在缺少关键模板时返回错误，但没有")
-} 
\ No newline at end of file
+} 
+
+// 测试按数据库选项生成database包
+func TestInitProjectDatabase(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"main.go.tmpl":              "package main // {{.Module}} {{.Database}}",
+		"database/database.go.tmpl": "package database // {{.Database}}",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "project", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 驱动名称应规范化
+	require.NoError(t, g.InitProject("pg-app", "example.com/pg-app", ProjectOptions{Database: "postgresql"}))
+	content, err := os.ReadFile(filepath.Join("pg-app", "main.go"))
+	require.NoError(t, err, "无法读取main.go")
+	assert.Equal(t, "package main // example.com/pg-app postgres", string(content))
+	content, err = os.ReadFile(filepath.Join("pg-app", "database", "database.go"))
+	require.NoError(t, err, "使用数据库时应生成database包")
+	assert.Equal(t, "package database // postgres", string(content))
+	
+	// 不使用数据库时不生成database包
+	require.NoError(t, g.InitProject("plain-app", "", ProjectOptions{Database: "none"}))
+	assert.FileExists(t, filepath.Join("plain-app", "main.go"))
+	assert.NoDirExists(t, filepath.Join("plain-app", "database"), "不使用数据库时不应生成database包")
+	
+	// 不支持的数据库
+	assert.Error(t, g.InitProject("bad-app", "", ProjectOptions{Database: "oracle"}), "不支持的数据库应返回错误")
+}
+
+// 测试通过--with启用可选模块
+func TestInitProjectWith(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templates := map[string]string{
+		"main.go.tmpl":            `package main // {{if .Enabled "metrics"}}metrics{{end}} {{len .With}}`,
+		"metrics/metrics.go.tmpl": "package metrics",
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, "templates", "project", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建模板目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法创建测试模板文件")
+	}
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 模块名称忽略大小写和重复
+	require.NoError(t, g.InitProject("metrics-app", "", ProjectOptions{With: []string{"Metrics", " metrics", ""}}))
+	content, err := os.ReadFile(filepath.Join("metrics-app", "main.go"))
+	require.NoError(t, err, "无法读取main.go")
+	assert.Equal(t, "package main // metrics 1", string(content))
+	assert.FileExists(t, filepath.Join("metrics-app", "metrics", "metrics.go"))
+	
+	// 未启用时不生成模块
+	require.NoError(t, g.InitProject("plain-app", "", ProjectOptions{}))
+	assert.NoDirExists(t, filepath.Join("plain-app", "metrics"), "未启用时不应生成metrics包")
+	
+	// 不支持的模块
+	err = g.InitProject("bad-app", "", ProjectOptions{With: []string{"unknown"}})
+	assert.ErrorContains(t, err, "不支持的模块 'unknown'", "不支持的模块应返回错误")
+	assert.NoDirExists(t, "bad-app", "模块无效时不应创建项目目录")
+}
+
+// 测试按选项跳过可选模板
+func TestProjectOptionsIncludes(t *testing.T) {
+	options := ProjectOptions{}
+	assert.True(t, options.includes("main.go.tmpl"))
+	assert.True(t, options.includes(filepath.Join("config", "config.go.tmpl")))
+	assert.True(t, options.includes("logger"), "日志包应始终生成")
+	assert.True(t, options.includes("health"), "健康检查包应始终生成")
+	assert.False(t, options.includes("database"), "不使用数据库时不应生成database包")
+	assert.False(t, options.includes(filepath.Join("config", "watcher.go.tmpl")), "未启用热加载时不应生成配置监听器")
+	
+	options = ProjectOptions{Database: "sqlite", ConfigReload: true}
+	assert.True(t, options.includes("database"))
+	assert.True(t, options.includes(filepath.Join("config", "watcher.go.tmpl")))
+	assert.True(t, options.includes(filepath.Join("config", "watcher_test.go.tmpl")))
+	assert.False(t, options.includes("metrics"), "未启用时不应生成metrics包")
+	
+	options = ProjectOptions{With: []string{"metrics"}}
+	assert.True(t, options.Enabled("metrics"))
+	assert.True(t, options.includes("metrics"))
+	assert.False(t, options.includes("tracing"), "未启用时不应生成tracing包")
+}
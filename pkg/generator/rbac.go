@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RBACData 权限模板数据
+type RBACData struct {
+	Name         string      // 资源名称，首字母大写
+	Resource     string      // 权限前缀，例如order
+	ResourceName string      // 资源名称，用于URL路径
+	Package      string      // 项目包名
+	BasePath     string      // 路由组路径
+	Fields       Fields      // 模型字段，用于生成请求体
+	Ops          Operations  // 生成的增删改查接口
+	CreateBody   RequestBody // 创建接口绑定的请求结构，用于生成请求体
+	UpdateBody   RequestBody // 更新接口绑定的请求结构，用于生成请求体
+	FeatureOptions
+}
+
+// rbacSharedFiles 所有资源共用的权限文件
+var rbacSharedFiles = []struct {
+	Template string // 模板文件名
+	Output   string // 输出路径
+}{
+	{"rbac.go.tmpl", filepath.Join("rbac", "rbac.go")},
+	{"memory_store.go.tmpl", filepath.Join("rbac", "memory_store.go")},
+	{"db_store.go.tmpl", filepath.Join("rbac", "db_store.go")},
+	{"store_test.go.tmpl", filepath.Join("rbac", "store_test.go")},
+	{"middleware.go.tmpl", filepath.Join("middlewares", "rbac.go")},
+}
+
+// GenerateRBAC 生成资源的权限常量和权限测试，首次生成时同时生成策略存储和RequirePermission中间件
+func (g *Generator) GenerateRBAC(name string, packageName string) error {
+	// 格式化名称
+	name = formatName(name)
+	
+	// 准备模板数据
+	spec := g.resourceSpec(name)
+	data := RBACData{
+		Name:           name,
+		Resource:       strings.ToLower(name),
+		ResourceName:   strings.ToLower(name) + "s",
+		Package:        packageName,
+		BasePath:       spec.basePath(name),
+		Fields:         spec.Fields,
+		Ops:            g.operations(name),
+		CreateBody:     g.requestBody(name, spec.CreateRequest),
+		UpdateBody:     g.requestBody(name, spec.UpdateRequest),
+		FeatureOptions: g.Options,
+	}
+	
+	// 权限文件路径
+	permissionsFile := filepath.Join("rbac", strings.ToLower(name)+"_permissions.go")
+	testFile := filepath.Join("tests", strings.ToLower(name)+"_rbac_test.go")
+	
+	// 权限测试通过增删改查接口校验，只有自定义接口的资源不生成
+	crud := g.resourceSpec(name).CRUD
+	files := []string{permissionsFile}
+	if crud {
+		files = append(files, testFile)
+	}
+	
+	// 检查文件是否已存在
+	for _, file := range files {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			return fmt.Errorf("权限文件已存在: %s", file)
+		}
+	}
+	
+	// 生成公共文件（仅首次）
+	templatesDir := filepath.Join(g.TemplatesDir, "component", "rbac")
+	for _, file := range rbacSharedFiles {
+		if err := g.generateSharedFile(filepath.Join(templatesDir, file.Template), file.Output, data); err != nil {
+			return fmt.Errorf("生成权限公共文件失败: %v", err)
+		}
+	}
+	
+	// 生成权限常量
+	if err := g.GenerateFromTemplate(filepath.Join(templatesDir, "permissions.go.tmpl"), permissionsFile, data); err != nil {
+		return fmt.Errorf("生成权限常量失败: %v", err)
+	}
+	fmt.Printf("已生成权限文件: %s\n", permissionsFile)
+	
+	// 生成权限测试
+	if crud {
+		if err := g.GenerateFromTemplate(filepath.Join(templatesDir, "test.go.tmpl"), testFile, data); err != nil {
+			return fmt.Errorf("生成权限测试失败: %v", err)
+		}
+		fmt.Printf("已生成权限测试文件: %s\n", testFile)
+	}
+	
+	return nil
+}
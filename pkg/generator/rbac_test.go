@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试生成权限
+func TestGenerateRBAC(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建templates目录结构
+	templatesDir := filepath.Join(tempDir, "templates", "component", "rbac")
+	err = os.MkdirAll(templatesDir, 0755)
+	require.NoError(t, err, "无法创建模板目录")
+	
+	// 创建测试模板
+	for _, file := range rbacSharedFiles {
+		err = os.WriteFile(filepath.Join(templatesDir, file.Template), []byte("shared {{.Package}}\n"), 0644)
+		require.NoError(t, err, "无法创建测试模板文件")
+	}
+	err = os.WriteFile(filepath.Join(templatesDir, "permissions.go.tmpl"), []byte("{{.Name}}Read = \"{{.Resource}}:read\"\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = os.WriteFile(filepath.Join(templatesDir, "test.go.tmpl"), []byte("/api/{{.ResourceName}}\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试生成权限
+	err = g.GenerateRBAC("order", "myapp")
+	require.NoError(t, err, "生成权限失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "rbac", "order_permissions.go"))
+	require.NoError(t, err, "无法读取生成的权限文件")
+	assert.Equal(t, "OrderRead = \"order:read\"\n", string(content), "生成的权限内容不符合预期")
+	
+	content, err = os.ReadFile(filepath.Join(tempDir, "tests", "order_rbac_test.go"))
+	require.NoError(t, err, "无法读取生成的权限测试文件")
+	assert.Equal(t, "/api/orders\n", string(content), "生成的权限测试内容不符合预期")
+	
+	for _, file := range rbacSharedFiles {
+		content, err = os.ReadFile(filepath.Join(tempDir, file.Output))
+		require.NoError(t, err, "公共文件未生成: %s", file.Output)
+		assert.Equal(t, "shared myapp\n", string(content), "生成的公共文件内容不符合预期")
+	}
+	
+	// 公共文件已存在时其他资源仍可生成
+	err = g.GenerateRBAC("Item", "myapp")
+	assert.NoError(t, err, "公共文件已存在时生成权限失败")
+	
+	// 测试文件已存在的情况
+	err = g.GenerateRBAC("Order", "myapp")
+	assert.Error(t, err, "期望在权限文件已存在时返回错误，但没有")
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yggai/gs/pkg/utils"
+)
+
+// RepositoryData 仓储模板数据
+type RepositoryData struct {
+	Name    string // 仓储名称，首字母大写
+	VarName string // 变量名称，首字母小写
+	Package string // 项目包名
+	FeatureOptions
+}
+
+// GenerateRepository 生成仓储代码
+func (g *Generator) GenerateRepository(name string, packageName string) error {
+	// 格式化名称
+	name = formatName(name)
+	
+	// 准备模板数据
+	data := RepositoryData{
+		Name:           name,
+		VarName:        strings.ToLower(name[:1]) + name[1:],
+		Package:        packageName,
+		FeatureOptions: g.featureOptions(),
+	}
+	
+	// 确保目录存在
+	outputDir := filepath.Join("repositories")
+	if err := utils.EnsureDir(outputDir); err != nil {
+		return fmt.Errorf("无法创建仓储目录: %v", err)
+	}
+	
+	// 仓储文件路径
+	outputFile := filepath.Join(outputDir, strings.ToLower(name)+"_repository.go")
+	
+	// 检查文件是否已存在
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		return fmt.Errorf("仓储文件已存在: %s", outputFile)
+	}
+	
+	// 生成仓储公共定义（仅首次）
+	baseFile := filepath.Join(outputDir, "repository.go")
+	baseTemplate := filepath.Join(g.TemplatesDir, "component", "repository", "base.go.tmpl")
+	if err := g.generateSharedFile(baseTemplate, baseFile, data); err != nil {
+		return fmt.Errorf("生成仓储公共定义失败: %v", err)
+	}
+	
+	// 生成仓储文件
+	templatePath := filepath.Join(g.TemplatesDir, "component", "repository", "repository.go.tmpl")
+	if err := g.GenerateFromTemplate(templatePath, outputFile, data); err != nil {
+		return fmt.Errorf("生成仓储失败: %v", err)
+	}
+	
+	fmt.Printf("已生成仓储文件: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试仓储数据结构
+func TestRepositoryData(t *testing.T) {
+	data := RepositoryData{
+		Name:           "User",
+		VarName:        "user",
+		Package:        "myapp",
+		FeatureOptions: FeatureOptions{Versioned: true},
+	}
+	
+	assert.Equal(t, "User", data.Name, "Name字段设置不正确")
+	assert.Equal(t, "user", data.VarName, "VarName字段设置不正确")
+	assert.Equal(t, "myapp", data.Package, "Package字段设置不正确")
+	assert.True(t, data.Versioned, "Versioned字段设置不正确")
+}
+
+// 测试生成仓储
+func TestGenerateRepository(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建templates目录结构
+	templatesDir := filepath.Join(tempDir, "templates", "component", "repository")
+	err = os.MkdirAll(templatesDir, 0755)
+	require.NoError(t, err, "无法创建模板目录")
+	
+	// 创建测试模板
+	templateContent := `package repositories
+
+// {{.Name}}Repository 定义{{.Name}}的数据访问接口
+type {{.Name}}Repository interface {
+{{- if .Versioned}}
+	Update(id uint, version int, {{.VarName}} *models.{{.Name}}) error
+{{- else}}
+	Update(id uint, {{.VarName}} *models.{{.Name}}) error
+{{- end}}
+}
+`
+	err = os.WriteFile(filepath.Join(templatesDir, "repository.go.tmpl"), []byte(templateContent), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	err = os.WriteFile(filepath.Join(templatesDir, "base.go.tmpl"), []byte("package repositories\n"), 0644)
+	require.NoError(t, err, "无法创建测试模板文件")
+	
+	// 创建生成器并启用乐观锁
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	g.Options.Versioned = true
+	
+	// 测试生成仓储
+	err = g.GenerateRepository("User", "myapp")
+	require.NoError(t, err, "生成仓储失败")
+	
+	// 验证仓储内容
+	content, err := os.ReadFile(filepath.Join(tempDir, "repositories", "user_repository.go"))
+	require.NoError(t, err, "无法读取生成的仓储文件")
+	
+	expectedContent := `package repositories
+
+// UserRepository 定义User的数据访问接口
+type UserRepository interface {
+	Update(id uint, version int, user *models.User) error
+}
+`
+	assert.Equal(t, expectedContent, string(content), "生成的仓储内容不符合预期")
+	
+	// 验证公共定义已生成
+	basePath := filepath.Join(tempDir, "repositories", "repository.go")
+	_, err = os.Stat(basePath)
+	require.False(t, os.IsNotExist(err), "仓储公共定义未生成")
+	
+	// 修改公共定义后再生成其他仓储，公共定义不应被覆盖
+	err = os.WriteFile(basePath, []byte("custom"), 0644)
+	require.NoError(t, err, "无法修改仓储公共定义")
+	
+	err = g.GenerateRepository("Order", "myapp")
+	require.NoError(t, err, "生成第二个仓储失败")
+	
+	content, err = os.ReadFile(basePath)
+	require.NoError(t, err, "无法读取仓储公共定义")
+	assert.Equal(t, "custom", string(content), "仓储公共定义被覆盖")
+}
+
+// 测试生成仓储 - 错误情况
+func TestGenerateRepository_Errors(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建生成器，但不创建模板目录
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试模板不存在的情况
+	err = g.GenerateRepository("User", "myapp")
+	assert.Error(t, err, "期望在模板不存在时返回错误，但没有")
+	
+	// 创建已存在的仓储文件
+	err = os.MkdirAll(filepath.Join(tempDir, "repositories"), 0755)
+	require.NoError(t, err, "无法创建repositories目录")
+	err = os.WriteFile(filepath.Join(tempDir, "repositories", "user_repository.go"), []byte("already exists"), 0644)
+	require.NoError(t, err, "无法创建已存在的仓储文件")
+	
+	// 测试仓储文件已存在的情况
+	err = g.GenerateRepository("User", "myapp")
+	assert.Error(t, err, "期望在仓储文件已存在时返回错误，但没有")
+}
@@ -0,0 +1,389 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field 模型字段
+type Field struct {
+	Name     string // Go字段名
+	Type     string // Go类型
+	JSONName string // json标签名
+	Gorm     string // gorm标签，为空时不输出
+	Binding  string // binding校验规则，为空时不输出
+	Comment  string // 字段说明
+	ReadOnly bool   // 只读字段不出现在请求结构中
+	Sample   string // 测试请求中使用的JSON值，为空时按类型生成
+}
+
+// ModelTag 返回模型字段的结构体标签
+func (f Field) ModelTag() string {
+	tag := fmt.Sprintf(`json:"%s"`, f.JSONName)
+	if f.Gorm != "" {
+		tag += fmt.Sprintf(` gorm:"%s"`, f.Gorm)
+	}
+	return "`" + tag + "`"
+}
+
+// RequestTag 返回请求结构字段的结构体标签
+func (f Field) RequestTag() string {
+	tag := fmt.Sprintf(`json:"%s"`, f.JSONName)
+	if f.Binding != "" {
+		tag += fmt.Sprintf(` binding:"%s"`, f.Binding)
+	}
+	return "`" + tag + "`"
+}
+
+// RequestType 返回字段在控制器请求结构中的类型，models中的结构体加上包名
+func (f Field) RequestType() string {
+	prefix, base := splitType(f.Type)
+	if base != "" && base[0] >= 'A' && base[0] <= 'Z' {
+		return prefix + "models." + base
+	}
+	return f.Type
+}
+
+// splitType 拆分切片和映射类型的前缀与元素类型，例如 []OrderItem -> [], OrderItem
+func splitType(t string) (string, string) {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(t, "[]"):
+			prefix += "[]"
+			t = t[2:]
+		case strings.HasPrefix(t, "*"):
+			prefix += "*"
+			t = t[1:]
+		case strings.HasPrefix(t, "map[string]"):
+			prefix += "map[string]"
+			t = t[len("map[string]"):]
+		default:
+			return prefix, t
+		}
+	}
+}
+
+// sample 返回字段在测试请求中的JSON值，未指定时按类型生成，字符串使用value
+func (f Field) sample(value string) string {
+	if f.Sample != "" {
+		return f.Sample
+	}
+	switch t := strings.TrimPrefix(f.Type, "*"); {
+	case t == "string":
+		return fmt.Sprintf("%q", value)
+	case t == "bool":
+		return "true"
+	case t == "time.Time":
+		return `"2024-01-01T00:00:00Z"`
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint"):
+		return "1"
+	case strings.HasPrefix(t, "float"):
+		return "1.5"
+	case t == "[]byte":
+		return `"dGVzdA=="`
+	case strings.HasPrefix(t, "[]"):
+		return "[]"
+	}
+	return "{}"
+}
+
+// Fields 模型字段列表
+type Fields []Field
+
+// Requests 返回出现在请求结构中的字段
+func (f Fields) Requests() Fields {
+	var fields Fields
+	for _, field := range f {
+		if !field.ReadOnly {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// NameWidth 返回字段名的对齐宽度，不小于min
+func (f Fields) NameWidth(min int) int {
+	width := min
+	for _, field := range f {
+		if len(field.Name) > width {
+			width = len(field.Name)
+		}
+	}
+	return width
+}
+
+// TypeWidth 返回字段类型的对齐宽度，不小于min
+func (f Fields) TypeWidth(min int) int {
+	width := min
+	for _, field := range f {
+		if len(field.Type) > width {
+			width = len(field.Type)
+		}
+	}
+	return width
+}
+
+// RequestTypeWidth 返回请求结构中字段类型的对齐宽度
+func (f Fields) RequestTypeWidth() int {
+	width := 0
+	for _, field := range f {
+		if len(field.RequestType()) > width {
+			width = len(field.RequestType())
+		}
+	}
+	return width
+}
+
+// UsesModels 判断请求结构中是否引用了models中的结构体
+func (f Fields) UsesModels() bool {
+	for _, field := range f {
+		if field.RequestType() != field.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyWidth 返回复合字面量中"字段名:"的对齐宽度
+func (f Fields) KeyWidth() int {
+	return f.NameWidth(0) + 1
+}
+
+// UsesTime 判断字段中是否使用了time包
+func (f Fields) UsesTime() bool {
+	for _, field := range f {
+		if strings.Contains(field.Type, "time.") {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesGorm 判断字段中是否使用了gorm包，例如软删除的gorm.DeletedAt
+func (f Fields) UsesGorm() bool {
+	for _, field := range f {
+		if strings.Contains(field.Type, "gorm.") {
+			return true
+		}
+	}
+	return false
+}
+
+// Echoes 判断Payload传入的值是否会出现在请求体中，即存在无约束的字符串字段
+func (f Fields) Echoes() bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, field := range f.Requests() {
+		if strings.TrimPrefix(field.Type, "*") == "string" && field.Sample == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload 返回测试用的JSON请求体，未定义字段时与默认模型的name字段一致
+func (f Fields) Payload(value string) string {
+	if len(f) == 0 {
+		return fmt.Sprintf(`{"name":%q}`, value)
+	}
+	var pairs []string
+	for _, field := range f.Requests() {
+		pairs = append(pairs, fmt.Sprintf("%q:%s", field.JSONName, field.sample(value)))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Handler 不符合REST约定的自定义接口
+type Handler struct {
+	Name    string // 处理函数名
+	Method  string // HTTP方法
+	Path    string // 相对路由组的路径，gin格式
+	Summary string // 接口说明
+	Request string // 请求体类型，为空时不绑定请求体
+}
+
+// Reads 判断接口是否为只读操作，RBAC下只读操作需要读权限，其余需要写权限
+func (h Handler) Reads() bool {
+	return h.Method == "GET" || h.Method == "HEAD" || h.Method == "OPTIONS"
+}
+
+// Handlers 自定义接口列表
+type Handlers []Handler
+
+// BindsModels 判断是否有接口以models中的结构体作为请求体
+func (h Handlers) BindsModels() bool {
+	for _, handler := range h {
+		if handler.Request != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads 判断是否有只读的接口
+func (h Handlers) Reads() bool {
+	for _, handler := range h {
+		if handler.Reads() {
+			return true
+		}
+	}
+	return false
+}
+
+// Writes 判断是否有需要写权限的接口
+func (h Handlers) Writes() bool {
+	for _, handler := range h {
+		if !handler.Reads() {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations 资源的增删改查接口
+type Operations struct {
+	List   bool // GET 路由组，获取列表
+	Get    bool // GET /:id，获取单个
+	Create bool // POST 路由组，创建
+	Put    bool // PUT /:id，更新
+	Patch  bool // PATCH /:id，与PUT共用更新处理函数
+	Delete bool // DELETE /:id，删除
+}
+
+// Update 判断是否生成更新处理函数
+func (o Operations) Update() bool {
+	return o.Put || o.Patch
+}
+
+// Reads 判断是否有只读的接口，RBAC下需要读权限
+func (o Operations) Reads() bool {
+	return o.List || o.Get
+}
+
+// Writes 判断是否有需要写权限的接口
+func (o Operations) Writes() bool {
+	return o.Create || o.Update() || o.Delete
+}
+
+// Probe 返回用于测试未认证访问的接口的方法和相对路径
+func (o Operations) Probe() (string, string) {
+	switch {
+	case o.List:
+		return "GET", ""
+	case o.Get:
+		return "GET", "/1"
+	case o.Create:
+		return "POST", ""
+	case o.Put:
+		return "PUT", "/1"
+	case o.Patch:
+		return "PATCH", "/1"
+	}
+	return "DELETE", "/1"
+}
+
+// ProbeMethod 返回Probe接口的方法
+func (o Operations) ProbeMethod() string {
+	method, _ := o.Probe()
+	return method
+}
+
+// ProbePath 返回Probe接口的相对路径
+func (o Operations) ProbePath() string {
+	_, path := o.Probe()
+	return path
+}
+
+// RequestBody 创建和更新接口绑定的请求结构
+type RequestBody struct {
+	Type   string // 请求结构类型，例如models.NewOrder，为空时使用由模型字段生成的请求结构
+	Fields Fields // 请求结构的字段，用于生成测试请求体
+}
+
+// ModelFields 返回从请求结构复制到模型的字段：使用由模型字段生成的请求结构时为全部请求字段，
+// 否则为与模型字段同名且类型相同的字段
+func (b RequestBody) ModelFields(model Fields) Fields {
+	if b.Type == "" {
+		return model.Requests()
+	}
+	types := map[string]string{}
+	for _, field := range b.Fields {
+		types[field.Name] = field.Type
+	}
+	var fields Fields
+	for _, field := range model.Requests() {
+		if types[field.Name] == field.Type {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ResourceSpec 来自外部定义(如OpenAPI文档)的资源结构，用于替换默认的模型字段和路由
+type ResourceSpec struct {
+	Fields        Fields      // 模型字段，不含ID、Version和时间戳
+	BasePath      string      // 路由组路径，为空时使用/api/<资源名>
+	Operations    *Operations // 生成的增删改查接口，为空时生成除PATCH以外的全部接口
+	CreateRequest string      // 创建接口绑定的models中的结构体，为空时使用由模型字段生成的请求结构
+	UpdateRequest string      // 更新接口绑定的models中的结构体，为空时使用由模型字段生成的请求结构
+	Handlers      Handlers    // 自定义接口
+	CRUD          bool        // 是否生成增删改查接口，为false时只生成自定义接口的控制器和路由
+	Comment       string      // 资源说明
+	Table         string      // 表名，为空时使用<小写名称>s
+	Complete      bool        // Fields已包含主键和时间戳等全部列，模型不再添加ID、Version和时间戳
+	Existing      bool        // 表已存在于数据库或SQL文件中，生成模型时不生成建表迁移
+}
+
+// resourceSpec 返回资源的定义，未定义时返回默认值
+func (g *Generator) resourceSpec(name string) *ResourceSpec {
+	if spec, ok := g.Specs[name]; ok {
+		return spec
+	}
+	return &ResourceSpec{CRUD: true}
+}
+
+// operations 返回资源生成的增删改查接口，乐观锁模式下有更新接口时总是注册PATCH
+func (g *Generator) operations(name string) Operations {
+	spec := g.resourceSpec(name)
+	if !spec.CRUD {
+		return Operations{}
+	}
+	ops := Operations{List: true, Get: true, Create: true, Put: true, Delete: true}
+	if spec.Operations != nil {
+		ops = *spec.Operations
+	}
+	if g.Options.Versioned && ops.Update() {
+		ops.Patch = true
+	}
+	return ops
+}
+
+// requestBody 返回创建或更新接口绑定的请求结构，structName为空时使用由模型字段生成的请求结构
+func (g *Generator) requestBody(name string, structName string) RequestBody {
+	if structName == "" {
+		return RequestBody{Fields: g.resourceSpec(name).Fields}
+	}
+	body := RequestBody{Type: "models." + structName}
+	if spec, ok := g.Specs[structName]; ok {
+		body.Fields = spec.Fields
+	}
+	return body
+}
+
+// tableName 返回资源的表名
+func (s *ResourceSpec) tableName(name string) string {
+	if s.Table != "" {
+		return s.Table
+	}
+	return strings.ToLower(name) + "s"
+}
+
+// basePath 返回资源的路由组路径
+func (s *ResourceSpec) basePath(name string) string {
+	if s.BasePath != "" {
+		return s.BasePath
+	}
+	return "/api/" + strings.ToLower(name) + "s"
+}
@@ -11,10 +11,15 @@ import (
 
 // RouteData 路由模板数据
 type RouteData struct {
-	Name         string // 路由名称，首字母大写
-	PluralName   string // 复数名称，用于列表方法
-	ResourceName string // 资源名称，用于URL路径
-	Package      string // 项目包名
+	Name         string     // 路由名称，首字母大写
+	PluralName   string     // 复数名称，用于列表方法
+	ResourceName string     // 资源名称，用于URL路径
+	Package      string     // 项目包名
+	BasePath     string     // 路由组路径
+	Ops          Operations // 生成的增删改查接口
+	Handlers     Handlers   // 自定义接口
+	CRUD         bool       // 是否生成增删改查接口
+	FeatureOptions
 }
 
 // GenerateRoute 生成路由代码
@@ -23,11 +28,17 @@ func (g *Generator) GenerateRoute(name string, packageName string) error {
 	name = formatName(name)
 	
 	// 准备模板数据
+	spec := g.resourceSpec(name)
 	data := RouteData{
-		Name:         name,
-		PluralName:   PluralForm(name),
-		ResourceName: strings.ToLower(name) + "s",
-		Package:      packageName,
+		Name:           name,
+		PluralName:     PluralForm(name),
+		ResourceName:   strings.ToLower(name) + "s",
+		Package:        packageName,
+		BasePath:       spec.basePath(name),
+		Ops:            g.operations(name),
+		Handlers:       spec.Handlers,
+		CRUD:           spec.CRUD,
+		FeatureOptions: g.featureOptions(),
 	}
 	
 	// 确保目录存在
@@ -52,4 +63,4 @@ func (g *Generator) GenerateRoute(name string, packageName string) error {
 	
 	fmt.Printf("已生成路由文件: %s\n", outputFile)
 	return nil
-} 
\ No newline at end of file
+}
@@ -14,6 +14,7 @@ type ServiceData struct {
 	Name      string // 服务名称，首字母大写
 	VarName   string // 变量名称，首字母小写
 	Package   string // 项目包名
+	FeatureOptions
 }
 
 // GenerateService 生成服务代码
@@ -26,6 +27,7 @@ func (g *Generator) GenerateService(name string, packageName string) error {
 		Name:     name,
 		VarName:  strings.ToLower(name[:1]) + name[1:],
 		Package:  packageName,
+		FeatureOptions: g.featureOptions(),
 	}
 	
 	// 确保目录存在
@@ -112,6 +112,43 @@ func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 	assert.Equal(t, expectedContent, string(content), "生成的服务内容不符合预期")
 }
 
+// 测试项目包含tracing包时服务方法创建子span
+func TestGenerateServiceTraced(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templatePath := filepath.Join(tempDir, "templates", "component", "service", "service.go.tmpl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(templatePath), 0755), "无法创建模板目录")
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{.Name}} traced={{.Traced}}"), 0644), "无法创建测试模板文件")
+	
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 没有tracing包时不启用
+	require.NoError(t, g.GenerateService("User", "myapp"), "生成服务失败")
+	content, err := os.ReadFile(filepath.Join("services", "user_service.go"))
+	require.NoError(t, err, "无法读取生成的服务文件")
+	assert.Equal(t, "User traced=false", string(content))
+	
+	// 以--with=tracing初始化的项目自动启用
+	require.NoError(t, os.MkdirAll("tracing", 0755), "无法创建tracing目录")
+	createTempFile(t, "tracing", "tracing.go", "package tracing")
+	require.NoError(t, g.GenerateService("Order", "myapp"), "生成服务失败")
+	content, err = os.ReadFile(filepath.Join("services", "order_service.go"))
+	require.NoError(t, err, "无法读取生成的服务文件")
+	assert.Equal(t, "Order traced=true", string(content))
+	assert.False(t, g.Options.Traced, "不应修改生成器的选项")
+}
+
 // 测试生成服务 - 错误情况
 func TestGenerateService_Errors(t *testing.T) {
 	// 创建测试环境
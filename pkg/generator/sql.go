@@ -0,0 +1,389 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// GenerateModelsFromSQL 解析SQL文件中的CREATE TABLE语句生成模型，tables为空时生成文件中的全部表
+func (g *Generator) GenerateModelsFromSQL(path string, tables []string, packageName string) error {
+	parsed, err := schema.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	
+	selected, err := selectTables(parsed, tables)
+	if err != nil {
+		return err
+	}
+	
+	specs, err := tableSpecs(parsed, selected, false)
+	if err != nil {
+		return err
+	}
+	if g.Specs == nil {
+		g.Specs = map[string]*ResourceSpec{}
+	}
+	for name, spec := range specs {
+		g.Specs[name] = spec
+	}
+	
+	// 生成模型
+	for _, table := range selected {
+		if err := g.GenerateModel(tableModelName(table.Name), packageName); err != nil {
+			return err
+		}
+	}
+	
+	fmt.Printf("已根据 %s 生成 %d 个模型\n", path, len(selected))
+	return nil
+}
+
+// selectTables 按名称选出要生成的表，names为空时返回全部表
+func selectTables(tables []*schema.Table, names []string) ([]*schema.Table, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("没有找到表定义")
+	}
+	if len(names) == 0 {
+		return tables, nil
+	}
+	
+	var selected []*schema.Table
+	for _, name := range names {
+		table := schema.Find(tables, strings.TrimSpace(name))
+		if table == nil {
+			return nil, fmt.Errorf("表 %s 未定义", name)
+		}
+		selected = append(selected, table)
+	}
+	return selected, nil
+}
+
+// tableModelName 将表名转换为模型名，例如 order_items -> OrderItem
+func tableModelName(table string) string {
+	return formatName(SingularForm(goName(table)))
+}
+
+// tableSpecs 将选中的表转换为资源定义，引用的表也在本次生成或模型已存在时加上关联字段，
+// features为true时单列整数主键统一为 ID uint，与服务、控制器等模板一致
+func tableSpecs(tables []*schema.Table, selected []*schema.Table, features bool) (map[string]*ResourceSpec, error) {
+	specs := map[string]*ResourceSpec{}
+	owners := map[string]string{}
+	for _, table := range selected {
+		name := tableModelName(table.Name)
+		if name == "" {
+			return nil, fmt.Errorf("无法根据表名 %q 确定模型名称", table.Name)
+		}
+		if owner, ok := owners[name]; ok {
+			return nil, fmt.Errorf("表 %s 和 %s 的模型名称重复: %s", owner, table.Name, name)
+		}
+		owners[name] = table.Name
+	}
+	
+	// related 判断模型在生成后是否存在，只有存在时才能声明关联
+	related := func(table *schema.Table) bool {
+		name := tableModelName(table.Name)
+		if _, ok := owners[name]; ok {
+			return true
+		}
+		_, err := os.Stat(filepath.Join("models", strings.ToLower(name)+".go"))
+		return err == nil
+	}
+	
+	for _, table := range selected {
+		spec := &ResourceSpec{
+			Table:    table.Name,
+			Comment:  table.Comment,
+			CRUD:     true,
+			Complete: true,
+			Existing: true,
+		}
+		used := map[string]bool{}
+		add := func(field Field) {
+			if field.Name == "" || used[field.Name] {
+				return
+			}
+			used[field.Name] = true
+			spec.Fields = append(spec.Fields, field)
+		}
+	
+		for _, column := range table.Columns {
+			add(columnField(table, column, features))
+		}
+	
+		// 属于(belongs to)关联
+		for _, fk := range table.ForeignKeys {
+			ref := schema.Find(tables, fk.RefTable)
+			if len(fk.Columns) != 1 || ref == nil || !related(ref) {
+				continue
+			}
+			add(belongsTo(table, fk, ref, features))
+		}
+	
+		// 一对多(has many)关联
+		for _, other := range tables {
+			if !related(other) {
+				continue
+			}
+			for _, fk := range other.ForeignKeys {
+				if len(fk.Columns) == 1 && strings.EqualFold(fk.RefTable, table.Name) {
+					add(hasMany(other, fk, table, features))
+				}
+			}
+		}
+	
+		specs[tableModelName(table.Name)] = spec
+	}
+	return specs, nil
+}
+
+// columnField 将列转换为模型字段，可为空的列使用指针类型
+func columnField(table *schema.Table, column *schema.Column, features bool) Field {
+	name := fieldName(table, column.Name, features)
+	primary := table.IsPrimaryKey(column.Name)
+	typ := sqlGoType(column)
+	switch {
+	case features && column == integerKey(table):
+		typ = "uint"
+	case name == "DeletedAt" && typ == "time.Time":
+		typ = "gorm.DeletedAt"
+	case !column.NotNull && !primary && typ != "[]byte":
+		typ = "*" + typ
+	}
+	
+	// 自增主键、计算列和GORM自动维护的时间戳不出现在请求中
+	readOnly := column.Generated || name == "CreatedAt" || name == "UpdatedAt" || name == "DeletedAt"
+	if primary && (column.AutoIncrement || len(table.PrimaryKey) == 1 && isIntegerType(typ)) {
+		readOnly = true
+	}
+	
+	// 非空且没有默认值的列在请求中必填，bool的零值无法通过required校验
+	binding := ""
+	if column.NotNull && column.Default == "" && !readOnly && typ != "bool" {
+		binding = "required"
+	}
+	
+	return Field{
+		Name:     name,
+		Type:     typ,
+		JSONName: column.Name,
+		Gorm:     columnGormTag(table, column, primary),
+		Binding:  binding,
+		Comment:  column.Comment,
+		ReadOnly: readOnly,
+	}
+}
+
+// integerKey 返回表的单列整数主键，没有时返回nil
+func integerKey(table *schema.Table) *schema.Column {
+	if len(table.PrimaryKey) != 1 {
+		return nil
+	}
+	column := table.Column(table.PrimaryKey[0])
+	if column == nil || !isIntegerType(sqlGoType(column)) {
+		return nil
+	}
+	return column
+}
+
+// fieldName 返回列对应的字段名，features为true时单列整数主键命名为ID
+func fieldName(table *schema.Table, column string, features bool) string {
+	if key := integerKey(table); features && key != nil && strings.EqualFold(key.Name, column) {
+		return "ID"
+	}
+	return goName(column)
+}
+
+// isIntegerType 判断Go类型是否为整数
+func isIntegerType(t string) bool {
+	return strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint")
+}
+
+// sqlGoType 将列类型转换为Go类型，不认识的类型使用string
+func sqlGoType(column *schema.Column) string {
+	integer := func(t string) string {
+		if column.Unsigned() {
+			return "u" + t
+		}
+		return t
+	}
+	
+	switch column.TypeName() {
+	case "bool", "boolean":
+		return "bool"
+	case "bit":
+		if column.Length() <= 1 {
+			return "bool"
+		}
+		return "[]byte"
+	case "tinyint":
+		if column.Length() == 1 {
+			return "bool"
+		}
+		return integer("int8")
+	case "smallint", "int2", "smallserial", "serial2":
+		return integer("int16")
+	case "mediumint", "int", "integer", "int4", "serial", "serial4":
+		return integer("int")
+	case "bigint", "int8", "bigserial", "serial8":
+		return integer("int64")
+	case "float", "float4":
+		return "float32"
+	case "real", "double", "double precision", "float8", "decimal", "numeric":
+		return "float64"
+	case "date", "datetime", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone":
+		return "time.Time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "bytea", "binary", "varbinary":
+		return "[]byte"
+	}
+	return "string"
+}
+
+// plainSQLTypes GORM按Go类型即可建出等价列的类型，不需要type标签
+var plainSQLTypes = map[string]bool{
+	"bool": true, "boolean": true, "tinyint": true, "smallint": true, "int2": true, "mediumint": true,
+	"int": true, "integer": true, "int4": true, "bigint": true, "int8": true,
+	"serial": true, "serial2": true, "serial4": true, "serial8": true, "smallserial": true, "bigserial": true,
+	"float": true, "float4": true, "real": true, "double": true, "double precision": true, "float8": true,
+	"datetime": true, "timestamp": true, "timestamptz": true, "timestamp with time zone": true, "timestamp without time zone": true,
+	"text": true, "blob": true, "bytea": true, "": true,
+}
+
+// columnGormTag 返回列的gorm标签：列名、类型、主键、非空、索引和默认值
+func columnGormTag(table *schema.Table, column *schema.Column, primary bool) string {
+	parts := []string{"column:" + column.Name}
+	
+	typeName := column.TypeName()
+	switch {
+	case typeName == "varchar" || typeName == "character varying" || typeName == "nvarchar":
+		if n := column.Length(); n > 0 {
+			parts = append(parts, fmt.Sprintf("size:%d", n))
+		}
+	case strings.ContainsAny(column.Type, `";`):
+		// 带引号的类型无法写入结构体标签
+	case !plainSQLTypes[typeName], isTimeType(typeName) && column.Length() > 0:
+		// 保留精度等参数，例如 datetime(3)
+		parts = append(parts, "type:"+column.Type)
+	}
+	
+	if primary {
+		parts = append(parts, "primaryKey")
+	}
+	if column.AutoIncrement {
+		parts = append(parts, "autoIncrement")
+	}
+	if column.Generated {
+		parts = append(parts, "->")
+	}
+	if column.NotNull && !primary {
+		parts = append(parts, "not null")
+	}
+	
+	for _, index := range table.ColumnIndexes(column.Name) {
+		var part string
+		switch {
+		case len(index.Columns) == 1 && index.Unique && index.Name == "":
+			if primary {
+				continue
+			}
+			part = "unique"
+		case len(index.Columns) == 1 && index.Name == "":
+			part = "index"
+		default:
+			kind := "index"
+			if index.Unique {
+				kind = "uniqueIndex"
+			}
+			name := index.Name
+			if name == "" {
+				name = "idx_" + table.Name + "_" + strings.Join(index.Columns, "_")
+			}
+			part = kind + ":" + name
+		}
+		parts = appendUnique(parts, part)
+	}
+	
+	if column.Default != "" && !column.AutoIncrement && !strings.ContainsAny(column.Default, "\";`") {
+		parts = append(parts, "default:"+column.Default)
+	}
+	return strings.Join(parts, ";")
+}
+
+// isTimeType 判断列类型是否为日期时间
+func isTimeType(typeName string) bool {
+	return typeName == "datetime" || strings.HasPrefix(typeName, "timestamp")
+}
+
+// appendUnique 追加不重复的元素
+func appendUnique(items []string, item string) []string {
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+// referenceField 返回外键引用的列对应的字段名，未指定时使用主键
+func referenceField(fk schema.ForeignKey, ref *schema.Table, features bool) string {
+	if len(fk.RefColumns) == 1 {
+		return fieldName(ref, fk.RefColumns[0], features)
+	}
+	if len(ref.PrimaryKey) == 1 {
+		return fieldName(ref, ref.PrimaryKey[0], features)
+	}
+	return "ID"
+}
+
+// belongsTo 返回外键对应的关联字段，例如 user_id -> User *User
+func belongsTo(table *schema.Table, fk schema.ForeignKey, ref *schema.Table, features bool) Field {
+	column := fk.Columns[0]
+	model := tableModelName(ref.Name)
+	
+	name := goName(column)
+	jsonName := strings.TrimSuffix(column, "_id")
+	if strings.HasSuffix(name, "ID") && len(name) > 2 {
+		name = strings.TrimSuffix(name, "ID")
+	} else {
+		name = model
+	}
+	if jsonName == column {
+		jsonName = strings.ToLower(model)
+	}
+	
+	tag := fmt.Sprintf("foreignKey:%s;references:%s", fieldName(table, column, features), referenceField(fk, ref, features))
+	var actions []string
+	if fk.OnDelete != "" {
+		actions = append(actions, "OnDelete:"+fk.OnDelete)
+	}
+	if fk.OnUpdate != "" {
+		actions = append(actions, "OnUpdate:"+fk.OnUpdate)
+	}
+	if len(actions) > 0 {
+		tag += ";constraint:" + strings.Join(actions, ",")
+	}
+	
+	return Field{
+		Name:     name,
+		Type:     "*" + model,
+		JSONName: jsonName + ",omitempty",
+		Gorm:     tag,
+		ReadOnly: true,
+	}
+}
+
+// hasMany 返回其他表通过外键引用本表时的一对多关联字段，例如 Orders []Order
+func hasMany(other *schema.Table, fk schema.ForeignKey, table *schema.Table, features bool) Field {
+	model := tableModelName(other.Name)
+	return Field{
+		Name:     PluralForm(model),
+		Type:     "[]" + model,
+		JSONName: other.Name + ",omitempty",
+		Gorm:     fmt.Sprintf("foreignKey:%s;references:%s", fieldName(other, fk.Columns[0], features), referenceField(fk, table, features)),
+		ReadOnly: true,
+	}
+}
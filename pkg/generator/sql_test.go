@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// 测试用SQL文件
+const testSchemaSQL = `
+CREATE TABLE users (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  email varchar(191) NOT NULL COMMENT '邮箱',
+  nickname varchar(64) DEFAULT NULL,
+  balance decimal(10,2) NOT NULL DEFAULT '0.00',
+  role enum('admin','member') NOT NULL DEFAULT 'member',
+  created_at datetime(3) NOT NULL,
+  deleted_at datetime(3) DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_users_email (email),
+  KEY idx_users_deleted_at (deleted_at)
+) COMMENT='用户表';
+
+CREATE TABLE order_items (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id bigint unsigned NOT NULL,
+  sku char(16) NOT NULL,
+  quantity int NOT NULL DEFAULT 1,
+  UNIQUE KEY uk_user_sku (user_id, sku),
+  FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+`
+
+// 测试将表转换为资源定义
+func TestTableSpecs(t *testing.T) {
+	tables, err := schema.ParseSQL(testSchemaSQL)
+	require.NoError(t, err, "解析SQL失败")
+	
+	specs, err := tableSpecs(tables, tables, false)
+	require.NoError(t, err, "转换表结构失败")
+	require.Contains(t, specs, "User")
+	require.Contains(t, specs, "OrderItem", "模型名应为表名的单数形式")
+	
+	user := specs["User"]
+	assert.Equal(t, "users", user.Table)
+	assert.Equal(t, "用户表", user.Comment)
+	assert.True(t, user.Complete, "字段应包含全部列")
+	
+	fields := map[string]Field{}
+	for _, field := range user.Fields {
+		fields[field.Name] = field
+	}
+	assert.Equal(t, Field{Name: "ID", Type: "uint64", JSONName: "id", Gorm: "column:id;primaryKey;autoIncrement", ReadOnly: true}, fields["ID"])
+	assert.Equal(t, Field{Name: "Email", Type: "string", JSONName: "email", Gorm: "column:email;size:191;not null;uniqueIndex:uk_users_email", Binding: "required", Comment: "邮箱"}, fields["Email"])
+	assert.Equal(t, "*string", fields["Nickname"].Type, "可为空的列应使用指针")
+	assert.Equal(t, "float64", fields["Balance"].Type)
+	assert.Equal(t, "column:balance;type:decimal(10,2);not null;default:'0.00'", fields["Balance"].Gorm)
+	assert.Equal(t, "column:role;type:enum('admin','member');not null;default:'member'", fields["Role"].Gorm)
+	assert.Equal(t, "time.Time", fields["CreatedAt"].Type)
+	assert.True(t, fields["CreatedAt"].ReadOnly, "时间戳由GORM维护")
+	assert.Equal(t, "gorm.DeletedAt", fields["DeletedAt"].Type, "deleted_at应使用软删除类型")
+	assert.Equal(t, Field{Name: "OrderItems", Type: "[]OrderItem", JSONName: "order_items,omitempty", Gorm: "foreignKey:UserID;references:ID", ReadOnly: true}, fields["OrderItems"])
+	
+	item := map[string]Field{}
+	for _, field := range specs["OrderItem"].Fields {
+		item[field.Name] = field
+	}
+	assert.Equal(t, "column:user_id;not null;uniqueIndex:uk_user_sku", item["UserID"].Gorm, "复合唯一键应使用命名索引")
+	assert.Equal(t, "column:sku;type:char(16);not null;uniqueIndex:uk_user_sku", item["Sku"].Gorm)
+	assert.Equal(t, Field{Name: "User", Type: "*User", JSONName: "user,omitempty", Gorm: "foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE", ReadOnly: true}, item["User"])
+	
+	// 只生成部分表时不引用不存在的模型
+	specs, err = tableSpecs(tables, tables[1:], false)
+	require.NoError(t, err)
+	for _, field := range specs["OrderItem"].Fields {
+		assert.NotEqual(t, "User", field.Name, "引用的模型不存在时不应生成关联字段")
+	}
+}
+
+// 测试根据SQL文件生成模型
+func TestGenerateModelsFromSQL(t *testing.T) {
+	// 创建测试环境
+	tempDir := createTempDir(t)
+	defer cleanupTempDir(t, tempDir)
+	
+	// 切换到临时目录
+	originalDir, err := os.Getwd()
+	require.NoError(t, err, "无法获取当前工作目录")
+	defer os.Chdir(originalDir)
+	
+	err = os.Chdir(tempDir)
+	require.NoError(t, err, "无法切换到临时目录")
+	
+	// 创建测试模板
+	templatePath := filepath.Join(tempDir, "templates", "component", "model", "model.go.tmpl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(templatePath), 0755), "无法创建模板目录")
+	templateContent := "{{.Name}} {{.TableName}} {{.Complete}}\n{{range .Fields}}{{.Name}} {{.Type}}\n{{end}}"
+	require.NoError(t, os.WriteFile(templatePath, []byte(templateContent), 0644), "无法创建测试模板文件")
+	
+	sqlPath := filepath.Join(tempDir, "schema.sql")
+	require.NoError(t, os.WriteFile(sqlPath, []byte(testSchemaSQL), 0644), "无法写入SQL文件")
+	
+	// 创建生成器
+	g := NewGenerator(filepath.Join(tempDir, "templates"))
+	
+	// 测试只生成指定的表
+	err = g.GenerateModelsFromSQL(sqlPath, []string{"order_items"}, "myapp")
+	require.NoError(t, err, "根据SQL文件生成模型失败")
+	
+	content, err := os.ReadFile(filepath.Join(tempDir, "models", "orderitem.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.Contains(t, string(content), "OrderItem order_items true\n", "模型名或表名不正确")
+	assert.NotContains(t, string(content), "User *User", "users表未生成时不应有关联字段")
+	_, err = os.Stat(filepath.Join(tempDir, "models", "user.go"))
+	assert.True(t, os.IsNotExist(err), "不应生成未指定的表")
+	
+	// 已存在的模型可以被关联
+	err = g.GenerateModelsFromSQL(sqlPath, []string{"users"}, "myapp")
+	require.NoError(t, err, "根据SQL文件生成模型失败")
+	content, err = os.ReadFile(filepath.Join(tempDir, "models", "user.go"))
+	require.NoError(t, err, "无法读取生成的模型文件")
+	assert.Contains(t, string(content), "OrderItems []OrderItem\n", "缺少一对多关联字段")
+	
+	// 测试表不存在的情况
+	err = g.GenerateModelsFromSQL(sqlPath, []string{"missing"}, "myapp")
+	assert.Error(t, err, "期望在表不存在时返回错误，但没有")
+	
+	// 测试文件已存在的情况
+	err = g.GenerateModelsFromSQL(sqlPath, nil, "myapp")
+	assert.Error(t, err, "期望在文件已存在时返回错误，但没有")
+}
+
+// 测试列类型到Go类型的转换
+func TestSQLGoType(t *testing.T) {
+	tests := map[string]string{
+		"tinyint(1)":               "bool",
+		"tinyint unsigned":         "uint8",
+		"int(11)":                  "int",
+		"INT UNSIGNED":             "uint",
+		"bigserial":                "int64",
+		"double precision":         "float64",
+		"float":                    "float32",
+		"timestamp with time zone": "time.Time",
+		"bytea":                    "[]byte",
+		"uuid":                     "string",
+		"jsonb":                    "string",
+	}
+	for typ, expected := range tests {
+		assert.Equal(t, expected, sqlGoType(&schema.Column{Type: typ}), "sqlGoType(%q)", typ)
+	}
+}
@@ -11,10 +11,38 @@ import (
 
 // TestData 测试模板数据
 type TestData struct {
-	Name         string // 测试名称，首字母大写
-	PluralName   string // 复数名称，用于列表方法
-	ResourceName string // 资源名称，用于URL路径
-	Package      string // 项目包名
+	Name         string      // 测试名称，首字母大写
+	PluralName   string      // 复数名称，用于列表方法
+	ResourceName string      // 资源名称，用于URL路径
+	Package      string      // 项目包名
+	BasePath     string      // 路由组路径
+	Fields       Fields      // 模型字段，用于生成请求体
+	Ops          Operations  // 生成的增删改查接口
+	CreateBody   RequestBody // 创建接口绑定的请求结构，用于生成请求体
+	UpdateBody   RequestBody // 更新接口绑定的请求结构，用于生成请求体
+	FeatureOptions
+}
+
+// Echoes 判断更新请求体中的字符串值是否会写入模型并出现在响应中
+func (d TestData) Echoes() bool {
+	if d.UpdateBody.Type == "" {
+		return d.Fields.Echoes()
+	}
+	fields := d.UpdateBody.ModelFields(d.Fields)
+	return len(fields) > 0 && fields.Echoes()
+}
+
+// PatchPayload 返回只包含第一个可更新字段的JSON请求体，用于测试PATCH部分更新，
+// 没有可更新的字段时返回空字符串
+func (d TestData) PatchPayload(value string) string {
+	if len(d.Fields) == 0 && d.UpdateBody.Type == "" {
+		return d.Fields.Payload(value)
+	}
+	fields := d.UpdateBody.ModelFields(d.Fields)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[:1].Payload(value)
 }
 
 // GenerateTest 生成测试代码
@@ -23,11 +51,18 @@ func (g *Generator) GenerateTest(name string, packageName string) error {
 	name = formatName(name)
 	
 	// 准备模板数据
+	spec := g.resourceSpec(name)
 	data := TestData{
-		Name:         name,
-		PluralName:   PluralForm(name),
-		ResourceName: strings.ToLower(name) + "s",
-		Package:      packageName,
+		Name:           name,
+		PluralName:     PluralForm(name),
+		ResourceName:   strings.ToLower(name) + "s",
+		Package:        packageName,
+		BasePath:       spec.basePath(name),
+		Fields:         spec.Fields,
+		Ops:            g.operations(name),
+		CreateBody:     g.requestBody(name, spec.CreateRequest),
+		UpdateBody:     g.requestBody(name, spec.UpdateRequest),
+		FeatureOptions: g.featureOptions(),
 	}
 	
 	// 确保目录存在
@@ -52,4 +87,4 @@ func (g *Generator) GenerateTest(name string, packageName string) error {
 	
 	fmt.Printf("已生成测试文件: %s\n", outputFile)
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,510 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// httpStatuses net/http中常用状态码常量
+var httpStatuses = map[string]int{
+	"StatusOK":                    200,
+	"StatusCreated":               201,
+	"StatusAccepted":              202,
+	"StatusNoContent":             204,
+	"StatusMovedPermanently":      301,
+	"StatusFound":                 302,
+	"StatusNotModified":           304,
+	"StatusBadRequest":            400,
+	"StatusUnauthorized":          401,
+	"StatusPaymentRequired":       402,
+	"StatusForbidden":             403,
+	"StatusNotFound":              404,
+	"StatusMethodNotAllowed":      405,
+	"StatusNotAcceptable":         406,
+	"StatusRequestTimeout":        408,
+	"StatusConflict":              409,
+	"StatusGone":                  410,
+	"StatusPreconditionFailed":    412,
+	"StatusRequestEntityTooLarge": 413,
+	"StatusUnsupportedMediaType":  415,
+	"StatusUnprocessableEntity":   422,
+	"StatusPreconditionRequired":  428,
+	"StatusTooManyRequests":       429,
+	"StatusInternalServerError":   500,
+	"StatusNotImplemented":        501,
+	"StatusBadGateway":            502,
+	"StatusServiceUnavailable":    503,
+	"StatusGatewayTimeout":        504,
+}
+
+// strconvResults strconv解析函数的返回值类型
+var strconvResults = map[string]string{
+	"Atoi":       "int",
+	"ParseInt":   "int64",
+	"ParseUint":  "uint64",
+	"ParseFloat": "float64",
+	"ParseBool":  "bool",
+}
+
+// handlerInfo 从处理函数中推断出的请求和响应
+type handlerInfo struct {
+	Body      *Schema            // 请求体
+	Query     []*Parameter       // 查询参数
+	Headers   []*Parameter       // 请求头参数
+	PathTypes map[string]*Schema // 路径参数类型
+	Statuses  []int              // 按出现顺序的状态码
+	Responses map[int]*Schema    // 状态码 -> 响应体，无响应体时为nil
+}
+
+// handlerAnalyzer 分析单个函数体
+type handlerAnalyzer struct {
+	project  *Project
+	registry *schemaRegistry
+	info     *handlerInfo
+	visited  map[string]bool
+
+	pkg      string             // 函数所在的包
+	recvType string             // 方法接收者类型
+	recvName string             // 方法接收者变量名
+	ctxName  string             // *gin.Context参数名
+	vars     map[string]typeRef // 局部变量类型
+}
+
+// analyzeHandler 分析处理函数，推断请求体、参数和响应
+func analyzeHandler(project *Project, registry *schemaRegistry, pkg string, recvType string, fn *ast.FuncDecl) *handlerInfo {
+	info := &handlerInfo{
+		PathTypes: map[string]*Schema{},
+		Responses: map[int]*Schema{},
+	}
+	a := &handlerAnalyzer{
+		project:  project,
+		registry: registry,
+		info:     info,
+		visited:  map[string]bool{},
+	}
+	a.analyze(pkg, recvType, fn, contextParam(fn))
+	return info
+}
+
+// analyze 分析函数体，ctxName为函数中*gin.Context的变量名
+func (a *handlerAnalyzer) analyze(pkg string, recvType string, fn *ast.FuncDecl, ctxName string) {
+	if fn.Body == nil || ctxName == "" {
+		return
+	}
+	key := pkg + "." + recvType + "." + fn.Name.Name
+	if a.visited[key] {
+		return
+	}
+	a.visited[key] = true
+
+	inner := *a
+	inner.pkg = pkg
+	inner.recvType = recvType
+	inner.recvName = ""
+	inner.ctxName = ctxName
+	inner.vars = map[string]typeRef{}
+	if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+		inner.recvName = fn.Recv.List[0].Names[0].Name
+	}
+
+	inner.collectVars(fn)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			inner.visitCall(call)
+		}
+		return true
+	})
+}
+
+// collectVars 记录函数参数和局部变量的类型
+func (a *handlerAnalyzer) collectVars(fn *ast.FuncDecl) {
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			a.vars[name.Name] = typeRef{a.pkg, field.Type}
+		}
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			if node.Type != nil {
+				for _, name := range node.Names {
+					a.vars[name.Name] = typeRef{a.pkg, node.Type}
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 && len(node.Lhs) > 1 {
+				if call, ok := node.Rhs[0].(*ast.CallExpr); ok {
+					for i, ref := range a.callResults(call) {
+						if i < len(node.Lhs) {
+							a.setVar(node.Lhs[i], ref)
+						}
+					}
+				}
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				if i >= len(node.Lhs) {
+					break
+				}
+				if ref, ok := a.exprType(rhs); ok {
+					a.setVar(node.Lhs[i], ref)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// setVar 记录赋值语句左侧变量的类型
+func (a *handlerAnalyzer) setVar(lhs ast.Expr, ref typeRef) {
+	if ident, ok := lhs.(*ast.Ident); ok && ident.Name != "_" {
+		if _, exists := a.vars[ident.Name]; !exists {
+			a.vars[ident.Name] = ref
+		}
+	}
+}
+
+// exprType 推断表达式的类型
+func (a *handlerAnalyzer) exprType(expr ast.Expr) (typeRef, bool) {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return typeRef{a.pkg, e.Type}, true
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return a.exprType(e.X)
+		}
+	case *ast.Ident:
+		ref, ok := a.vars[e.Name]
+		return ref, ok
+	case *ast.CallExpr:
+		if results := a.callResults(e); len(results) > 0 {
+			return results[0], true
+		}
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if ref, ok := a.vars[x.Name]; ok {
+				pkg, name, _ := a.project.lookupType(ref)
+				return a.project.fieldType(pkg, name, e.Sel.Name)
+			}
+		}
+	}
+	return typeRef{}, false
+}
+
+// callResults 推断函数调用的返回值类型
+func (a *handlerAnalyzer) callResults(call *ast.CallExpr) []typeRef {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return a.project.funcResults(a.pkg, fun.Name)
+	case *ast.SelectorExpr:
+		switch x := fun.X.(type) {
+		case *ast.Ident:
+			if x.Name == a.ctxName {
+				switch fun.Sel.Name {
+				case "Param", "Query", "DefaultQuery", "GetHeader", "PostForm", "DefaultPostForm":
+					return []typeRef{{a.pkg, ast.NewIdent("string")}}
+				}
+				return nil
+			}
+			if x.Name == "strconv" {
+				if result, ok := strconvResults[fun.Sel.Name]; ok {
+					return []typeRef{{a.pkg, ast.NewIdent(result)}}
+				}
+				return nil
+			}
+			if ref, ok := a.vars[x.Name]; ok {
+				pkg, name, _ := a.project.lookupType(ref)
+				return a.project.methodResults(pkg, name, fun.Sel.Name)
+			}
+			if _, ok := a.project.Packages[x.Name]; ok {
+				return a.project.funcResults(x.Name, fun.Sel.Name)
+			}
+		case *ast.SelectorExpr:
+			// c.service.Method(...)：通过接收者的字段类型解析方法
+			if recv, ok := x.X.(*ast.Ident); ok && recv.Name == a.recvName {
+				if field, ok := a.project.fieldType(a.pkg, a.recvType, x.Sel.Name); ok {
+					pkg, name, _ := a.project.lookupType(field)
+					return a.project.methodResults(pkg, name, fun.Sel.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// visitCall 识别对gin.Context的调用以及以gin.Context为参数的辅助函数
+func (a *handlerAnalyzer) visitCall(call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if ok {
+		if x, ok := sel.X.(*ast.Ident); ok {
+			if x.Name == a.ctxName {
+				a.visitContextCall(sel.Sel.Name, call.Args)
+				return
+			}
+			if x.Name == "strconv" {
+				a.visitParse(call)
+				return
+			}
+		}
+	}
+
+	// 以gin.Context为参数的包级辅助函数
+	if ident, ok := call.Fun.(*ast.Ident); ok {
+		fn, ok := a.project.Packages[a.pkg].Funcs[ident.Name]
+		if !ok {
+			return
+		}
+		for i, arg := range call.Args {
+			if argIdent, ok := arg.(*ast.Ident); ok && argIdent.Name == a.ctxName {
+				if name := paramName(fn, i); name != "" {
+					a.analyze(a.pkg, "", fn, name)
+				}
+				return
+			}
+		}
+	}
+}
+
+// visitContextCall 处理 ctx.Xxx(...) 调用
+func (a *handlerAnalyzer) visitContextCall(method string, args []ast.Expr) {
+	switch method {
+	case "ShouldBindJSON", "BindJSON", "ShouldBind", "Bind":
+		if len(args) > 0 {
+			if ref, ok := a.exprType(args[0]); ok {
+				a.info.Body = a.registry.schemaFor(ref)
+			}
+		}
+	case "ShouldBindQuery", "BindQuery":
+		if len(args) > 0 {
+			if ref, ok := a.exprType(args[0]); ok {
+				a.addQueryStruct(ref)
+			}
+		}
+	case "Query", "DefaultQuery", "GetQuery":
+		if name, ok := argString(args, 0); ok {
+			a.info.Query = addParameter(a.info.Query, &Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+	case "QueryArray", "GetQueryArray":
+		if name, ok := argString(args, 0); ok {
+			a.info.Query = addParameter(a.info.Query, &Parameter{Name: name, In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}})
+		}
+	case "GetHeader":
+		if name, ok := argString(args, 0); ok && !strings.EqualFold(name, "Authorization") {
+			a.info.Headers = addParameter(a.info.Headers, &Parameter{Name: name, In: "header", Schema: &Schema{Type: "string"}})
+		}
+	case "JSON", "IndentedJSON", "PureJSON", "SecureJSON", "AbortWithStatusJSON":
+		if len(args) == 2 {
+			if code, ok := statusCode(args[0]); ok {
+				a.addResponse(code, a.exprSchema(args[1]))
+			}
+		}
+	case "Status", "AbortWithStatus", "String", "Data":
+		if len(args) > 0 {
+			if code, ok := statusCode(args[0]); ok {
+				a.addResponse(code, nil)
+			}
+		}
+	}
+}
+
+// visitParse 识别 strconv.ParseUint(ctx.Param("id"), ...) 以确定路径参数类型
+func (a *handlerAnalyzer) visitParse(call *ast.CallExpr) {
+	sel := call.Fun.(*ast.SelectorExpr)
+	result, ok := strconvResults[sel.Sel.Name]
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+	inner, ok := call.Args[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	if !ok || innerSel.Sel.Name != "Param" {
+		return
+	}
+	if x, ok := innerSel.X.(*ast.Ident); ok && x.Name == a.ctxName {
+		if name, ok := argString(inner.Args, 0); ok {
+			schema, _ := basicSchema(result)
+			a.info.PathTypes[name] = schema
+		}
+	}
+}
+
+// addQueryStruct 将ShouldBindQuery绑定的结构体字段展开为查询参数
+func (a *handlerAnalyzer) addQueryStruct(ref typeRef) {
+	pkg, _, spec := a.project.lookupType(ref)
+	if spec == nil {
+		return
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return
+	}
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		name, _ := parseTag(tag.Get("form"))
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := a.registry.schemaFor(typeRef{pkg, field.Type})
+		required := applyBinding(schema, tag.Get("binding"))
+		a.info.Query = addParameter(a.info.Query, &Parameter{Name: name, In: "query", Required: required, Schema: schema})
+	}
+}
+
+// addResponse 记录状态码，同一状态码保留第一个有响应体的Schema
+func (a *handlerAnalyzer) addResponse(code int, schema *Schema) {
+	existing, ok := a.info.Responses[code]
+	if !ok {
+		a.info.Statuses = append(a.info.Statuses, code)
+	}
+	if existing == nil {
+		a.info.Responses[code] = schema
+	}
+}
+
+// exprSchema 推断响应体表达式的Schema
+func (a *handlerAnalyzer) exprSchema(expr ast.Expr) *Schema {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if isGinH(e.Type) {
+			schema := &Schema{Type: "object"}
+			for _, elt := range e.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				if key, ok := stringLit(kv.Key); ok {
+					schema.Properties = append(schema.Properties, Property{Name: key, Schema: a.exprSchema(kv.Value)})
+				}
+			}
+			return schema
+		}
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return &Schema{Type: "string"}
+		case token.INT:
+			return &Schema{Type: "integer"}
+		case token.FLOAT:
+			return &Schema{Type: "number"}
+		}
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return &Schema{Type: "boolean"}
+		}
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Error" && len(e.Args) == 0 {
+			return &Schema{Type: "string"}
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return &Schema{Type: "string"}
+		}
+	}
+
+	if ref, ok := a.exprType(expr); ok {
+		return a.registry.schemaFor(ref)
+	}
+	return &Schema{}
+}
+
+// contextParam 返回函数中*gin.Context参数的名称
+func contextParam(fn *ast.FuncDecl) string {
+	for i := range fn.Type.Params.List {
+		if name := paramName(fn, i); name != "" && isGinContext(fn.Type.Params.List[i].Type) {
+			return name
+		}
+	}
+	return ""
+}
+
+// paramName 返回函数第i个参数的名称
+func paramName(fn *ast.FuncDecl, index int) string {
+	i := 0
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if i == index {
+				return name.Name
+			}
+			i++
+		}
+		if len(field.Names) == 0 {
+			i++
+		}
+	}
+	return ""
+}
+
+// isGinContext 判断类型是否为*gin.Context
+func isGinContext(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "gin" && sel.Sel.Name == "Context"
+}
+
+// isGinH 判断类型是否为gin.H或map[string]interface{}
+func isGinH(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		return ok && x.Name == "gin" && t.Sel.Name == "H"
+	case *ast.MapType:
+		key, ok := t.Key.(*ast.Ident)
+		return ok && key.Name == "string"
+	}
+	return false
+}
+
+// statusCode 解析状态码表达式，支持http.StatusXxx和整数字面量
+func statusCode(expr ast.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "http" {
+			code, ok := httpStatuses[e.Sel.Name]
+			return code, ok
+		}
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			code, err := strconv.Atoi(e.Value)
+			return code, err == nil
+		}
+	}
+	return 0, false
+}
+
+// argString 返回第i个参数的字符串字面量
+func argString(args []ast.Expr, i int) (string, bool) {
+	if i >= len(args) {
+		return "", false
+	}
+	return stringLit(args[i])
+}
+
+// addParameter 添加参数，同名参数只保留一个
+func addParameter(params []*Parameter, param *Parameter) []*Parameter {
+	for _, p := range params {
+		if p.Name == param.Name && p.In == param.In {
+			return params
+		}
+	}
+	return append(params, param)
+}
@@ -0,0 +1,276 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options 文档生成选项
+type Options struct {
+	Title   string // 文档标题，默认取模块名的最后一段
+	Version string // 接口版本，默认1.0.0
+}
+
+// annotations 处理函数注释中的@注解，可覆盖推断结果
+//
+//	// @Summary 获取订单列表
+//	// @Description 按创建时间倒序
+//	// @Tags 订单
+//	// @Response 200 订单列表
+//	// @Response 404
+//	// @Deprecated
+type annotations struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Responses   []annotatedResponse
+	Deprecated  bool
+}
+
+// annotatedResponse @Response注解
+type annotatedResponse struct {
+	Code        int
+	Description string
+}
+
+// Generate 扫描项目的路由和控制器，生成OpenAPI 3文档
+func Generate(dir string, opts Options) (*Document, error) {
+	project, err := LoadProject(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := project.Routes()
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("未在 %s 中找到路由", filepath.Join(dir, "routes"))
+	}
+
+	if opts.Title == "" {
+		opts.Title = path.Base(project.Module)
+		if project.Module == "" {
+			opts.Title = filepath.Base(dir)
+		}
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0.0"
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   opts.Title,
+			Version: opts.Version,
+		},
+		Paths: map[string]*PathItem{},
+	}
+
+	registry := newSchemaRegistry(project)
+	operationIDs := map[string]bool{}
+	tags := map[string]bool{}
+	for _, route := range routes {
+		op := buildOperation(project, registry, route)
+
+		if operationIDs[op.OperationID] {
+			op.OperationID += strings.ToUpper(route.Method[:1]) + strings.ToLower(route.Method[1:])
+		}
+		operationIDs[op.OperationID] = true
+
+		for _, tag := range op.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				doc.Tags = append(doc.Tags, Tag{Name: tag})
+			}
+		}
+
+		specPath, _ := openAPIPath(route.Path)
+		item, ok := doc.Paths[specPath]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[specPath] = item
+		}
+		if slot := item.operation(route.Method); slot != nil {
+			*slot = op
+		}
+	}
+
+	if len(registry.schemas) > 0 {
+		doc.Components = &Components{Schemas: registry.schemas}
+	}
+	return doc, nil
+}
+
+// buildOperation 根据路由和处理函数生成接口描述
+func buildOperation(project *Project, registry *schemaRegistry, route Route) *Operation {
+	op := &Operation{
+		OperationID: route.Handler,
+		Responses:   map[string]*Response{},
+	}
+	if route.Type != "" {
+		op.Tags = []string{strings.TrimSuffix(route.Type, "Controller")}
+	} else {
+		op.Tags = []string{strings.TrimSuffix(route.File, "_routes.go")}
+	}
+
+	fn := handlerDecl(project, route)
+	info := &handlerInfo{PathTypes: map[string]*Schema{}, Responses: map[int]*Schema{}}
+	var notes annotations
+	if fn != nil {
+		info = analyzeHandler(project, registry, route.Pkg, route.Type, fn)
+		if fn.Doc != nil {
+			op.Summary = docSummary(fn.Doc.Text(), fn.Name.Name)
+			notes = parseAnnotations(fn.Doc.Text())
+		}
+	}
+
+	// 路径参数
+	_, params := openAPIPath(route.Path)
+	for _, name := range params {
+		schema, ok := info.PathTypes[name]
+		if !ok {
+			schema = &Schema{Type: "string"}
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	op.Parameters = append(op.Parameters, info.Query...)
+	op.Parameters = append(op.Parameters, info.Headers...)
+
+	// 请求体
+	if info.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: info.Body}},
+		}
+	}
+
+	// 响应
+	for _, code := range info.Statuses {
+		op.Responses[strconv.Itoa(code)] = newResponse(code, "", info.Responses[code])
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = newResponse(http.StatusOK, "", nil)
+	}
+
+	applyAnnotations(op, notes, info)
+	return op
+}
+
+// applyAnnotations 用注释中的注解覆盖推断结果，存在@Response时只保留注解中的状态码
+func applyAnnotations(op *Operation, notes annotations, info *handlerInfo) {
+	if notes.Summary != "" {
+		op.Summary = notes.Summary
+	}
+	if notes.Description != "" {
+		op.Description = notes.Description
+	}
+	if len(notes.Tags) > 0 {
+		op.Tags = notes.Tags
+	}
+	op.Deprecated = notes.Deprecated
+
+	if len(notes.Responses) > 0 {
+		op.Responses = map[string]*Response{}
+		for _, r := range notes.Responses {
+			op.Responses[strconv.Itoa(r.Code)] = newResponse(r.Code, r.Description, info.Responses[r.Code])
+		}
+	}
+}
+
+// newResponse 创建响应，说明为空时使用标准状态描述
+func newResponse(code int, description string, schema *Schema) *Response {
+	if description == "" {
+		description = http.StatusText(code)
+	}
+	response := &Response{Description: description}
+	if schema != nil {
+		response.Content = map[string]*MediaType{"application/json": {Schema: schema}}
+	}
+	return response
+}
+
+// parseAnnotations 解析注释中的@注解
+func parseAnnotations(doc string) annotations {
+	var notes annotations
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		name, value, _ := strings.Cut(line[1:], " ")
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(name) {
+		case "summary":
+			notes.Summary = value
+		case "description":
+			if notes.Description != "" {
+				notes.Description += "\n"
+			}
+			notes.Description += value
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					notes.Tags = append(notes.Tags, tag)
+				}
+			}
+		case "response", "success", "failure":
+			codeText, description, _ := strings.Cut(value, " ")
+			if code, err := strconv.Atoi(codeText); err == nil {
+				notes.Responses = append(notes.Responses, annotatedResponse{Code: code, Description: strings.TrimSpace(description)})
+			}
+		case "deprecated":
+			notes.Deprecated = true
+		}
+	}
+	return notes
+}
+
+// handlerDecl 查找路由处理函数的声明
+func handlerDecl(project *Project, route Route) *ast.FuncDecl {
+	pkg, ok := project.Packages[route.Pkg]
+	if !ok || route.Handler == "" {
+		return nil
+	}
+	if route.Type != "" {
+		return pkg.Methods[route.Type][route.Handler]
+	}
+	return pkg.Funcs[route.Handler]
+}
+
+// Load 读取YAML或JSON格式的OpenAPI 3文档
+func Load(path string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取OpenAPI文档: %v", err)
+	}
+
+	var doc Document
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("无法解析OpenAPI文档: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("仅支持OpenAPI 3文档，当前版本: %q", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// Marshal 将文档输出为两个空格缩进的YAML
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("无法生成YAML: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("无法生成YAML: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试项目源码
+var testSources = map[string]string{
+	"go.mod": "module example.com/shop\n\ngo 1.22\n",
+	"routes/order_routes.go": `package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/shop/controllers"
+)
+
+func RegisterOrderRoutes(router *gin.Engine) {
+	controller := controllers.NewOrderController()
+
+	api := router.Group("/api")
+	group := api.Group("/orders")
+	{
+		group.GET("", controller.GetOrders)
+		group.GET("/:id", controller.GetOrder)
+		group.POST("", controller.CreateOrder)
+	}
+	router.GET("/health", health)
+}
+
+func health(c *gin.Context) {}
+`,
+	"controllers/order_controller.go": `package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"example.com/shop/models"
+)
+
+type OrderController struct{}
+
+func NewOrderController() *OrderController {
+	return &OrderController{}
+}
+
+// orderRequest 创建订单请求
+type orderRequest struct {
+	Name   string   ` + "`" + `json:"name" binding:"required,min=2,max=64"` + "`" + `
+	Status string   ` + "`" + `json:"status" binding:"oneof=new paid"` + "`" + `
+	Count  int      ` + "`" + `json:"count" binding:"gte=1"` + "`" + ` // 数量
+	Tags   []string ` + "`" + `json:"tags,omitempty"` + "`" + `
+	secret string
+}
+
+// GetOrders 获取订单列表
+func (c *OrderController) GetOrders(ctx *gin.Context) {
+	page := ctx.DefaultQuery("page", "1")
+	ctx.JSON(http.StatusOK, gin.H{"page": page, "data": []models.Order{}})
+}
+
+// GetOrder 获取单个订单
+// @Summary 查询订单
+// @Response 200 订单详情
+// @Response 404 订单不存在
+func (c *OrderController) GetOrder(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	order := models.Order{ID: uint(id)}
+	ctx.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// CreateOrder 创建订单
+func (c *OrderController) CreateOrder(ctx *gin.Context) {
+	var request orderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		respondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, &models.Order{Name: request.Name})
+}
+
+func respondError(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+}
+`,
+	"models/order.go": `package models
+
+import (
+	"time"
+)
+
+// Order 订单
+type Order struct {
+	ID        uint      ` + "`" + `json:"id"` + "`" + `
+	Name      string    ` + "`" + `json:"name"` + "`" + `
+	Owner     *User     ` + "`" + `json:"owner"` + "`" + `
+	Hidden    string    ` + "`" + `json:"-"` + "`" + `
+	CreatedAt time.Time ` + "`" + `json:"created_at"` + "`" + `
+}
+
+// User 用户
+type User struct {
+	Email string ` + "`" + `json:"email"` + "`" + `
+}
+`,
+}
+
+// createTestProject 在临时目录中写入测试项目
+func createTestProject(t *testing.T) string {
+	dir := t.TempDir()
+	for name, content := range testSources {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "无法创建目录")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "无法写入测试源码")
+	}
+	return dir
+}
+
+// propertyNames 返回Schema的属性名
+func propertyNames(schema *Schema) []string {
+	var names []string
+	for _, prop := range schema.Properties {
+		names = append(names, prop.Name)
+	}
+	return names
+}
+
+// 测试路由解析
+func TestRoutes(t *testing.T) {
+	project, err := LoadProject(createTestProject(t))
+	require.NoError(t, err, "解析项目失败")
+
+	routes := project.Routes()
+	require.Len(t, routes, 4, "路由数量不正确")
+
+	assert.Equal(t, Route{Method: "GET", Path: "/api/orders", Pkg: "controllers", Type: "OrderController", Handler: "GetOrders", File: "order_routes.go"}, routes[0])
+	assert.Equal(t, "/api/orders/:id", routes[1].Path, "路由组前缀拼接不正确")
+	assert.Equal(t, "POST", routes[2].Method, "HTTP方法不正确")
+	assert.Equal(t, Route{Method: "GET", Path: "/health", Pkg: "routes", Handler: "health", File: "order_routes.go"}, routes[3])
+}
+
+// 测试生成文档
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(createTestProject(t), Options{})
+	require.NoError(t, err, "生成文档失败")
+
+	assert.Equal(t, "shop", doc.Info.Title, "默认标题应取模块名")
+	assert.Equal(t, "1.0.0", doc.Info.Version, "默认版本不正确")
+	require.Contains(t, doc.Paths, "/api/orders/{id}", "路径参数未转换为OpenAPI格式")
+
+	// 查询参数和推断的响应
+	list := doc.Paths["/api/orders"].Get
+	require.NotNil(t, list, "缺少GET /api/orders")
+	assert.Equal(t, "获取订单列表", list.Summary, "默认摘要应取注释首行")
+	assert.Equal(t, []string{"Order"}, list.Tags, "标签应取控制器名")
+	require.Len(t, list.Parameters, 1, "查询参数数量不正确")
+	assert.Equal(t, "page", list.Parameters[0].Name)
+	assert.Equal(t, "query", list.Parameters[0].In)
+	data := list.Responses["200"].Content["application/json"].Schema.Properties[1].Schema
+	assert.Equal(t, "array", data.Type, "切片应转换为数组")
+	assert.Equal(t, "#/components/schemas/Order", data.Items.Ref, "项目内结构体应以$ref引用")
+
+	// 注解覆盖摘要和状态码，路径参数类型来自strconv解析
+	get := doc.Paths["/api/orders/{id}"].Get
+	require.NotNil(t, get, "缺少GET /api/orders/{id}")
+	assert.Equal(t, "查询订单", get.Summary, "@Summary未覆盖摘要")
+	assert.Len(t, get.Responses, 2, "@Response应替换推断的状态码")
+	assert.Equal(t, "订单不存在", get.Responses["404"].Description)
+	assert.NotNil(t, get.Responses["200"].Content, "注解的状态码应保留推断的响应体")
+	assert.Equal(t, "integer", get.Parameters[0].Schema.Type, "路径参数类型不正确")
+
+	// 请求体和辅助函数中的响应
+	create := doc.Paths["/api/orders"].Post
+	require.NotNil(t, create, "缺少POST /api/orders")
+	require.NotNil(t, create.RequestBody, "缺少请求体")
+	assert.Equal(t, "#/components/schemas/orderRequest", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, create.Responses, "201")
+	assert.Contains(t, create.Responses, "422", "未识别辅助函数中的响应")
+	assert.Equal(t, "#/components/schemas/Order", create.Responses["201"].Content["application/json"].Schema.Ref)
+
+	// 包级处理函数
+	assert.NotNil(t, doc.Paths["/health"].Get, "缺少包级处理函数的路由")
+
+	// binding标签转换为约束
+	request := doc.Components.Schemas["orderRequest"]
+	require.NotNil(t, request, "缺少orderRequest组件")
+	assert.Equal(t, "创建订单请求", request.Description)
+	assert.Equal(t, []string{"name", "status", "count", "tags"}, propertyNames(request), "属性顺序或过滤不正确")
+	assert.Equal(t, []string{"name"}, request.Required)
+	name := request.Properties[0].Schema
+	assert.Equal(t, 2, *name.MinLength)
+	assert.Equal(t, 64, *name.MaxLength)
+	assert.Equal(t, []string{"new", "paid"}, request.Properties[1].Schema.Enum)
+	assert.Equal(t, 1.0, *request.Properties[2].Schema.Minimum)
+	assert.Equal(t, "数量", request.Properties[2].Schema.Description)
+
+	// 嵌套结构体和外部类型
+	order := doc.Components.Schemas["Order"]
+	require.NotNil(t, order, "缺少Order组件")
+	assert.Equal(t, []string{"id", "name", "owner", "created_at"}, propertyNames(order))
+	assert.Equal(t, "#/components/schemas/User", order.Properties[2].Schema.Ref)
+	assert.Equal(t, "date-time", order.Properties[3].Schema.Format)
+	assert.Contains(t, doc.Components.Schemas, "User")
+
+	// 输出YAML
+	content, err := Marshal(doc)
+	require.NoError(t, err, "输出YAML失败")
+	assert.Contains(t, string(content), "openapi: 3.0.3")
+	assert.Contains(t, string(content), "/api/orders/{id}:")
+}
+
+// 测试没有路由的项目
+func TestGenerate_NoRoutes(t *testing.T) {
+	_, err := Generate(t.TempDir(), Options{})
+	assert.Error(t, err, "期望在没有路由时返回错误，但没有")
+}
+
+// 测试读取生成的文档
+func TestLoad(t *testing.T) {
+	doc, err := Generate(createTestProject(t), Options{})
+	require.NoError(t, err, "生成文档失败")
+	content, err := Marshal(doc)
+	require.NoError(t, err, "输出文档失败")
+
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(path, content, 0644), "无法写入文档")
+
+	loaded, err := Load(path)
+	require.NoError(t, err, "读取文档失败")
+	assert.Equal(t, doc.Info, loaded.Info)
+	assert.NotNil(t, loaded.Paths["/api/orders/{id}"].Operation("GET"), "缺少GET /api/orders/{id}")
+
+	// 属性保持文档中的顺序
+	schema := loaded.Schema("Order")
+	require.NotNil(t, schema, "缺少Order结构")
+	assert.Equal(t, doc.Schema("Order").Properties, schema.Properties, "属性顺序不一致")
+	assert.Equal(t, "Order", SchemaRef(&Schema{Ref: "#/components/schemas/Order"}))
+
+	// 不支持Swagger 2文档
+	require.NoError(t, os.WriteFile(path, []byte("swagger: \"2.0\"\n"), 0644))
+	_, err = Load(path)
+	assert.Error(t, err, "期望在非OpenAPI 3文档时返回错误，但没有")
+}
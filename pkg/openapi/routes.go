@@ -0,0 +1,234 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// httpMethods gin路由注册方法
+var httpMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
+// Route 从路由文件中解析出的一条路由
+type Route struct {
+	Method  string // HTTP方法
+	Path    string // 完整路径，包含路由组前缀，gin格式
+	Pkg     string // 处理函数所在的包
+	Type    string // 处理函数的接收者类型，包级函数为空
+	Handler string // 处理函数名
+	File    string // 路由所在文件
+}
+
+// Routes 扫描routes目录下的*_routes.go，解析路由组前缀、HTTP方法、路径和处理函数
+func (p *Project) Routes() []Route {
+	pkg, ok := p.Packages["routes"]
+	if !ok {
+		return nil
+	}
+
+	files := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		if strings.HasSuffix(name, "_routes.go") {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+
+	var routes []Route
+	for _, name := range files {
+		for _, decl := range pkg.Files[name].Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				routes = append(routes, p.funcRoutes(name, fn)...)
+			}
+		}
+	}
+	return routes
+}
+
+// funcRoutes 解析单个函数中注册的路由
+func (p *Project) funcRoutes(file string, fn *ast.FuncDecl) []Route {
+	prefixes := map[string]string{}  // 路由器变量 -> 路由组前缀
+	handlers := map[string]typeRef{} // 控制器变量 -> 类型
+
+	for _, field := range fn.Type.Params.List {
+		if isRouterType(field.Type) {
+			for _, name := range field.Names {
+				prefixes[name.Name] = ""
+			}
+		}
+	}
+
+	var routes []Route
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				call, ok := rhs.(*ast.CallExpr)
+				if !ok || i >= len(node.Lhs) {
+					continue
+				}
+				lhs, ok := node.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if prefix, ok := groupPrefix(prefixes, call); ok {
+					prefixes[lhs.Name] = prefix
+				} else if results := p.callResults("routes", call); len(results) > 0 {
+					handlers[lhs.Name] = results[0]
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok || !httpMethods[sel.Sel.Name] || len(node.Args) < 2 {
+				return true
+			}
+			prefix, ok := routerPrefix(prefixes, sel.X)
+			if !ok {
+				return true
+			}
+			path, ok := stringLit(node.Args[0])
+			if !ok {
+				return true
+			}
+			route := Route{
+				Method: sel.Sel.Name,
+				Path:   joinPath(prefix, path),
+				File:   file,
+			}
+			p.resolveHandler(&route, handlers, node.Args[len(node.Args)-1])
+			routes = append(routes, route)
+		}
+		return true
+	})
+	return routes
+}
+
+// resolveHandler 解析路由的处理函数：控制器方法、包级函数或匿名函数
+func (p *Project) resolveHandler(route *Route, handlers map[string]typeRef, expr ast.Expr) {
+	switch h := expr.(type) {
+	case *ast.SelectorExpr:
+		x, ok := h.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		route.Handler = h.Sel.Name
+		if ref, ok := handlers[x.Name]; ok {
+			route.Pkg, route.Type, _ = p.lookupType(ref)
+		} else if _, ok := p.Packages[x.Name]; ok {
+			route.Pkg = x.Name
+		}
+	case *ast.Ident:
+		route.Pkg = "routes"
+		route.Handler = h.Name
+	}
+}
+
+// callResults 解析路由文件中函数调用的返回值类型，例如 controllers.NewOrderController(...)
+func (p *Project) callResults(pkg string, call *ast.CallExpr) []typeRef {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			return p.funcResults(x.Name, fun.Sel.Name)
+		}
+	case *ast.Ident:
+		return p.funcResults(pkg, fun.Name)
+	}
+	return nil
+}
+
+// groupPrefix 判断调用是否为 X.Group("/prefix")，返回完整前缀
+func groupPrefix(prefixes map[string]string, call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Group" || len(call.Args) == 0 {
+		return "", false
+	}
+	parent, ok := routerPrefix(prefixes, sel.X)
+	if !ok {
+		return "", false
+	}
+	path, ok := stringLit(call.Args[0])
+	if !ok {
+		return "", false
+	}
+	return joinPath(parent, path), true
+}
+
+// routerPrefix 返回路由器表达式对应的前缀，支持链式的 router.Group("/x").GET(...)
+func routerPrefix(prefixes map[string]string, expr ast.Expr) (string, bool) {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		prefix, ok := prefixes[x.Name]
+		return prefix, ok
+	case *ast.CallExpr:
+		return groupPrefix(prefixes, x)
+	}
+	return "", false
+}
+
+// isRouterType 判断参数类型是否为gin的路由器
+func isRouterType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || x.Name != "gin" {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Engine", "RouterGroup", "IRouter", "IRoutes":
+		return true
+	}
+	return false
+}
+
+// stringLit 返回字符串字面量的值
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// joinPath 拼接路由组前缀和相对路径
+func joinPath(prefix string, path string) string {
+	if path == "" || path == "/" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// openAPIPath 将gin路径参数转换为OpenAPI格式，例如 /:id -> /{id}，并返回参数名
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, name)
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
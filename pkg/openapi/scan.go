@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Package 一个已解析的项目包
+type Package struct {
+	Name    string
+	Files   map[string]*ast.File                // 文件名 -> 语法树
+	Types   map[string]*ast.TypeSpec            // 类型名 -> 类型声明
+	Funcs   map[string]*ast.FuncDecl            // 函数名 -> 函数声明
+	Methods map[string]map[string]*ast.FuncDecl // 接收者类型名 -> 方法名 -> 方法声明
+}
+
+// Project 项目中全部顶层目录下的包，按目录名索引
+type Project struct {
+	Dir      string
+	Module   string
+	Packages map[string]*Package
+}
+
+// typeRef 类型表达式及其所在的包，用于解析包内的未限定类型名
+type typeRef struct {
+	pkg  string
+	expr ast.Expr
+}
+
+// LoadProject 解析项目根目录下各个顶层目录中的Go源码(不含测试文件)
+func LoadProject(dir string) (*Project, error) {
+	project := &Project{
+		Dir:      dir,
+		Module:   readModule(dir),
+		Packages: map[string]*Package{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取项目目录: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || name == "vendor" || name == "tests" || name == "examples" {
+			continue
+		}
+
+		files, err := filepath.Glob(filepath.Join(dir, name, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+			if err != nil {
+				return nil, fmt.Errorf("无法解析源码 %s: %v", file, err)
+			}
+			project.addFile(name, filepath.Base(file), f)
+		}
+	}
+
+	return project, nil
+}
+
+// addFile 将文件中的类型、函数和方法加入包索引
+func (p *Project) addFile(dir string, name string, file *ast.File) {
+	pkg, ok := p.Packages[dir]
+	if !ok {
+		pkg = &Package{
+			Name:    file.Name.Name,
+			Files:   map[string]*ast.File{},
+			Types:   map[string]*ast.TypeSpec{},
+			Funcs:   map[string]*ast.FuncDecl{},
+			Methods: map[string]map[string]*ast.FuncDecl{},
+		}
+		p.Packages[dir] = pkg
+	}
+	pkg.Files[name] = file
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					if ts.Doc == nil && len(d.Specs) == 1 {
+						ts.Doc = d.Doc
+					}
+					pkg.Types[ts.Name.Name] = ts
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				pkg.Funcs[d.Name.Name] = d
+				continue
+			}
+			recv := receiverName(d.Recv.List[0].Type)
+			if pkg.Methods[recv] == nil {
+				pkg.Methods[recv] = map[string]*ast.FuncDecl{}
+			}
+			pkg.Methods[recv][d.Name.Name] = d
+		}
+	}
+}
+
+// lookupType 解析类型表达式指向的项目内命名类型，去掉指针
+func (p *Project) lookupType(ref typeRef) (string, string, *ast.TypeSpec) {
+	switch t := ref.expr.(type) {
+	case *ast.StarExpr:
+		return p.lookupType(typeRef{ref.pkg, t.X})
+	case *ast.Ident:
+		if pkg, ok := p.Packages[ref.pkg]; ok {
+			if spec, ok := pkg.Types[t.Name]; ok {
+				return ref.pkg, t.Name, spec
+			}
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if pkg, ok := p.Packages[x.Name]; ok {
+				if spec, ok := pkg.Types[t.Sel.Name]; ok {
+					return x.Name, t.Sel.Name, spec
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// methodResults 返回命名类型上方法的返回值类型，支持结构体方法和接口方法
+func (p *Project) methodResults(pkgName string, typeName string, method string) []typeRef {
+	pkg, ok := p.Packages[pkgName]
+	if !ok {
+		return nil
+	}
+	if fn, ok := pkg.Methods[typeName][method]; ok {
+		return resultRefs(pkgName, fn.Type)
+	}
+	if spec, ok := pkg.Types[typeName]; ok {
+		if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+			for _, m := range iface.Methods.List {
+				for _, name := range m.Names {
+					if ft, ok := m.Type.(*ast.FuncType); ok && name.Name == method {
+						return resultRefs(pkgName, ft)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// funcResults 返回包级函数的返回值类型
+func (p *Project) funcResults(pkgName string, name string) []typeRef {
+	pkg, ok := p.Packages[pkgName]
+	if !ok {
+		return nil
+	}
+	if fn, ok := pkg.Funcs[name]; ok {
+		return resultRefs(pkgName, fn.Type)
+	}
+	return nil
+}
+
+// fieldType 返回结构体字段的类型
+func (p *Project) fieldType(pkgName string, typeName string, field string) (typeRef, bool) {
+	pkg, ok := p.Packages[pkgName]
+	if !ok {
+		return typeRef{}, false
+	}
+	spec, ok := pkg.Types[typeName]
+	if !ok {
+		return typeRef{}, false
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return typeRef{}, false
+	}
+	for _, f := range st.Fields.List {
+		for _, name := range f.Names {
+			if name.Name == field {
+				return typeRef{pkgName, f.Type}, true
+			}
+		}
+	}
+	return typeRef{}, false
+}
+
+// resultRefs 将函数签名的返回值展开为类型列表
+func resultRefs(pkg string, ft *ast.FuncType) []typeRef {
+	if ft.Results == nil {
+		return nil
+	}
+	var refs []typeRef
+	for _, field := range ft.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			refs = append(refs, typeRef{pkg, field.Type})
+		}
+	}
+	return refs
+}
+
+// receiverName 返回方法接收者的类型名
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	}
+	return ""
+}
+
+// readModule 读取go.mod中的模块名
+func readModule(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schemaRegistry 收集被引用的结构体并生成components.schemas
+type schemaRegistry struct {
+	project *Project
+	schemas map[string]*Schema // 组件名 -> Schema
+	names   map[string]string  // 包名.类型名 -> 组件名
+}
+
+func newSchemaRegistry(project *Project) *schemaRegistry {
+	return &schemaRegistry{
+		project: project,
+		schemas: map[string]*Schema{},
+		names:   map[string]string{},
+	}
+}
+
+// externalSchemas 项目外常用类型对应的Schema
+var externalSchemas = map[string]func() *Schema{
+	"time.Time":       func() *Schema { return &Schema{Type: "string", Format: "date-time"} },
+	"time.Duration":   func() *Schema { return &Schema{Type: "integer", Format: "int64"} },
+	"gorm.DeletedAt":  func() *Schema { return &Schema{Type: "string", Format: "date-time", Nullable: true} },
+	"uuid.UUID":       func() *Schema { return &Schema{Type: "string", Format: "uuid"} },
+	"json.RawMessage": func() *Schema { return &Schema{} },
+	"gin.H":           func() *Schema { return &Schema{Type: "object"} },
+}
+
+// basicSchema 返回Go内置类型对应的Schema
+func basicSchema(name string) (*Schema, bool) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, true
+	case "bool":
+		return &Schema{Type: "boolean"}, true
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte", "rune":
+		return &Schema{Type: "integer"}, true
+	case "int32", "uint32":
+		return &Schema{Type: "integer", Format: "int32"}, true
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}, true
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}, true
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}, true
+	case "any":
+		return &Schema{}, true
+	case "error":
+		return &Schema{Type: "string"}, true
+	}
+	return nil, false
+}
+
+// schemaFor 将类型表达式转换为Schema，项目内的结构体以$ref引用
+func (r *schemaRegistry) schemaFor(ref typeRef) *Schema {
+	switch t := ref.expr.(type) {
+	case *ast.StarExpr:
+		return r.schemaFor(typeRef{ref.pkg, t.X})
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(typeRef{ref.pkg, t.Elt})}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(typeRef{ref.pkg, t.Value})}
+	case *ast.InterfaceType:
+		return &Schema{}
+	case *ast.StructType:
+		return r.structSchema(ref.pkg, t)
+	case *ast.Ident:
+		if schema, ok := basicSchema(t.Name); ok {
+			return schema
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if external, ok := externalSchemas[x.Name+"."+t.Sel.Name]; ok {
+				return external()
+			}
+		}
+	}
+
+	pkg, name, spec := r.project.lookupType(ref)
+	if spec == nil {
+		return &Schema{Type: "object"}
+	}
+	if _, ok := spec.Type.(*ast.StructType); !ok {
+		// 非结构体的命名类型按底层类型内联，例如 type Permission string
+		return r.schemaFor(typeRef{pkg, spec.Type})
+	}
+	return &Schema{Ref: "#/components/schemas/" + r.register(pkg, name, spec)}
+}
+
+// register 将结构体加入components.schemas并返回组件名，同名结构体以包名区分
+func (r *schemaRegistry) register(pkg string, name string, spec *ast.TypeSpec) string {
+	key := pkg + "." + name
+	if component, ok := r.names[key]; ok {
+		return component
+	}
+
+	component := name
+	if _, taken := r.schemas[component]; taken {
+		component = key
+	}
+	r.names[key] = component
+
+	// 先占位，避免自引用的结构体无限递归
+	schema := &Schema{}
+	r.schemas[component] = schema
+	*schema = *r.structSchema(pkg, spec.Type.(*ast.StructType))
+	if spec.Doc != nil {
+		schema.Description = docSummary(spec.Doc.Text(), name)
+	}
+	return component
+}
+
+// structSchema 按json和binding标签生成结构体的Schema
+func (r *schemaRegistry) structSchema(pkg string, st *ast.StructType) *Schema {
+	schema := &Schema{Type: "object"}
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		jsonName, jsonOpts := parseTag(tag.Get("json"))
+		if jsonName == "-" && jsonOpts == "" {
+			continue
+		}
+
+		// 匿名嵌入的结构体展开其字段
+		if len(field.Names) == 0 && jsonName == "" {
+			r.embed(schema, typeRef{pkg, field.Type})
+			continue
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: receiverName(field.Type)}}
+		}
+		for _, ident := range names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := ident.Name
+			if jsonName != "" {
+				name = jsonName
+			}
+
+			prop := r.schemaFor(typeRef{pkg, field.Type})
+			if field.Comment != nil {
+				prop = withDescription(prop, strings.TrimSpace(field.Comment.Text()))
+			}
+			if applyBinding(prop, tag.Get("binding")) {
+				schema.Required = append(schema.Required, name)
+			}
+			schema.Properties = append(schema.Properties, Property{Name: name, Schema: prop})
+		}
+	}
+	return schema
+}
+
+// embed 将嵌入结构体的属性合并到外层Schema
+func (r *schemaRegistry) embed(schema *Schema, ref typeRef) {
+	if sel, ok := ref.expr.(*ast.SelectorExpr); ok {
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == "gorm" && sel.Sel.Name == "Model" {
+			schema.Properties = append(schema.Properties,
+				Property{Name: "ID", Schema: &Schema{Type: "integer"}},
+				Property{Name: "CreatedAt", Schema: &Schema{Type: "string", Format: "date-time"}},
+				Property{Name: "UpdatedAt", Schema: &Schema{Type: "string", Format: "date-time"}},
+				Property{Name: "DeletedAt", Schema: &Schema{Type: "string", Format: "date-time", Nullable: true}},
+			)
+			return
+		}
+	}
+
+	pkg, _, spec := r.project.lookupType(ref)
+	if spec == nil {
+		return
+	}
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		inner := r.structSchema(pkg, st)
+		schema.Properties = append(schema.Properties, inner.Properties...)
+		schema.Required = append(schema.Required, inner.Required...)
+	}
+}
+
+// applyBinding 将gin的binding校验规则转换为Schema约束，返回字段是否必填
+func applyBinding(schema *Schema, binding string) bool {
+	if binding == "" || schema.Ref != "" {
+		return strings.Contains(","+binding+",", ",required,")
+	}
+
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "datetime":
+			schema.Format = "date-time"
+		case "oneof":
+			schema.Enum = strings.Fields(arg)
+		case "min", "gte":
+			setBound(schema, arg, true)
+		case "max", "lte":
+			setBound(schema, arg, false)
+		case "len":
+			setBound(schema, arg, true)
+			setBound(schema, arg, false)
+		}
+	}
+	return required
+}
+
+// setBound 按Schema类型设置长度、数值或数组元素个数的上下限
+func setBound(schema *Schema, arg string, lower bool) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return
+	}
+	n := int(value)
+	switch schema.Type {
+	case "string":
+		if lower {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &value
+		} else {
+			schema.Maximum = &value
+		}
+	}
+}
+
+// withDescription 为Schema加上说明，$ref不能与其他字段并列，因此保持原样
+func withDescription(schema *Schema, description string) *Schema {
+	if schema.Ref == "" && description != "" {
+		schema.Description = description
+	}
+	return schema
+}
+
+// parseTag 拆分json标签的名称和选项
+func parseTag(tag string) (string, string) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, opts
+}
+
+// docSummary 取注释首行，并去掉开头重复的标识符名称
+func docSummary(doc string, name string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(doc), "\n")
+	line = strings.TrimSpace(line)
+	if rest, ok := strings.CutPrefix(line, name); ok {
+		line = strings.TrimSpace(rest)
+	}
+	return line
+}
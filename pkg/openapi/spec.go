@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document OpenAPI 3文档
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Tags       []Tag                `yaml:"tags,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components *Components          `yaml:"components,omitempty"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version"`
+}
+
+// Tag 接口分组
+type Tag struct {
+	Name string `yaml:"name"`
+}
+
+// PathItem 一个路径下的全部操作
+type PathItem struct {
+	Get     *Operation `yaml:"get,omitempty"`
+	Put     *Operation `yaml:"put,omitempty"`
+	Post    *Operation `yaml:"post,omitempty"`
+	Delete  *Operation `yaml:"delete,omitempty"`
+	Options *Operation `yaml:"options,omitempty"`
+	Head    *Operation `yaml:"head,omitempty"`
+	Patch   *Operation `yaml:"patch,omitempty"`
+}
+
+// Operation 单个接口
+type Operation struct {
+	Tags        []string             `yaml:"tags,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	OperationID string               `yaml:"operationId,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses"`
+	Deprecated  bool                 `yaml:"deprecated,omitempty"`
+}
+
+// Parameter 路径、查询或请求头参数
+type Parameter struct {
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required,omitempty"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `yaml:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+// MediaType 请求或响应的内容
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Components 可复用的组件
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas,omitempty"`
+}
+
+// Schema JSON Schema
+type Schema struct {
+	Ref                  string     `yaml:"$ref,omitempty"`
+	Type                 string     `yaml:"type,omitempty"`
+	Format               string     `yaml:"format,omitempty"`
+	Description          string     `yaml:"description,omitempty"`
+	Nullable             bool       `yaml:"nullable,omitempty"`
+	ReadOnly             bool       `yaml:"readOnly,omitempty"`
+	Enum                 []string   `yaml:"enum,omitempty"`
+	MinLength            *int       `yaml:"minLength,omitempty"`
+	MaxLength            *int       `yaml:"maxLength,omitempty"`
+	Minimum              *float64   `yaml:"minimum,omitempty"`
+	Maximum              *float64   `yaml:"maximum,omitempty"`
+	MinItems             *int       `yaml:"minItems,omitempty"`
+	MaxItems             *int       `yaml:"maxItems,omitempty"`
+	Items                *Schema    `yaml:"items,omitempty"`
+	Properties           Properties `yaml:"properties,omitempty"`
+	AdditionalProperties *Schema    `yaml:"additionalProperties,omitempty"`
+	Required             []string   `yaml:"required,omitempty"`
+	AllOf                []*Schema  `yaml:"allOf,omitempty"`
+	OneOf                []*Schema  `yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema  `yaml:"anyOf,omitempty"`
+}
+
+// Property 结构体字段对应的属性
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties 按字段声明顺序输出的属性列表
+type Properties []Property
+
+// MarshalYAML 将属性列表输出为保持顺序的映射
+func (p Properties) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range p {
+		value := &yaml.Node{}
+		if err := value.Encode(prop.Schema); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: prop.Name}, value)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML 按文档中的顺序读取属性
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("properties必须是映射: 第%d行", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		schema := &Schema{}
+		if err := node.Content[i+1].Decode(schema); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: node.Content[i].Value, Schema: schema})
+	}
+	return nil
+}
+
+// Methods 按固定顺序排列的HTTP方法
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// Operation 返回路径下指定HTTP方法的操作，不存在时返回nil
+func (p *PathItem) Operation(method string) *Operation {
+	if slot := p.operation(method); slot != nil {
+		return *slot
+	}
+	return nil
+}
+
+// SchemaRef 返回指向components.schemas的$ref中的组件名，不是此类引用时返回空字符串
+func SchemaRef(schema *Schema) string {
+	if schema == nil {
+		return ""
+	}
+	return strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+}
+
+// Schema 返回components.schemas中的组件，不存在时返回nil
+func (d *Document) Schema(name string) *Schema {
+	if d.Components == nil {
+		return nil
+	}
+	return d.Components.Schemas[name]
+}
+
+// operation 返回路径下指定HTTP方法的操作槽位
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "OPTIONS":
+		return &p.Options
+	case "HEAD":
+		return &p.Head
+	case "PATCH":
+		return &p.Patch
+	}
+	return nil
+}
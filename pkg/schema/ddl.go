@@ -0,0 +1,253 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect SQL方言，决定标识符的引号、自增列和注释的写法
+type Dialect string
+
+// 支持的SQL方言
+const (
+	MySQL      Dialect = "mysql"
+	PostgreSQL Dialect = "postgres"
+	SQLite     Dialect = "sqlite"
+)
+
+// ParseDialect 解析方言名称，兼容常见的驱动名，例如 postgresql、pgx、sqlite3
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "mysql", "mariadb":
+		return MySQL, nil
+	case "postgres", "postgresql", "pgx", "pg":
+		return PostgreSQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	}
+	return "", fmt.Errorf("不支持的SQL方言: %s(可选 mysql、postgres、sqlite)", name)
+}
+
+// Quote 为标识符加上引号
+func (d Dialect) Quote(name string) string {
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteList 为多个标识符加上引号并用逗号连接
+func (d Dialect) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// String 返回字符串字面量
+func String(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// CreateTable 返回建表语句，以及建表后需要执行的索引和注释语句，语句不含结尾的分号
+func (d Dialect) CreateTable(t *Table) []string {
+	// SQLite的自增列必须写成 INTEGER PRIMARY KEY AUTOINCREMENT
+	inlineKey := ""
+	if d == SQLite && len(t.PrimaryKey) == 1 {
+		if column := t.Column(t.PrimaryKey[0]); column != nil && column.AutoIncrement {
+			inlineKey = column.Name
+		}
+	}
+
+	var lines []string
+	for _, column := range t.Columns {
+		lines = append(lines, "  "+d.columnDefinition(column, column.Name == inlineKey))
+	}
+	if len(t.PrimaryKey) > 0 && inlineKey == "" {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", d.quoteList(t.PrimaryKey)))
+	}
+	for _, index := range t.Indexes {
+		if index.Unique && index.Name == "" {
+			lines = append(lines, fmt.Sprintf("  UNIQUE (%s)", d.quoteList(index.Columns)))
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		lines = append(lines, "  "+d.foreignKey(fk))
+	}
+
+	create := fmt.Sprintf("CREATE TABLE %s (\n%s\n)", d.Quote(t.Name), strings.Join(lines, ",\n"))
+	if d == MySQL && t.Comment != "" {
+		create += " COMMENT=" + String(t.Comment)
+	}
+	statements := []string{create}
+
+	for _, index := range t.Indexes {
+		if index.Name != "" {
+			statements = append(statements, d.CreateIndex(t.Name, index))
+		}
+	}
+	if d == PostgreSQL {
+		if t.Comment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s", d.Quote(t.Name), String(t.Comment)))
+		}
+		for _, column := range t.Columns {
+			if column.Comment != "" {
+				statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", d.Quote(t.Name), d.Quote(column.Name), String(column.Comment)))
+			}
+		}
+	}
+	return statements
+}
+
+// DropTable 返回删除表的语句
+func (d Dialect) DropTable(name string) string {
+	return "DROP TABLE IF EXISTS " + d.Quote(name)
+}
+
+// CreateIndex 返回创建具名索引的语句
+func (d Dialect) CreateIndex(table string, index Index) string {
+	kind := "INDEX"
+	if index.Unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, d.Quote(index.Name), d.Quote(table), d.quoteList(index.Columns))
+}
+
+// columnDefinition 返回建表语句中的列定义
+func (d Dialect) columnDefinition(column *Column, inlineKey bool) string {
+	typ := column.Type
+	if column.AutoIncrement && d == PostgreSQL {
+		switch column.TypeName() {
+		case "bigint", "int8":
+			typ = "bigserial"
+		case "smallint", "int2":
+			typ = "smallserial"
+		default:
+			typ = "serial"
+		}
+	}
+
+	parts := []string{d.Quote(column.Name), typ}
+	if inlineKey {
+		return strings.Join(append(parts, "PRIMARY KEY AUTOINCREMENT"), " ")
+	}
+	if column.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if column.AutoIncrement && d == MySQL {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if column.Default != "" {
+		parts = append(parts, "DEFAULT "+column.Default)
+	}
+	if d == MySQL && column.Comment != "" {
+		parts = append(parts, "COMMENT "+String(column.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+// foreignKey 返回外键约束
+func (d Dialect) foreignKey(fk ForeignKey) string {
+	clause := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s", d.quoteList(fk.Columns), d.Quote(fk.RefTable))
+	if len(fk.RefColumns) > 0 {
+		clause += fmt.Sprintf(" (%s)", d.quoteList(fk.RefColumns))
+	}
+	if fk.OnDelete != "" {
+		clause += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		clause += " ON UPDATE " + fk.OnUpdate
+	}
+	return clause
+}
+
+// DropIndex 返回删除索引的语句
+func (d Dialect) DropIndex(table string, name string) string {
+	if d == MySQL {
+		return fmt.Sprintf("DROP INDEX %s ON %s", d.Quote(name), d.Quote(table))
+	}
+	return "DROP INDEX " + d.Quote(name)
+}
+
+// AddColumn 返回增加列的语句
+func (d Dialect) AddColumn(table string, column *Column) []string {
+	statements := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", d.Quote(table), d.columnDefinition(column, false))}
+	if d == PostgreSQL && column.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", d.Quote(table), d.Quote(column.Name), String(column.Comment)))
+	}
+	return statements
+}
+
+// DropColumn 返回删除列的语句
+func (d Dialect) DropColumn(table string, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.Quote(table), d.Quote(name))
+}
+
+// AlterColumn 返回将列从from修改为to的语句，SQLite不支持修改列，需要重建表
+func (d Dialect) AlterColumn(table string, from *Column, to *Column) []string {
+	if d == MySQL {
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", d.Quote(table), d.columnDefinition(to, false))}
+	}
+
+	prefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", d.Quote(table), d.Quote(to.Name))
+	var statements []string
+	if !strings.EqualFold(from.Type, to.Type) {
+		statements = append(statements, fmt.Sprintf("%sTYPE %s USING %s::%s", prefix, to.Type, d.Quote(to.Name), to.Type))
+	}
+	if from.NotNull != to.NotNull {
+		if to.NotNull {
+			statements = append(statements, prefix+"SET NOT NULL")
+		} else {
+			statements = append(statements, prefix+"DROP NOT NULL")
+		}
+	}
+	if from.Default != to.Default {
+		if to.Default != "" {
+			statements = append(statements, prefix+"SET DEFAULT "+to.Default)
+		} else {
+			statements = append(statements, prefix+"DROP DEFAULT")
+		}
+	}
+	if from.Comment != to.Comment {
+		comment := "NULL"
+		if to.Comment != "" {
+			comment = String(to.Comment)
+		}
+		statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", d.Quote(table), d.Quote(to.Name), comment))
+	}
+	return statements
+}
+
+// RebuildTable 返回按to的定义重建表的语句：建新表、复制两者共有的列、删除旧表后改名，再重建索引，
+// 用于SQLite修改列
+func (d Dialect) RebuildTable(from *Table, to *Table) []string {
+	temp := *to
+	temp.Name = to.Name + "__new"
+	temp.Indexes = nil
+	for _, index := range to.Indexes {
+		if index.Name == "" {
+			temp.Indexes = append(temp.Indexes, index)
+		}
+	}
+
+	var columns []string
+	for _, column := range to.Columns {
+		if from.Column(column.Name) != nil {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	statements := d.CreateTable(&temp)
+	statements = append(statements,
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", d.Quote(temp.Name), d.quoteList(columns), d.quoteList(columns), d.Quote(from.Name)),
+		"DROP TABLE "+d.Quote(from.Name),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", d.Quote(temp.Name), d.Quote(to.Name)),
+	)
+	for _, index := range to.Indexes {
+		if index.Name != "" {
+			statements = append(statements, d.CreateIndex(to.Name, index))
+		}
+	}
+	return statements
+}
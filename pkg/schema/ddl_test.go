@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试解析方言名称
+func TestParseDialect(t *testing.T) {
+	for name, want := range map[string]Dialect{
+		"mysql":      MySQL,
+		"PostgreSQL": PostgreSQL,
+		"pgx":        PostgreSQL,
+		"sqlite3":    SQLite,
+	} {
+		dialect, err := ParseDialect(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, dialect, name)
+	}
+
+	_, err := ParseDialect("oracle")
+	assert.Error(t, err, "不支持的方言应返回错误")
+}
+
+// 测试生成建表语句
+func TestCreateTable(t *testing.T) {
+	table := &Table{
+		Name: "users",
+		Columns: []*Column{
+			{Name: "id", Type: "bigint", NotNull: true, AutoIncrement: true},
+			{Name: "email", Type: "varchar(191)", NotNull: true, Comment: "邮箱"},
+			{Name: "nickname", Type: "text", Default: "'it''s me'"},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes: []Index{
+			{Columns: []string{"email"}, Unique: true},
+			{Name: "idx_users_nickname", Columns: []string{"nickname"}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"CREATE TABLE `users` (\n" +
+			"  `id` bigint NOT NULL AUTO_INCREMENT,\n" +
+			"  `email` varchar(191) NOT NULL COMMENT '邮箱',\n" +
+			"  `nickname` text DEFAULT 'it''s me',\n" +
+			"  PRIMARY KEY (`id`),\n" +
+			"  UNIQUE (`email`)\n" +
+			")",
+		"CREATE INDEX `idx_users_nickname` ON `users` (`nickname`)",
+	}, MySQL.CreateTable(table))
+
+	assert.Equal(t, []string{
+		"CREATE TABLE \"users\" (\n" +
+			"  \"id\" bigserial NOT NULL,\n" +
+			"  \"email\" varchar(191) NOT NULL,\n" +
+			"  \"nickname\" text DEFAULT 'it''s me',\n" +
+			"  PRIMARY KEY (\"id\"),\n" +
+			"  UNIQUE (\"email\")\n" +
+			")",
+		"CREATE INDEX \"idx_users_nickname\" ON \"users\" (\"nickname\")",
+		"COMMENT ON COLUMN \"users\".\"email\" IS '邮箱'",
+	}, PostgreSQL.CreateTable(table), "PostgreSQL的自增列应使用serial类型，注释使用单独的语句")
+
+	statements := SQLite.CreateTable(table)
+	assert.Contains(t, statements[0], "  \"id\" bigint PRIMARY KEY AUTOINCREMENT,\n", "SQLite的自增主键应写在列上")
+	assert.NotContains(t, statements[0], "PRIMARY KEY (", "SQLite的自增主键不应重复声明")
+
+	assert.Equal(t, "DROP TABLE IF EXISTS `users`", MySQL.DropTable("users"))
+}
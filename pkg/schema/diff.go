@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Change 表结构的一项变更
+type Change struct {
+	Description string   // 变更说明，例如 删除列 users.age
+	Destructive bool     // 可能丢失数据，执行前需要确认
+	Up          []string // 升级语句
+	Down        []string // 回滚语句
+}
+
+// Diff 比较两组表结构，返回从from变为to所需的变更。
+// 每张表按删除索引、增加列、修改列、删除列、创建索引的顺序排列，删除的表排在最后；
+// 无法区分重命名和删除后新增，重命名的列或表表现为删除和新增
+func (d Dialect) Diff(from []*Table, to []*Table) []Change {
+	var changes []Change
+	for _, table := range to {
+		old := Find(from, table.Name)
+		if old == nil {
+			changes = append(changes, Change{
+				Description: "新建表 " + table.Name,
+				Up:          d.CreateTable(table),
+				Down:        []string{d.DropTable(table.Name)},
+			})
+			continue
+		}
+		changes = append(changes, d.diffTable(old, table)...)
+	}
+
+	for _, table := range from {
+		if Find(to, table.Name) == nil {
+			changes = append(changes, Change{
+				Description: "删除表 " + table.Name,
+				Destructive: true,
+				Up:          []string{d.DropTable(table.Name)},
+				Down:        d.CreateTable(table),
+			})
+		}
+	}
+	return changes
+}
+
+// diffTable 比较同一张表的列和索引
+func (d Dialect) diffTable(from *Table, to *Table) []Change {
+	var altered []string
+	for _, column := range to.Columns {
+		if old := from.Column(column.Name); old != nil && !sameColumn(old, column) {
+			altered = append(altered, column.Name)
+		}
+	}
+
+	// SQLite不支持修改列，重建整张表，增删的列和索引一并处理
+	if d == SQLite && len(altered) > 0 {
+		var descriptions []string
+		for _, name := range altered {
+			descriptions = append(descriptions, name+" "+describeAlter(from.Column(name), to.Column(name)))
+		}
+		for _, column := range to.Columns {
+			if from.Column(column.Name) == nil {
+				descriptions = append(descriptions, "增加 "+column.Name)
+			}
+		}
+		for _, column := range from.Columns {
+			if to.Column(column.Name) == nil {
+				descriptions = append(descriptions, "删除 "+column.Name)
+			}
+		}
+		return []Change{{
+			Description: fmt.Sprintf("重建表 %s: %s", to.Name, strings.Join(descriptions, "；")),
+			Destructive: true,
+			Up:          d.RebuildTable(from, to),
+			Down:        d.RebuildTable(to, from),
+		}}
+	}
+
+	var changes []Change
+
+	// 先删除不再需要或定义变化的索引，避免删除列时索引失效
+	for _, index := range from.Indexes {
+		if index.Name == "" || sameIndex(index, findIndex(to.Indexes, index.Name)) {
+			continue
+		}
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("删除索引 %s.%s", from.Name, index.Name),
+			Up:          []string{d.DropIndex(from.Name, index.Name)},
+			Down:        []string{d.CreateIndex(from.Name, index)},
+		})
+	}
+
+	for _, column := range to.Columns {
+		if from.Column(column.Name) != nil {
+			continue
+		}
+		change := Change{
+			Description: fmt.Sprintf("增加列 %s.%s", to.Name, column.Name),
+			Up:          d.AddColumn(to.Name, column),
+			Down:        []string{d.DropColumn(to.Name, column.Name)},
+		}
+		if column.NotNull && column.Default == "" {
+			change.Description += "(非空且没有默认值，表中已有数据时会失败)"
+			change.Destructive = true
+		}
+		changes = append(changes, change)
+	}
+
+	for _, name := range altered {
+		old, column := from.Column(name), to.Column(name)
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("修改列 %s.%s: %s", to.Name, name, describeAlter(old, column)),
+			Destructive: !strings.EqualFold(old.Type, column.Type) || column.NotNull && !old.NotNull,
+			Up:          d.AlterColumn(to.Name, old, column),
+			Down:        d.AlterColumn(to.Name, column, old),
+		})
+	}
+
+	for _, column := range from.Columns {
+		if to.Column(column.Name) == nil {
+			changes = append(changes, Change{
+				Description: fmt.Sprintf("删除列 %s.%s", from.Name, column.Name),
+				Destructive: true,
+				Up:          []string{d.DropColumn(from.Name, column.Name)},
+				Down:        d.AddColumn(from.Name, column),
+			})
+		}
+	}
+
+	for _, index := range to.Indexes {
+		if index.Name == "" || sameIndex(index, findIndex(from.Indexes, index.Name)) {
+			continue
+		}
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("创建索引 %s.%s", to.Name, index.Name),
+			Up:          []string{d.CreateIndex(to.Name, index)},
+			Down:        []string{d.DropIndex(to.Name, index.Name)},
+		})
+	}
+	return changes
+}
+
+// sameColumn 判断两列的定义是否相同
+func sameColumn(a *Column, b *Column) bool {
+	return strings.EqualFold(a.Type, b.Type) && a.NotNull == b.NotNull && a.Default == b.Default &&
+		a.AutoIncrement == b.AutoIncrement && a.Comment == b.Comment
+}
+
+// describeAlter 描述列定义的变化，例如 bigint -> int，可为空 -> 非空
+func describeAlter(from *Column, to *Column) string {
+	var parts []string
+	if !strings.EqualFold(from.Type, to.Type) {
+		parts = append(parts, from.Type+" -> "+to.Type)
+	}
+	if from.NotNull != to.NotNull {
+		if to.NotNull {
+			parts = append(parts, "可为空 -> 非空")
+		} else {
+			parts = append(parts, "非空 -> 可为空")
+		}
+	}
+	if from.Default != to.Default {
+		parts = append(parts, fmt.Sprintf("默认值 %q -> %q", from.Default, to.Default))
+	}
+	if from.AutoIncrement != to.AutoIncrement {
+		parts = append(parts, "自增属性变化")
+	}
+	if from.Comment != to.Comment {
+		parts = append(parts, "注释变化")
+	}
+	return strings.Join(parts, "，")
+}
+
+// findIndex 按名称查找索引
+func findIndex(indexes []Index, name string) *Index {
+	for i := range indexes {
+		if indexes[i].Name == name {
+			return &indexes[i]
+		}
+	}
+	return nil
+}
+
+// sameIndex 判断索引的列和唯一性是否相同
+func sameIndex(a Index, b *Index) bool {
+	if b == nil || a.Unique != b.Unique || len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		if !strings.EqualFold(a.Columns[i], b.Columns[i]) {
+			return false
+		}
+	}
+	return true
+}
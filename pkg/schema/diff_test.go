@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试比较表结构生成变更
+func TestDiff(t *testing.T) {
+	from := []*Table{
+		{
+			Name: "users",
+			Columns: []*Column{
+				{Name: "id", Type: "bigint", NotNull: true, AutoIncrement: true},
+				{Name: "name", Type: "text"},
+				{Name: "age", Type: "int"},
+				{Name: "email", Type: "varchar(191)"},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []Index{{Name: "idx_users_email", Columns: []string{"email"}}},
+		},
+		{Name: "logs", Columns: []*Column{{Name: "id", Type: "bigint"}}},
+	}
+	to := []*Table{
+		{
+			Name: "users",
+			Columns: []*Column{
+				{Name: "id", Type: "bigint", NotNull: true, AutoIncrement: true},
+				{Name: "name", Type: "varchar(100)", NotNull: true},
+				{Name: "email", Type: "varchar(191)"},
+				{Name: "status", Type: "int", NotNull: true, Default: "0"},
+				{Name: "code", Type: "text", NotNull: true},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []Index{{Name: "idx_users_email", Columns: []string{"email"}, Unique: true}},
+		},
+		{Name: "tags", Columns: []*Column{{Name: "id", Type: "bigint"}}},
+	}
+
+	changes := MySQL.Diff(from, to)
+	var descriptions []string
+	var destructive []bool
+	for _, change := range changes {
+		descriptions = append(descriptions, strings.SplitN(change.Description, "(非空", 2)[0])
+		destructive = append(destructive, change.Destructive)
+	}
+	assert.Equal(t, []string{
+		"删除索引 users.idx_users_email",
+		"增加列 users.status",
+		"增加列 users.code",
+		"修改列 users.name: text -> varchar(100)，可为空 -> 非空",
+		"删除列 users.age",
+		"创建索引 users.idx_users_email",
+		"新建表 tags",
+		"删除表 logs",
+	}, descriptions)
+	assert.Equal(t, []bool{false, false, true, true, true, false, false, true}, destructive, "删除、收紧约束和非空无默认值的新列应标为破坏性变更")
+
+	assert.Equal(t, []string{"DROP INDEX `idx_users_email` ON `users`"}, changes[0].Up)
+	assert.Equal(t, []string{"ALTER TABLE `users` ADD COLUMN `status` int NOT NULL DEFAULT 0"}, changes[1].Up)
+	assert.Equal(t, []string{"ALTER TABLE `users` MODIFY COLUMN `name` varchar(100) NOT NULL"}, changes[3].Up)
+	assert.Equal(t, []string{"ALTER TABLE `users` MODIFY COLUMN `name` text"}, changes[3].Down, "回滚应恢复原来的列定义")
+	assert.Equal(t, []string{"ALTER TABLE `users` DROP COLUMN `age`"}, changes[4].Up)
+	assert.Equal(t, []string{"ALTER TABLE `users` ADD COLUMN `age` int"}, changes[4].Down)
+	assert.Equal(t, []string{"CREATE UNIQUE INDEX `idx_users_email` ON `users` (`email`)"}, changes[5].Up)
+
+	assert.Equal(t, []string{
+		"ALTER TABLE \"users\" ALTER COLUMN \"name\" TYPE varchar(100) USING \"name\"::varchar(100)",
+		"ALTER TABLE \"users\" ALTER COLUMN \"name\" SET NOT NULL",
+	}, PostgreSQL.Diff(from, to)[3].Up)
+
+	assert.Empty(t, MySQL.Diff(to, to), "相同的表结构不应产生变更")
+}
+
+// 测试SQLite修改列时重建表
+func TestDiffSQLiteRebuild(t *testing.T) {
+	from := []*Table{{
+		Name:    "users",
+		Columns: []*Column{{Name: "id", Type: "integer"}, {Name: "name", Type: "text"}, {Name: "age", Type: "integer"}},
+		Indexes: []Index{{Name: "idx_users_name", Columns: []string{"name"}}},
+	}}
+	to := []*Table{{
+		Name:    "users",
+		Columns: []*Column{{Name: "id", Type: "integer"}, {Name: "name", Type: "text", NotNull: true}, {Name: "email", Type: "text"}},
+		Indexes: []Index{{Name: "idx_users_name", Columns: []string{"name"}}},
+	}}
+
+	changes := SQLite.Diff(from, to)
+	require.Len(t, changes, 1, "修改列时应合并为一次重建")
+	assert.True(t, changes[0].Destructive)
+	assert.Equal(t, "重建表 users: name 可为空 -> 非空；增加 email；删除 age", changes[0].Description)
+	assert.Equal(t, []string{
+		"CREATE TABLE \"users__new\" (\n  \"id\" integer,\n  \"name\" text NOT NULL,\n  \"email\" text\n)",
+		"INSERT INTO \"users__new\" (\"id\", \"name\") SELECT \"id\", \"name\" FROM \"users\"",
+		"DROP TABLE \"users\"",
+		"ALTER TABLE \"users__new\" RENAME TO \"users\"",
+		"CREATE INDEX \"idx_users_name\" ON \"users\" (\"name\")",
+	}, changes[0].Up)
+	assert.Contains(t, changes[0].Down[0], "\"age\" integer", "回滚应按原来的定义重建")
+
+	// 只增删列时不需要重建
+	to[0].Columns[1].NotNull = false
+	changes = SQLite.Diff(from, to)
+	require.Len(t, changes, 2)
+	assert.Equal(t, []string{"ALTER TABLE \"users\" ADD COLUMN \"email\" text"}, changes[0].Up)
+	assert.Equal(t, []string{"ALTER TABLE \"users\" DROP COLUMN \"age\""}, changes[1].Up)
+}
@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Introspector 从数据库读取表结构
+type Introspector interface {
+	// Tables 返回数据库中的用户表，按创建顺序排列
+	Tables() ([]*Table, error)
+	// Close 关闭数据库连接
+	Close() error
+}
+
+// Opener 根据连接地址中scheme之后的部分打开数据库，例如 sqlite://./app.db 中的 ./app.db
+type Opener func(dsn string) (Introspector, error)
+
+var (
+	openersMu sync.RWMutex
+	openers   = map[string]Opener{}
+)
+
+// Register 注册数据库类型，通常在驱动包的init中调用，例如 schema.Register("sqlite", Open)
+func Register(scheme string, open Opener) {
+	openersMu.Lock()
+	defer openersMu.Unlock()
+	if _, ok := openers[scheme]; ok {
+		panic("schema: 重复注册数据库类型 " + scheme)
+	}
+	openers[scheme] = open
+}
+
+// Schemes 返回已注册的数据库类型
+func Schemes() []string {
+	openersMu.RLock()
+	defer openersMu.RUnlock()
+	schemes := make([]string, 0, len(openers))
+	for scheme := range openers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Open 根据 <类型>://<地址> 格式的连接地址打开数据库
+func Open(url string) (Introspector, error) {
+	scheme, dsn, ok := strings.Cut(url, "://")
+	if !ok || scheme == "" || dsn == "" {
+		return nil, fmt.Errorf("数据库地址格式应为 <类型>://<地址>，例如 sqlite://./app.db")
+	}
+
+	openersMu.RLock()
+	open, ok := openers[strings.ToLower(scheme)]
+	openersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据库类型: %s (支持: %s)", scheme, strings.Join(Schemes(), ", "))
+	}
+	return open(dsn)
+}
+
+// Inspect 打开数据库并读取全部表结构
+func Inspect(url string) ([]*Table, error) {
+	db, err := Open(url)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return db.Tables()
+}
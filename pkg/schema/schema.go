@@ -0,0 +1,140 @@
+// Package schema 描述数据库表结构，来源可以是SQL DDL文件或数据库
+package schema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Table 数据表
+type Table struct {
+	Name        string       `json:"name"`                   // 表名，不含schema前缀
+	Comment     string       `json:"comment,omitempty"`      // 表注释
+	Columns     []*Column    `json:"columns"`                // 列，按定义顺序
+	PrimaryKey  []string     `json:"primary_key,omitempty"`  // 主键列
+	Indexes     []Index      `json:"indexes,omitempty"`      // 索引和唯一约束
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"` // 外键
+}
+
+// Column 数据表的列
+type Column struct {
+	Name          string `json:"name"`                     // 列名
+	Type          string `json:"type"`                     // 原始列类型，例如 varchar(255)、int unsigned
+	NotNull       bool   `json:"not_null,omitempty"`       // 是否为NOT NULL
+	Default       string `json:"default,omitempty"`        // 默认值表达式，为空表示没有默认值
+	AutoIncrement bool   `json:"auto_increment,omitempty"` // 是否自增(AUTO_INCREMENT、SERIAL、IDENTITY等)
+	Generated     bool   `json:"generated,omitempty"`      // 是否为计算列
+	Comment       string `json:"comment,omitempty"`        // 列注释
+}
+
+// Index 索引
+type Index struct {
+	Name    string   `json:"name,omitempty"`   // 索引名，列上直接声明的UNIQUE没有名称
+	Columns []string `json:"columns"`          // 索引列
+	Unique  bool     `json:"unique,omitempty"` // 是否唯一
+}
+
+// ForeignKey 外键
+type ForeignKey struct {
+	Columns    []string `json:"columns"`               // 本表的列
+	RefTable   string   `json:"ref_table"`             // 引用的表
+	RefColumns []string `json:"ref_columns,omitempty"` // 引用的列，为空时引用主键
+	OnDelete   string   `json:"on_delete,omitempty"`   // 删除时的动作，例如 CASCADE、SET NULL
+	OnUpdate   string   `json:"on_update,omitempty"`   // 更新时的动作
+}
+
+// Column 按名称查找列，不区分大小写
+func (t *Table) Column(name string) *Column {
+	for _, column := range t.Columns {
+		if strings.EqualFold(column.Name, name) {
+			return column
+		}
+	}
+	return nil
+}
+
+// IsPrimaryKey 判断列是否属于主键
+func (t *Table) IsPrimaryKey(name string) bool {
+	for _, column := range t.PrimaryKey {
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForeignKey 返回以单个列为外键的定义，没有时返回nil
+func (t *Table) ForeignKey(name string) *ForeignKey {
+	for i, fk := range t.ForeignKeys {
+		if len(fk.Columns) == 1 && strings.EqualFold(fk.Columns[0], name) {
+			return &t.ForeignKeys[i]
+		}
+	}
+	return nil
+}
+
+// ColumnIndexes 返回包含指定列的索引
+func (t *Table) ColumnIndexes(name string) []Index {
+	var indexes []Index
+	for _, index := range t.Indexes {
+		for _, column := range index.Columns {
+			if strings.EqualFold(column, name) {
+				indexes = append(indexes, index)
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+// typeArgs 匹配类型参数，例如 (255)、(10,2)
+var typeArgs = regexp.MustCompile(`\s*\(.*?\)`)
+
+// TypeName 返回小写的类型名，不含参数和unsigned等修饰，例如 varchar、double precision
+func (c *Column) TypeName() string {
+	name := strings.ToLower(typeArgs.ReplaceAllString(c.Type, ""))
+	var words []string
+	for _, word := range strings.Fields(name) {
+		if word == "unsigned" || word == "signed" || word == "zerofill" {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// Unsigned 判断是否为无符号整数类型
+func (c *Column) Unsigned() bool {
+	for _, word := range strings.Fields(strings.ToLower(c.Type)) {
+		if word == "unsigned" {
+			return true
+		}
+	}
+	return false
+}
+
+// Length 返回类型的第一个数字参数，例如 varchar(255) 返回255，没有时返回0
+func (c *Column) Length() int {
+	args := typeArgs.FindString(c.Type)
+	if args == "" {
+		return 0
+	}
+	args = strings.Trim(strings.TrimSpace(args), "()")
+	first, _, _ := strings.Cut(args, ",")
+	n, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Find 按名称查找表，不区分大小写
+func Find(tables []*Table, name string) *Table {
+	for _, table := range tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
@@ -0,0 +1,772 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // 关键字或未加引号的标识符
+	tokenIdent                   // 加引号的标识符
+	tokenString                  // 字符串字面量
+	tokenNumber                  // 数字
+	tokenPunct                   // 标点符号
+)
+
+// token 词法单元
+type token struct {
+	kind  tokenKind
+	text  string // 去掉引号后的内容
+	start int    // 在源码中的起始位置
+	end   int    // 在源码中的结束位置
+}
+
+// is 判断是否为指定的关键字(不区分大小写)或标点
+func (t token) is(text string) bool {
+	switch t.kind {
+	case tokenWord:
+		return strings.EqualFold(t.text, text)
+	case tokenPunct:
+		return t.text == text
+	}
+	return false
+}
+
+// tokenize 将SQL拆分为词法单元，忽略注释
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(src[i:], "--"), c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("注释未结束")
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			var text strings.Builder
+			j := i + 1
+			for {
+				if j >= len(src) {
+					return nil, fmt.Errorf("引号未结束: %s", src[i:min(i+20, len(src))])
+				}
+				if src[j] == closing {
+					// 连续两个引号表示引号本身
+					if closing != ']' && j+1 < len(src) && src[j+1] == closing {
+						text.WriteByte(closing)
+						j += 2
+						continue
+					}
+					break
+				}
+				if src[j] == '\\' && c == '\'' && j+1 < len(src) {
+					text.WriteByte(src[j+1])
+					j += 2
+					continue
+				}
+				text.WriteByte(src[j])
+				j++
+			}
+			kind := tokenIdent
+			if c == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, text: text.String(), start: i, end: j + 1})
+			i = j + 1
+		case isDigit(c):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:j], start: i, end: j})
+			i = j
+		case isWordByte(c):
+			j := i
+			for j < len(src) && (isWordByte(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// cursor 在一组词法单元上移动的游标
+type cursor struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+// done 判断是否已读完
+func (c *cursor) done() bool {
+	return c.pos >= len(c.tokens)
+}
+
+// peek 判断接下来的词法单元是否依次为指定的关键字或标点
+func (c *cursor) peek(words ...string) bool {
+	if c.pos+len(words) > len(c.tokens) {
+		return false
+	}
+	for i, word := range words {
+		if !c.tokens[c.pos+i].is(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept 接下来的词法单元匹配时跳过它们并返回true
+func (c *cursor) accept(words ...string) bool {
+	if !c.peek(words...) {
+		return false
+	}
+	c.pos += len(words)
+	return true
+}
+
+// next 返回当前词法单元并前进
+func (c *cursor) next() token {
+	t := c.tokens[c.pos]
+	c.pos++
+	return t
+}
+
+// ident 读取一个标识符
+func (c *cursor) ident() (string, error) {
+	if c.done() {
+		return "", fmt.Errorf("缺少标识符")
+	}
+	t := c.tokens[c.pos]
+	if t.kind != tokenWord && t.kind != tokenIdent && t.kind != tokenString {
+		return "", fmt.Errorf("期望标识符，实际为 %q", t.text)
+	}
+	c.pos++
+	return t.text, nil
+}
+
+// name 读取可能带schema前缀的名称，返回最后一段
+func (c *cursor) name() (string, error) {
+	name, err := c.ident()
+	if err != nil {
+		return "", err
+	}
+	for c.accept(".") {
+		if name, err = c.ident(); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// group 读取一对括号中的词法单元，不含括号本身
+func (c *cursor) group() ([]token, error) {
+	if !c.peek("(") {
+		return nil, fmt.Errorf("期望 (")
+	}
+	depth := 0
+	for i := c.pos; i < len(c.tokens); i++ {
+		switch {
+		case c.tokens[i].is("("):
+			depth++
+		case c.tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				inner := c.tokens[c.pos+1 : i]
+				c.pos = i + 1
+				return inner, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("括号未闭合")
+}
+
+// sub 返回在指定词法单元上移动的游标
+func (c *cursor) sub(tokens []token) *cursor {
+	return &cursor{src: c.src, tokens: tokens}
+}
+
+// raw 返回词法单元在源码中对应的原文，连续空白合并为一个空格
+func (c *cursor) raw(tokens []token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(c.src[tokens[0].start:tokens[len(tokens)-1].end]), " ")
+}
+
+// split 按顶层的分隔符拆分词法单元
+func split(tokens []token, sep string) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case depth == 0 && t.is(sep):
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// columnList 读取括号中的列名列表，忽略前缀长度和排序方向，表达式索引返回空列表
+func (c *cursor) columnList() ([]string, error) {
+	inner, err := c.group()
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, part := range split(inner, ",") {
+		p := c.sub(part)
+		name, err := p.ident()
+		if err != nil {
+			return nil, nil
+		}
+		// 前缀长度，例如 name(10)；其他括号是函数调用
+		if p.peek("(") {
+			if args, _ := p.group(); len(args) != 1 || args[0].kind != tokenNumber {
+				return nil, nil
+			}
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
+}
+
+// ParseFile 解析SQL文件中的表结构
+func ParseFile(path string) ([]*Table, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取SQL文件: %v", err)
+	}
+	return ParseSQL(string(content))
+}
+
+// ParseSQL 解析MySQL或PostgreSQL方言的DDL，支持CREATE TABLE、CREATE INDEX、
+// ALTER TABLE 和 COMMENT ON 语句，其他语句被忽略
+func ParseSQL(src string) ([]*Table, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析SQL: %v", err)
+	}
+
+	p := &sqlParser{}
+	for _, statement := range split(tokens, ";") {
+		c := &cursor{src: src, tokens: statement}
+		if err := p.statement(c); err != nil {
+			return nil, fmt.Errorf("无法解析SQL语句 %q: %v", summary(c.raw(statement)), err)
+		}
+	}
+	return p.tables, nil
+}
+
+// summary 截取语句开头用于错误信息
+func summary(statement string) string {
+	if runes := []rune(statement); len(runes) > 60 {
+		return string(runes[:60]) + "..."
+	}
+	return statement
+}
+
+// sqlParser 累积解析出的表
+type sqlParser struct {
+	tables []*Table
+}
+
+// statement 解析一条语句
+func (p *sqlParser) statement(c *cursor) error {
+	switch {
+	case c.accept("CREATE"):
+		c.accept("OR", "REPLACE")
+		for c.accept("TEMPORARY") || c.accept("TEMP") || c.accept("UNLOGGED") || c.accept("GLOBAL") || c.accept("LOCAL") {
+		}
+		if c.accept("TABLE") {
+			return p.createTable(c)
+		}
+		unique := c.accept("UNIQUE")
+		if c.accept("INDEX") {
+			return p.createIndex(c, unique)
+		}
+	case c.accept("ALTER", "TABLE"):
+		return p.alterTable(c)
+	case c.accept("COMMENT", "ON"):
+		return p.comment(c)
+	}
+	return nil
+}
+
+// createTable 解析CREATE TABLE语句
+func (p *sqlParser) createTable(c *cursor) error {
+	c.accept("IF", "NOT", "EXISTS")
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	// CREATE TABLE ... AS SELECT 和 LIKE 等形式没有列定义
+	if !c.peek("(") {
+		return nil
+	}
+	definitions, err := c.group()
+	if err != nil {
+		return err
+	}
+
+	table := &Table{Name: name}
+	for _, definition := range split(definitions, ",") {
+		if len(definition) == 0 {
+			continue
+		}
+		d := c.sub(definition)
+		if isConstraint(d) {
+			err = table.constraint(d)
+		} else {
+			err = table.column(d)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// 表选项，例如MySQL的 COMMENT='用户表'
+	for !c.done() {
+		if c.accept("COMMENT") {
+			c.accept("=")
+			if !c.done() {
+				table.Comment = c.next().text
+			}
+			continue
+		}
+		c.next()
+	}
+
+	if existing := Find(p.tables, name); existing != nil {
+		*existing = *table
+	} else {
+		p.tables = append(p.tables, table)
+	}
+	return nil
+}
+
+// isConstraint 判断表定义中的一项是否为表级约束
+func isConstraint(c *cursor) bool {
+	for _, word := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "KEY", "INDEX", "FULLTEXT", "SPATIAL", "CHECK", "EXCLUDE"} {
+		if c.peek(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraint 解析表级约束
+func (t *Table) constraint(c *cursor) error {
+	name := ""
+	if c.accept("CONSTRAINT") {
+		var err error
+		if name, err = c.ident(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		columns, err := c.columnList()
+		if err != nil {
+			return err
+		}
+		t.PrimaryKey = columns
+	case c.accept("UNIQUE"):
+		if !c.accept("KEY") {
+			c.accept("INDEX")
+		}
+		if !c.peek("(") {
+			var err error
+			if name, err = c.ident(); err != nil {
+				return err
+			}
+		}
+		columns, err := c.columnList()
+		if err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			t.Indexes = append(t.Indexes, Index{Name: name, Columns: columns, Unique: true})
+		}
+	case c.accept("KEY"), c.accept("INDEX"):
+		if !c.peek("(") {
+			var err error
+			if name, err = c.ident(); err != nil {
+				return err
+			}
+		}
+		columns, err := c.columnList()
+		if err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			t.Indexes = append(t.Indexes, Index{Name: name, Columns: columns})
+		}
+	case c.accept("FOREIGN", "KEY"):
+		if !c.peek("(") {
+			if _, err := c.ident(); err != nil {
+				return err
+			}
+		}
+		columns, err := c.columnList()
+		if err != nil {
+			return err
+		}
+		if !c.accept("REFERENCES") {
+			return fmt.Errorf("外键缺少REFERENCES")
+		}
+		fk, err := references(c)
+		if err != nil {
+			return err
+		}
+		fk.Columns = columns
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	}
+	// CHECK、EXCLUDE、FULLTEXT等约束不影响模型
+	return nil
+}
+
+// references 解析REFERENCES之后的表名、列和引用动作
+func references(c *cursor) (ForeignKey, error) {
+	var fk ForeignKey
+	var err error
+	if fk.RefTable, err = c.name(); err != nil {
+		return fk, err
+	}
+	if c.peek("(") {
+		if fk.RefColumns, err = c.columnList(); err != nil {
+			return fk, err
+		}
+	}
+	for {
+		switch {
+		case c.accept("ON", "DELETE"):
+			fk.OnDelete = referentialAction(c)
+		case c.accept("ON", "UPDATE"):
+			fk.OnUpdate = referentialAction(c)
+		case c.accept("MATCH"):
+			c.next()
+		default:
+			return fk, nil
+		}
+	}
+}
+
+// referentialAction 读取外键动作，例如 CASCADE、SET NULL、NO ACTION
+func referentialAction(c *cursor) string {
+	for _, action := range [][]string{{"SET", "NULL"}, {"SET", "DEFAULT"}, {"NO", "ACTION"}, {"CASCADE"}, {"RESTRICT"}} {
+		if c.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+// columnStops 列类型之后开始列约束的关键字
+var columnStops = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "AUTO_INCREMENT", "AUTOINCREMENT",
+	"COMMENT", "CHECK", "CONSTRAINT", "COLLATE", "CHARSET", "GENERATED", "ON", "KEY", "AS", "IDENTITY",
+}
+
+// atColumnStop 判断游标是否位于列约束的开头
+func atColumnStop(c *cursor) bool {
+	if c.peek("CHARACTER", "SET") {
+		return true
+	}
+	for _, word := range columnStops {
+		if c.peek(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// column 解析列定义并加入表中
+func (t *Table) column(c *cursor) error {
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	column := &Column{Name: name}
+
+	// 列类型，例如 varchar(255)、int unsigned、timestamp with time zone、text[]
+	start := c.pos
+	for !c.done() && !atColumnStop(c) {
+		if c.peek("(") {
+			if _, err := c.group(); err != nil {
+				return err
+			}
+			continue
+		}
+		c.next()
+	}
+	column.Type = c.raw(c.tokens[start:c.pos])
+	switch strings.ToLower(column.TypeName()) {
+	case "serial", "bigserial", "smallserial", "serial4", "serial8", "serial2":
+		column.AutoIncrement = true
+		column.NotNull = true
+	}
+
+	for !c.done() {
+		switch {
+		case c.accept("NOT", "NULL"):
+			column.NotNull = true
+		case c.accept("NULL"):
+		case c.accept("DEFAULT"):
+			column.Default = defaultValue(c)
+			if strings.HasPrefix(strings.ToLower(column.Default), "nextval(") {
+				column.AutoIncrement = true
+				column.Default = ""
+			}
+		case c.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = []string{name}
+			column.NotNull = true
+		case c.accept("UNIQUE"):
+			c.accept("KEY")
+			t.Indexes = append(t.Indexes, Index{Columns: []string{name}, Unique: true})
+		case c.accept("AUTO_INCREMENT"), c.accept("AUTOINCREMENT"):
+			column.AutoIncrement = true
+		case c.accept("REFERENCES"):
+			fk, err := references(c)
+			if err != nil {
+				return err
+			}
+			fk.Columns = []string{name}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		case c.accept("COMMENT"):
+			if !c.done() {
+				column.Comment = c.next().text
+			}
+		case c.accept("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"), c.accept("GENERATED", "ALWAYS", "AS", "IDENTITY"):
+			column.AutoIncrement = true
+			if c.peek("(") {
+				c.group()
+			}
+		case c.accept("GENERATED", "ALWAYS", "AS"), c.accept("AS"):
+			column.Generated = true
+			if c.peek("(") {
+				c.group()
+			}
+		case c.accept("ON", "UPDATE"):
+			defaultValue(c)
+		case c.accept("CONSTRAINT"), c.accept("COLLATE"), c.accept("CHARSET"), c.accept("CHARACTER", "SET"):
+			if !c.done() {
+				c.next()
+			}
+		case c.peek("CHECK"):
+			c.next()
+			if c.peek("(") {
+				c.group()
+			}
+		default:
+			c.next()
+		}
+	}
+
+	t.Columns = append(t.Columns, column)
+	return nil
+}
+
+// defaultValue 读取默认值表达式，去掉PostgreSQL的类型转换
+func defaultValue(c *cursor) string {
+	start := c.pos
+	for !c.done() && (c.pos == start || !atColumnStop(c)) {
+		if c.peek("(") {
+			c.group()
+			continue
+		}
+		c.next()
+	}
+	tokens := c.tokens[start:c.pos]
+	// 'abc'::character varying 只保留 'abc'
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].is(":") && tokens[i+1].is(":") {
+			tokens = tokens[:i]
+			break
+		}
+	}
+	if len(tokens) == 1 && tokens[0].is("NULL") {
+		return ""
+	}
+	// 带括号的表达式，例如 (now())
+	if len(tokens) > 0 && tokens[0].is("(") && tokens[len(tokens)-1].is(")") {
+		inner := c.raw(tokens)
+		return strings.TrimSpace(inner[1 : len(inner)-1])
+	}
+	return c.raw(tokens)
+}
+
+// createIndex 解析CREATE INDEX语句
+func (p *sqlParser) createIndex(c *cursor, unique bool) error {
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "NOT", "EXISTS")
+	name := ""
+	if !c.peek("ON") {
+		var err error
+		if name, err = c.name(); err != nil {
+			return err
+		}
+	}
+	if !c.accept("ON") {
+		return fmt.Errorf("索引缺少ON")
+	}
+	c.accept("ONLY")
+	tableName, err := c.name()
+	if err != nil {
+		return err
+	}
+	if c.accept("USING") {
+		c.next()
+	}
+	columns, err := c.columnList()
+	if err != nil {
+		return err
+	}
+	// 文件中未定义的表和表达式索引被忽略
+	table := Find(p.tables, tableName)
+	if table == nil || len(columns) == 0 {
+		return nil
+	}
+	table.Indexes = append(table.Indexes, Index{Name: name, Columns: columns, Unique: unique})
+	return nil
+}
+
+// alterTable 解析ALTER TABLE中的ADD和ALTER COLUMN，例如pg_dump输出的约束和序列默认值
+func (p *sqlParser) alterTable(c *cursor) error {
+	c.accept("IF", "EXISTS")
+	c.accept("ONLY")
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	table := Find(p.tables, name)
+	if table == nil {
+		return nil
+	}
+
+	for _, action := range split(c.tokens[c.pos:], ",") {
+		a := c.sub(action)
+		switch {
+		case a.accept("ADD"):
+			if isConstraint(a) {
+				err = table.constraint(a)
+			} else {
+				a.accept("COLUMN")
+				a.accept("IF", "NOT", "EXISTS")
+				err = table.column(a)
+			}
+		case a.accept("ALTER"):
+			a.accept("COLUMN")
+			err = table.alterColumn(a)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterColumn 解析ALTER COLUMN的SET DEFAULT、SET NOT NULL和ADD GENERATED
+func (t *Table) alterColumn(c *cursor) error {
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	column := t.Column(name)
+	if column == nil {
+		return nil
+	}
+	switch {
+	case c.accept("SET", "DEFAULT"):
+		column.Default = defaultValue(c)
+		if strings.HasPrefix(strings.ToLower(column.Default), "nextval(") {
+			column.AutoIncrement = true
+			column.Default = ""
+		}
+	case c.accept("DROP", "DEFAULT"):
+		column.Default = ""
+	case c.accept("SET", "NOT", "NULL"):
+		column.NotNull = true
+	case c.accept("DROP", "NOT", "NULL"):
+		column.NotNull = false
+	case c.accept("ADD", "GENERATED"):
+		column.AutoIncrement = true
+	}
+	return nil
+}
+
+// comment 解析PostgreSQL的 COMMENT ON TABLE/COLUMN 语句
+func (p *sqlParser) comment(c *cursor) error {
+	switch {
+	case c.accept("TABLE"):
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		table := Find(p.tables, name)
+		if table != nil && c.accept("IS") && !c.done() {
+			table.Comment = c.next().text
+		}
+	case c.accept("COLUMN"):
+		// [schema.]table.column
+		var parts []string
+		for {
+			part, err := c.ident()
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+			if !c.accept(".") {
+				break
+			}
+		}
+		if len(parts) < 2 {
+			return fmt.Errorf("列名缺少表名")
+		}
+		table := Find(p.tables, parts[len(parts)-2])
+		if table == nil {
+			return nil
+		}
+		column := table.Column(parts[len(parts)-1])
+		if column != nil && c.accept("IS") && !c.done() {
+			column.Comment = c.next().text
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试解析MySQL方言的DDL
+func TestParseSQL_MySQL(t *testing.T) {
+	tables, err := ParseSQL("-- 用户\n" + `CREATE TABLE IF NOT EXISTS ` + "`users`" + ` (
+  ` + "`id`" + ` bigint unsigned NOT NULL AUTO_INCREMENT,
+  ` + "`email`" + ` varchar(191) NOT NULL COMMENT '邮箱',
+  ` + "`nickname`" + ` varchar(64) DEFAULT NULL,
+  ` + "`active`" + ` tinyint(1) NOT NULL DEFAULT '1',
+  ` + "`updated_at`" + ` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (` + "`id`" + `),
+  UNIQUE KEY ` + "`uk_users_email` (`email`)" + `,
+  KEY ` + "`idx_users_nickname` (`nickname`(10))" + `
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
+
+/* 订单 */
+CREATE TABLE orders (
+  id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id bigint unsigned NOT NULL,
+  note text,
+  CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE SET NULL
+);
+INSERT INTO users (email) VALUES ('a;b');
+`)
+	require.NoError(t, err, "解析SQL失败")
+	require.Len(t, tables, 2, "表数量不正确")
+
+	users := tables[0]
+	assert.Equal(t, "users", users.Name)
+	assert.Equal(t, "用户表", users.Comment, "表注释不正确")
+	assert.Equal(t, []string{"id"}, users.PrimaryKey)
+	require.Len(t, users.Columns, 5, "列数量不正确")
+	assert.Equal(t, &Column{Name: "id", Type: "bigint unsigned", NotNull: true, AutoIncrement: true}, users.Columns[0])
+	assert.Equal(t, &Column{Name: "email", Type: "varchar(191)", NotNull: true, Comment: "邮箱"}, users.Columns[1])
+	assert.Equal(t, "", users.Column("nickname").Default, "DEFAULT NULL不应视为默认值")
+	assert.Equal(t, "'1'", users.Column("active").Default)
+	assert.Equal(t, "CURRENT_TIMESTAMP(3)", users.Column("updated_at").Default, "ON UPDATE不应计入默认值")
+	assert.Equal(t, []Index{
+		{Name: "uk_users_email", Columns: []string{"email"}, Unique: true},
+		{Name: "idx_users_nickname", Columns: []string{"nickname"}},
+	}, users.Indexes)
+
+	orders := tables[1]
+	assert.Equal(t, []string{"id"}, orders.PrimaryKey, "列上声明的主键不正确")
+	assert.Equal(t, []ForeignKey{{Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "SET NULL"}}, orders.ForeignKeys)
+	assert.NotNil(t, orders.ForeignKey("USER_ID"), "按列查找外键应不区分大小写")
+}
+
+// 测试解析PostgreSQL方言和pg_dump输出
+func TestParseSQL_PostgreSQL(t *testing.T) {
+	tables, err := ParseSQL(`
+CREATE TYPE public.status AS ENUM ('new', 'done');
+
+CREATE TABLE public.categories (
+    id integer NOT NULL,
+    parent_id integer,
+    name character varying(100) NOT NULL,
+    meta jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+ALTER TABLE ONLY public.categories ALTER COLUMN id SET DEFAULT nextval('public.categories_id_seq'::regclass);
+ALTER TABLE ONLY public.categories ADD CONSTRAINT categories_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.categories
+    ADD CONSTRAINT categories_parent_fkey FOREIGN KEY (parent_id) REFERENCES public.categories(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX categories_name_key ON public.categories USING btree (name);
+CREATE INDEX categories_lower_name ON public.categories (lower(name));
+COMMENT ON TABLE public.categories IS '分类';
+COMMENT ON COLUMN public.categories.name IS '名称';
+
+CREATE TABLE "products" (
+    id BIGSERIAL PRIMARY KEY,
+    category_id integer REFERENCES categories,
+    price numeric(10,2) NOT NULL CHECK (price >= 0),
+    total numeric GENERATED ALWAYS AS (price * 2) STORED,
+    code uuid UNIQUE,
+    tags text[]
+);
+`)
+	require.NoError(t, err, "解析SQL失败")
+	require.Len(t, tables, 2, "表数量不正确")
+
+	categories := Find(tables, "CATEGORIES")
+	require.NotNil(t, categories, "查找表应不区分大小写")
+	assert.Equal(t, "分类", categories.Comment)
+	assert.Equal(t, []string{"id"}, categories.PrimaryKey, "ALTER TABLE添加的主键不正确")
+	assert.True(t, categories.Column("id").AutoIncrement, "nextval默认值应视为自增")
+	assert.Empty(t, categories.Column("id").Default)
+	assert.Equal(t, "character varying(100)", categories.Column("name").Type)
+	assert.Equal(t, "名称", categories.Column("name").Comment)
+	assert.Equal(t, "'{}'", categories.Column("meta").Default, "默认值应去掉类型转换")
+	assert.Equal(t, "timestamp with time zone", categories.Column("created_at").Type)
+	assert.Equal(t, "now()", categories.Column("created_at").Default)
+	assert.Equal(t, []Index{{Name: "categories_name_key", Columns: []string{"name"}, Unique: true}}, categories.Indexes, "表达式索引应被忽略")
+	assert.Equal(t, "SET NULL", categories.ForeignKey("parent_id").OnDelete)
+
+	products := tables[1]
+	assert.Equal(t, "products", products.Name)
+	assert.Equal(t, &Column{Name: "id", Type: "BIGSERIAL", NotNull: true, AutoIncrement: true}, products.Column("id"))
+	assert.Equal(t, "categories", products.ForeignKey("category_id").RefTable)
+	assert.Empty(t, products.ForeignKey("category_id").RefColumns, "未指定列时引用主键")
+	assert.True(t, products.Column("price").NotNull, "CHECK约束之前的NOT NULL丢失")
+	assert.True(t, products.Column("total").Generated)
+	assert.Equal(t, []Index{{Columns: []string{"code"}, Unique: true}}, products.ColumnIndexes("code"))
+	assert.Equal(t, "text[]", products.Column("tags").Type)
+}
+
+// 测试列类型的辅助方法
+func TestColumnType(t *testing.T) {
+	tests := []struct {
+		typ      string
+		name     string
+		unsigned bool
+		length   int
+	}{
+		{"varchar(255)", "varchar", false, 255},
+		{"INT(11) UNSIGNED ZEROFILL", "int", true, 11},
+		{"decimal(10, 2)", "decimal", false, 10},
+		{"double precision", "double precision", false, 0},
+		{"enum('a','b')", "enum", false, 0},
+	}
+	for _, tt := range tests {
+		column := &Column{Type: tt.typ}
+		assert.Equal(t, tt.name, column.TypeName(), "TypeName(%q)", tt.typ)
+		assert.Equal(t, tt.unsigned, column.Unsigned(), "Unsigned(%q)", tt.typ)
+		assert.Equal(t, tt.length, column.Length(), "Length(%q)", tt.typ)
+	}
+}
+
+// 测试解析错误
+func TestParseSQL_Errors(t *testing.T) {
+	_, err := ParseSQL("CREATE TABLE users (id int, name varchar(10)")
+	assert.Error(t, err, "期望在括号未闭合时返回错误，但没有")
+
+	_, err = ParseSQL("CREATE TABLE users (name text DEFAULT 'abc);")
+	assert.Error(t, err, "期望在引号未结束时返回错误，但没有")
+
+	_, err = ParseFile(filepath.Join(t.TempDir(), "missing.sql"))
+	assert.Error(t, err, "期望在文件不存在时返回错误，但没有")
+
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte("CREATE TABLE t (id int);"), 0644))
+	tables, err := ParseFile(path)
+	require.NoError(t, err)
+	assert.Len(t, tables, 1)
+}
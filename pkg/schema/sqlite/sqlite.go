@@ -0,0 +1,283 @@
+// Package sqlite 从SQLite数据库文件读取表结构，导入后注册 sqlite:// 地址
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yggai/gs/pkg/schema"
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	schema.Register("sqlite", Open)
+}
+
+// Introspector 通过sqlite_master和PRAGMA读取表结构
+type Introspector struct {
+	db *sql.DB
+}
+
+// uriEscaper 转义文件路径中在SQLite URI里有特殊含义的字符
+var uriEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+// Open 以只读方式打开SQLite数据库文件
+func Open(path string) (schema.Introspector, error) {
+	// 文件不存在时SQLite会创建空数据库
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("无法打开SQLite数据库: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+uriEscaper.Replace(path)+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("无法打开SQLite数据库: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("无法打开SQLite数据库: %v", err)
+	}
+	return &Introspector{db: db}, nil
+}
+
+// Close 关闭数据库连接
+func (i *Introspector) Close() error {
+	return i.db.Close()
+}
+
+// Tables 返回数据库中的用户表，不含sqlite_开头的内部表
+func (i *Introspector) Tables() ([]*schema.Table, error) {
+	rows, err := i.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取表列表: %v", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("无法读取表列表: %v", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("无法读取表列表: %v", err)
+	}
+
+	var tables []*schema.Table
+	for _, name := range names {
+		table, err := i.table(name)
+		if err != nil {
+			return nil, fmt.Errorf("无法读取表 %s 的结构: %v", name, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// table 读取单个表的列、主键、索引和外键
+func (i *Introspector) table(name string) (*schema.Table, error) {
+	table := &schema.Table{Name: name}
+	if err := i.columns(table); err != nil {
+		return nil, err
+	}
+	if err := i.indexes(table); err != nil {
+		return nil, err
+	}
+	if err := i.foreignKeys(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// quote 为标识符加上双引号
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columns 通过PRAGMA table_xinfo读取列，包括计算列
+func (i *Introspector) columns(table *schema.Table) error {
+	rows, err := i.db.Query("PRAGMA table_xinfo(" + quote(table.Name) + ")")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	primaryKey := map[int]string{}
+	for rows.Next() {
+		var (
+			cid, notNull, pk, hidden int
+			name, typ                string
+			dflt                     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk, &hidden); err != nil {
+			return err
+		}
+		// hidden为1的是虚拟表的隐藏列，2和3是计算列
+		if hidden == 1 {
+			continue
+		}
+		column := &schema.Column{
+			Name:      name,
+			Type:      typ,
+			NotNull:   notNull == 1 || pk > 0,
+			Default:   dflt.String,
+			Generated: hidden == 2 || hidden == 3,
+		}
+		if strings.EqualFold(column.Default, "NULL") {
+			column.Default = ""
+		}
+		if pk > 0 {
+			primaryKey[pk] = name
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for n := 1; n <= len(primaryKey); n++ {
+		table.PrimaryKey = append(table.PrimaryKey, primaryKey[n])
+	}
+	// INTEGER PRIMARY KEY 是rowid的别名，插入时自动分配
+	if len(table.PrimaryKey) == 1 {
+		if column := table.Column(table.PrimaryKey[0]); strings.EqualFold(column.Type, "INTEGER") {
+			column.AutoIncrement = true
+		}
+	}
+	return nil
+}
+
+// indexes 通过PRAGMA index_list和index_info读取索引，主键索引和表达式索引被忽略
+func (i *Introspector) indexes(table *schema.Table) error {
+	rows, err := i.db.Query("PRAGMA index_list(" + quote(table.Name) + ")")
+	if err != nil {
+		return err
+	}
+	var indexes []schema.Index
+	var origins []string
+	for rows.Next() {
+		var (
+			seq, unique, partial int
+			name, origin         string
+		)
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return err
+		}
+		if origin == "pk" {
+			continue
+		}
+		indexes = append(indexes, schema.Index{Name: name, Unique: unique == 1})
+		origins = append(origins, origin)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// index_list按创建顺序倒序返回
+	for n := len(indexes) - 1; n >= 0; n-- {
+		index := indexes[n]
+		columns, err := i.indexColumns(index.Name)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		index.Columns = columns
+		// 列上直接声明的UNIQUE约束使用SQLite自动生成的索引名
+		if origins[n] == "u" && strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+			index.Name = ""
+		}
+		table.Indexes = append(table.Indexes, index)
+	}
+	return nil
+}
+
+// indexColumns 返回索引的列，包含表达式时返回空
+func (i *Introspector) indexColumns(index string) ([]string, error) {
+	rows, err := i.db.Query("PRAGMA index_info(" + quote(index) + ")")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	expression := false
+	for rows.Next() {
+		var (
+			seqno, cid int
+			name       sql.NullString
+		)
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		if !name.Valid {
+			expression = true
+		}
+		columns = append(columns, name.String)
+	}
+	if expression {
+		return nil, rows.Err()
+	}
+	return columns, rows.Err()
+}
+
+// foreignKeys 通过PRAGMA foreign_key_list读取外键，多列外键按id合并
+func (i *Introspector) foreignKeys(table *schema.Table) error {
+	rows, err := i.db.Query("PRAGMA foreign_key_list(" + quote(table.Name) + ")")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	positions := map[int]int{}
+	for rows.Next() {
+		var (
+			id, seq                            int
+			refTable, from, onUpdate, onDelete string
+			to                                 sql.NullString
+			match                              string
+		)
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return err
+		}
+		pos, ok := positions[id]
+		if !ok {
+			pos = len(table.ForeignKeys)
+			positions[id] = pos
+			table.ForeignKeys = append(table.ForeignKeys, schema.ForeignKey{
+				RefTable: refTable,
+				OnDelete: action(onDelete),
+				OnUpdate: action(onUpdate),
+			})
+		}
+		fk := &table.ForeignKeys[pos]
+		fk.Columns = append(fk.Columns, from)
+		// 未指定引用列时引用主键，to为NULL
+		if to.Valid {
+			fk.RefColumns = append(fk.RefColumns, to.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// PRAGMA foreign_key_list按定义顺序倒序返回
+	for l, r := 0, len(table.ForeignKeys)-1; l < r; l, r = l+1, r-1 {
+		table.ForeignKeys[l], table.ForeignKeys[r] = table.ForeignKeys[r], table.ForeignKeys[l]
+	}
+	return nil
+}
+
+// action 将SQLite的NO ACTION转换为空，与未声明时一致
+func action(s string) string {
+	if strings.EqualFold(s, "NO ACTION") {
+		return ""
+	}
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yggai/gs/pkg/schema"
+)
+
+// createTestDatabase 创建测试用SQLite数据库文件
+func createTestDatabase(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err, "无法创建数据库")
+	defer db.Close()
+
+	_, err = db.Exec(`
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email VARCHAR(191) NOT NULL UNIQUE,
+  name TEXT,
+  active BOOLEAN NOT NULL DEFAULT 1,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_users_name ON users (name);
+CREATE INDEX idx_users_lower ON users (lower(email));
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  total REAL NOT NULL DEFAULT 0,
+  doubled REAL GENERATED ALWAYS AS (total * 2) VIRTUAL
+);
+CREATE TABLE order_tags (
+  order_id INTEGER NOT NULL,
+  tag TEXT NOT NULL,
+  PRIMARY KEY (order_id, tag),
+  FOREIGN KEY (order_id) REFERENCES orders
+);`)
+	require.NoError(t, err, "无法创建测试表")
+	return path
+}
+
+// 测试读取SQLite表结构
+func TestInspect(t *testing.T) {
+	tables, err := schema.Inspect("sqlite://" + createTestDatabase(t))
+	require.NoError(t, err, "读取表结构失败")
+	require.Len(t, tables, 3, "应忽略sqlite_sequence等内部表")
+
+	users := tables[0]
+	assert.Equal(t, "users", users.Name)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey)
+	assert.Equal(t, &schema.Column{Name: "id", Type: "INTEGER", NotNull: true, AutoIncrement: true}, users.Column("id"))
+	assert.Equal(t, &schema.Column{Name: "email", Type: "VARCHAR(191)", NotNull: true}, users.Column("email"))
+	assert.False(t, users.Column("name").NotNull)
+	assert.Equal(t, "1", users.Column("active").Default)
+	assert.Equal(t, "CURRENT_TIMESTAMP", users.Column("created_at").Default)
+	assert.Equal(t, []schema.Index{
+		{Columns: []string{"email"}, Unique: true},
+		{Name: "idx_users_name", Columns: []string{"name"}},
+	}, users.Indexes, "索引不正确")
+
+	orders := tables[1]
+	assert.True(t, orders.Column("id").AutoIncrement, "INTEGER PRIMARY KEY应视为自增")
+	assert.True(t, orders.Column("doubled").Generated, "计算列未识别")
+	assert.Equal(t, []schema.ForeignKey{{Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnDelete: "CASCADE"}}, orders.ForeignKeys)
+
+	tags := tables[2]
+	assert.Equal(t, []string{"order_id", "tag"}, tags.PrimaryKey, "复合主键顺序不正确")
+	assert.False(t, tags.Column("order_id").AutoIncrement)
+	assert.Equal(t, []schema.ForeignKey{{Columns: []string{"order_id"}, RefTable: "orders"}}, tags.ForeignKeys, "未指定引用列时应为空")
+}
+
+// 测试打开数据库的错误
+func TestOpen_Errors(t *testing.T) {
+	_, err := schema.Open("sqlite://" + filepath.Join(t.TempDir(), "missing.db"))
+	assert.Error(t, err, "期望在文件不存在时返回错误，但没有")
+
+	_, err = schema.Open("oracle://localhost")
+	assert.ErrorContains(t, err, "sqlite", "错误信息应列出支持的数据库类型")
+
+	_, err = schema.Open("./app.db")
+	assert.Error(t, err, "期望在地址缺少类型时返回错误，但没有")
+}